@@ -0,0 +1,26 @@
+// Package cache contains the interface which is common for the cache
+// variants. See the 'VL' and 'NVL' packages for the caches themselves.
+package cache
+
+// Cache is a set of methods which is common for vl.Cache and nvl.Cache. It
+// allows wrappers and mocks to be written once for both variants.
+type Cache[U comparable, D any] interface {
+	// RecordExists checks whether the specified record exists or not.
+	RecordExists(uid U) (recordExists bool)
+
+	// AddRecord adds a new record or updates an existing one.
+	AddRecord(uid U, data D) (err error)
+
+	// GetRecord reads a record.
+	GetRecord(uid U) (data D, err error)
+
+	// RemoveRecord removes a record if it exists.
+	RemoveRecord(uid U)
+
+	// RemoveExistingRecord removes an existing record, it returns an error
+	// if the record is not found.
+	RemoveExistingRecord(uid U) (err error)
+
+	// Clear removes all records.
+	Clear() (err error)
+}
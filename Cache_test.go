@@ -0,0 +1,111 @@
+package cache_test
+
+import (
+	"testing"
+
+	cache "github.com/vault-thirteen/Cache"
+	nvl "github.com/vault-thirteen/Cache/NVL"
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/arena"
+	"github.com/vault-thirteen/Cache/cachetest"
+	"github.com/vault-thirteen/Cache/cluster"
+	"github.com/vault-thirteen/Cache/disktier"
+	"github.com/vault-thirteen/Cache/fake"
+	"github.com/vault-thirteen/Cache/invalidation"
+	"github.com/vault-thirteen/Cache/remote"
+	"github.com/vault-thirteen/Cache/slab"
+	"github.com/vault-thirteen/Cache/tiered"
+	"github.com/vault-thirteen/Cache/wal"
+)
+
+var (
+	_ cache.Cache[string, string] = (*vl.Cache[string, string])(nil)
+	_ cache.Cache[string, string] = (*nvl.Cache[string, string])(nil)
+	_ cache.Cache[string, string] = (*fake.Cache[string, string])(nil)
+	_ cache.Cache[string, string] = (*disktier.Cache[string, string])(nil)
+	_ cache.Cache[string, string] = (*tiered.Tiered[string, string])(nil)
+	_ cache.Cache[string, string] = (*invalidation.Cache[string, string])(nil)
+	_ cache.Cache[string, []byte] = (*remote.Client)(nil)
+	_ cache.Cache[string, []byte] = (*cluster.Cluster)(nil)
+	_ cache.Cache[string, string] = (*wal.Cache[string, string])(nil)
+	_ cache.Cache[string, []byte] = (*arena.Cache[string])(nil)
+	_ cache.Cache[string, []byte] = (*slab.Cache[string])(nil)
+)
+
+func Test_Conformance(t *testing.T) {
+	t.Run("Fake", func(t *testing.T) {
+		cachetest.Run(t, func(t *testing.T, s cachetest.Settings) cache.Cache[string, string] {
+			return fake.New[string, string]()
+		}, cachetest.Features{})
+	})
+
+	t.Run("DiskTier", func(t *testing.T) {
+		cachetest.Run(t, func(t *testing.T, s cachetest.Settings) cache.Cache[string, string] {
+			c, err := disktier.Open(
+				vl.Config[string, string]{SizeLimit: 2, RecordTtl: s.RecordTtl, Clock: s.Clock},
+				disktier.Settings{Dir: t.TempDir(), VolumeLimit: 100_000, SegmentSize: 1000, Clock: s.Clock},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = c.Close() })
+
+			return c
+		}, cachetest.Features{})
+	})
+
+	t.Run("Tiered", func(t *testing.T) {
+		cachetest.Run(t, func(t *testing.T, s cachetest.Settings) cache.Cache[string, string] {
+			c, err := tiered.New[string, string](
+				vl.NewCache[string, string](2, 0, 60),
+				nvl.NewCache[string, string](s.SizeLimit, 60),
+				tiered.WritePolicyAround,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return c
+		}, cachetest.Features{})
+	})
+
+	t.Run("Invalidation", func(t *testing.T) {
+		cachetest.Run(t, func(t *testing.T, s cachetest.Settings) cache.Cache[string, string] {
+			c, err := invalidation.New[string, string](
+				fake.New[string, string](),
+				invalidation.NewLocalBus(),
+				invalidation.Settings{NodeId: "N1"},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(c.Close)
+
+			return c
+		}, cachetest.Features{})
+	})
+
+	t.Run("Wal", func(t *testing.T) {
+		cachetest.Run(t, func(t *testing.T, s cachetest.Settings) cache.Cache[string, string] {
+			c, err := wal.Open(
+				vl.Config[string, string]{
+					SizeLimit: s.SizeLimit,
+					RecordTtl: s.RecordTtl,
+					Clock:     s.Clock,
+					OnEviction: func(uid string, data string, reason vl.EvictionReason) {
+						if s.OnEviction != nil {
+							s.OnEviction(uid)
+						}
+					},
+				},
+				wal.Settings{Dir: t.TempDir()},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = c.Close() })
+
+			return c
+		}, cachetest.Features{SizeLimit: true, Ttl: true, OnEviction: true})
+	})
+}
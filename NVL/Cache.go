@@ -1,226 +1,185 @@
 package nvl
 
 import (
-	"errors"
-	"fmt"
-	"sync"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/vault-thirteen/Cache/core"
 )
 
-// Cache is cache. Surprisingly, but it is true.
+// Cache is cache. Surprisingly, but it is true. It wraps the cache of the
+// 'core' package without calculation of records' volume, so methods related
+// to the volume are not available.
 type Cache[U UidType, D DataType] struct {
-	top          *Record[U, D]
-	bottom       *Record[U, D]
-	size         int
-	sizeLimit    int
-	recordsByUid map[U]*Record[U, D]
-	recordTtl    uint
-	lock         *sync.RWMutex
+	cache *core.Cache[U, D]
 }
 
-// NewCache creates a new cache.
+// NewCache creates a new cache. It panics if the TTL is zero. Negative
+// limits disable the limits, as they did before the configuration was
+// validated. This constructor is kept for compatibility, New and
+// NewFromConfig constructors return an error instead of panicking.
 func NewCache[U UidType, D DataType](sizeLimit int, recordTtl uint) (cache *Cache[U, D]) {
-	if recordTtl == 0 {
-		panic(ErrTtlIsZero)
+	var err error
+	cache, err = NewFromConfig(Config[U, D]{
+		SizeLimit: max(sizeLimit, 0),
+		RecordTtl: recordTtl,
+	})
+	if err != nil {
+		panic(err.Error())
 	}
 
-	cache = new(Cache[U, D])
-	cache.initialize(sizeLimit, recordTtl)
 	return cache
 }
 
-func (c *Cache[U, D]) initialize(sizeLimit int, recordTtl uint) {
-	c.top = nil
-	c.bottom = nil
-	c.size = 0
-	c.sizeLimit = sizeLimit
-	c.recordsByUid = make(map[U]*Record[U, D])
-	c.recordTtl = recordTtl
-	c.lock = new(sync.RWMutex)
-}
-
-func (c *Cache[U, D]) hasLimitedSize() bool {
-	return c.sizeLimit > 0
-}
+// New creates a new cache configured by the options.
+func New[U UidType, D DataType](opts ...Option[U, D]) (cache *Cache[U, D], err error) {
+	var cfg Config[U, D]
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 
-func (c *Cache[U, D]) isEmpty() bool {
-	return c.size == 0
+	return NewFromConfig(cfg)
 }
 
-func (c *Cache[U, D]) isNotEmpty() bool {
-	return c.size > 0
-}
+// NewFromConfig creates a new cache using the configuration. Volume of
+// records is not calculated, so the volume limit must not be set.
+func NewFromConfig[U UidType, D DataType](cfg Config[U, D]) (cache *Cache[U, D], err error) {
+	cfg.Measurement = nil
 
-func (c *Cache[U, D]) linkNewTopRecord(rec *Record[U, D]) {
-	if c.isNotEmpty() {
-		rec.lowerRecord = c.top
-		c.top.upperRecord = rec
-		c.top = rec
-	} else {
-		c.top = rec
-		c.bottom = rec
+	var c *core.Cache[U, D]
+	c, err = core.NewFromConfig(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	c.size++
-	c.recordsByUid[rec.uid] = rec
+	return &Cache[U, D]{cache: c}, nil
 }
 
-func (c *Cache[U, D]) unlinkBottomRecord() (rec *Record[U, D], err error) {
-	rec = c.bottom
-
-	if c.size > 1 {
-		c.bottom = rec.upperRecord
-		c.bottom.lowerRecord = nil
-	} else if c.size == 1 {
-		c.top = nil
-		c.bottom = nil
-	} else { // size = 0.
-		return nil, errors.New(ErrBottomRecordDoesNotExist)
-	}
+// GetSize returns current size of the cache.
+func (c *Cache[U, D]) GetSize() (size int, sizeLimit int) {
+	return c.cache.GetSize()
+}
 
-	rec.upperRecord = nil
-	rec.lowerRecord = nil
+// GetStats returns a copy of the cache's statistics.
+func (c *Cache[U, D]) GetStats() (stats Stats) {
+	return c.cache.GetStats()
+}
 
-	c.size--
-	delete(c.recordsByUid, rec.uid)
+// GetTtl returns the records' TTL in seconds.
+func (c *Cache[U, D]) GetTtl() (recordTtl uint) {
+	return c.cache.GetTtl()
+}
 
-	return rec, nil
+// PeekTopUids returns UIDs of at most n records starting from the top of the
+// cache without changing the cache.
+func (c *Cache[U, D]) PeekTopUids(n int) (uids []U) {
+	return c.cache.PeekTopUids(n)
 }
 
-// RecordExists checks whether the specified record exists or not. If the
-// record is outdated, it is removed from the cache.
-func (c *Cache[U, D]) RecordExists(uid U) (recordExists bool) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
+// PeekBottomUids returns UIDs of at most n records starting from the bottom
+// of the cache without changing the cache.
+func (c *Cache[U, D]) PeekBottomUids(n int) (uids []U) {
+	return c.cache.PeekBottomUids(n)
+}
 
-	var rec *Record[U, D]
-	rec, recordExists = c.recordsByUid[uid]
-	if !recordExists {
-		return false
-	}
+// WalkRecords calls the function for each alive record from the bottom of the
+// cache to its top without changing the cache.
+func (c *Cache[U, D]) WalkRecords(fn func(uid U, data D, lastAccessTime time.Time) (next bool)) {
+	c.cache.WalkRecords(fn)
+}
 
-	if !rec.isAlive() {
-		rec.unlink()
-		return false
-	}
+// RecordExists checks whether the specified record exists or not.
+func (c *Cache[U, D]) RecordExists(uid U) (recordExists bool) {
+	return c.cache.RecordExists(uid)
+}
 
-	return true
+// PeekRecord reads data of an alive record without touching the record.
+func (c *Cache[U, D]) PeekRecord(uid U) (data D, err error) {
+	return c.cache.PeekRecord(uid)
 }
 
 // AddRecord either adds a new record to the top of the cache or moves an
-// existing record to the top of the cache. If the record already exists, its
-// data and LAT are updated.
+// existing record to the top of the cache.
 func (c *Cache[U, D]) AddRecord(uid U, data D) (err error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	var rec *Record[U, D]
-	var recExists bool
-	rec, recExists = c.recordsByUid[uid]
-	if recExists {
-		// If the UID is already used,
-		// we update data of the record having this UID.
-		rec.moveToTop()
-		rec.update(data)
-	} else {
-		// UID is not found,
-		// we add a new record.
-		rec, err = NewRecord(c, uid, data)
-		if err != nil {
-			return err
-		}
-
-		c.linkNewTopRecord(rec)
-	}
-
-	// Now we need (or do not need) to apply various constraints.
-	if c.hasLimitedSize() {
-		if c.size > c.sizeLimit {
-			n := c.size - c.sizeLimit
-			for i := 1; i <= n; i++ {
-				_, err = c.unlinkBottomRecord()
-				if err != nil {
-					return err
-				}
-			}
-		}
-	}
+	return c.cache.AddRecord(uid, data)
+}
 
-	return nil
+// AddRecordCtx is a variant of the AddRecord method which stops waiting for
+// the cache's lock when the context is done.
+func (c *Cache[U, D]) AddRecordCtx(ctx context.Context, uid U, data D) (err error) {
+	return c.cache.AddRecordCtx(ctx, uid, data)
 }
 
-// GetRecord reads a record from the cache. If the record is outdated, it is
-// removed from the cache and is not returned.
+// GetRecord reads a record from the cache.
 func (c *Cache[U, D]) GetRecord(uid U) (data D, err error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	var rec *Record[U, D]
-	var ok bool
-	rec, ok = c.recordsByUid[uid]
-	if !ok {
-		return data, fmt.Errorf(ErrRecordIsNotFound, uid)
-	}
+	return c.cache.GetRecord(uid)
+}
 
-	if !rec.isAlive() {
-		rec.unlink()
-		return data, fmt.Errorf(ErrRecordIsOutdated, uid)
-	}
+// GetRecordCtx is a variant of the GetRecord method which stops waiting for
+// the cache's lock when the context is done.
+func (c *Cache[U, D]) GetRecordCtx(ctx context.Context, uid U) (data D, err error) {
+	return c.cache.GetRecordCtx(ctx, uid)
+}
 
-	rec.moveToTop()
-	rec.touch()
+// GetOrLoadCtx reads a record from the cache or loads it using the loader
+// when it is absent.
+func (c *Cache[U, D]) GetOrLoadCtx(ctx context.Context, uid U, loader Loader[U, D]) (data D, err error) {
+	return c.cache.GetOrLoadCtx(ctx, uid, loader)
+}
 
-	return rec.data, nil
+// GetRecordAllowStale reads a record from the cache, returning data of an
+// outdated record in its grace period flagged as stale.
+func (c *Cache[U, D]) GetRecordAllowStale(uid U) (data D, freshness Freshness, err error) {
+	return c.cache.GetRecordAllowStale(uid)
 }
 
 // RemoveRecord safely removes a record from the cache.
 func (c *Cache[U, D]) RemoveRecord(uid U) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	var rec *Record[U, D]
-	var recExists bool
-	rec, recExists = c.recordsByUid[uid]
-	if !recExists {
-		return
-	}
-
-	rec.unlink()
-
-	return
+	c.cache.RemoveRecord(uid)
 }
 
 // RemoveExistingRecord removes an existing record from the cache.
 func (c *Cache[U, D]) RemoveExistingRecord(uid U) (err error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	var rec *Record[U, D]
-	var recExists bool
-	rec, recExists = c.recordsByUid[uid]
-	if !recExists {
-		return fmt.Errorf(ErrRecordIsNotFound, uid)
-	}
-
-	rec.unlink()
-
-	return nil
+	return c.cache.RemoveExistingRecord(uid)
 }
 
 // Clear removes all records from the cache.
 func (c *Cache[U, D]) Clear() (err error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	for {
-		if c.isEmpty() {
-			break
-		}
-
-		_, err = c.unlinkBottomRecord()
-		if err != nil {
-			return err
-		}
-	}
+	return c.cache.Clear()
+}
+
+// SetInstrumentation sets the instrumentation which observes operations of
+// the cache. Nil disables the instrumentation.
+func (c *Cache[U, D]) SetInstrumentation(instrumentation Instrumentation[U]) {
+	c.cache.SetInstrumentation(instrumentation)
+}
+
+// SetLogger sets the logger which receives events of the cache. Nil disables
+// logging.
+func (c *Cache[U, D]) SetLogger(logger *slog.Logger, sampling LogSampling) {
+	c.cache.SetLogger(logger, sampling)
+}
+
+// SetSizeLimit changes the size limit at runtime. The number of evicted
+// records is returned.
+func (c *Cache[U, D]) SetSizeLimit(sizeLimit int) (droppedCount int, err error) {
+	return c.cache.SetSizeLimit(sizeLimit)
+}
+
+// SetTtl changes the records' TTL at runtime. The number of removed records
+// is returned.
+func (c *Cache[U, D]) SetTtl(recordTtl uint) (droppedCount int, err error) {
+	return c.cache.SetTtl(recordTtl)
+}
+
+// Flush writes all the records queued in the write-behind mode to the store.
+func (c *Cache[U, D]) Flush(ctx context.Context) (err error) {
+	return c.cache.Flush(ctx)
+}
 
-	return nil
+// Close stops the write-behind flusher and writes all the queued records to
+// the store.
+func (c *Cache[U, D]) Close() (err error) {
+	return c.cache.Close()
 }
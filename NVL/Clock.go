@@ -0,0 +1,8 @@
+package nvl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// Clock is a source of current time.
+type Clock = core.Clock
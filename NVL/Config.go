@@ -0,0 +1,8 @@
+package nvl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// Config contains settings of the cache.
+type Config[U UidType, D DataType] = core.Config[U, D]
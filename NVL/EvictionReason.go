@@ -0,0 +1,13 @@
+package nvl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// EvictionReason is a reason of a record's removal made by the cache itself.
+type EvictionReason = core.EvictionReason
+
+const (
+	EvictionReasonSize    = core.EvictionReasonSize
+	EvictionReasonExpired = core.EvictionReasonExpired
+)
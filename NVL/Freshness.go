@@ -0,0 +1,13 @@
+package nvl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// Freshness tells whether data returned by the cache is fresh or stale.
+type Freshness = core.Freshness
+
+const (
+	FreshnessFresh = core.FreshnessFresh
+	FreshnessStale = core.FreshnessStale
+)
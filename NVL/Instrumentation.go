@@ -0,0 +1,8 @@
+package nvl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// Instrumentation observes operations of the cache.
+type Instrumentation[U UidType] = core.Instrumentation[U]
@@ -0,0 +1,72 @@
+package nvl
+
+import (
+	"log/slog"
+
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// Option is a functional option of the cache's constructor.
+type Option[U UidType, D DataType] = core.Option[U, D]
+
+// WithSizeLimit sets the maximum number of records.
+func WithSizeLimit[U UidType, D DataType](sizeLimit int) Option[U, D] {
+	return core.WithSizeLimit[U, D](sizeLimit)
+}
+
+// WithRecordTtl sets the records' TTL in seconds.
+func WithRecordTtl[U UidType, D DataType](recordTtl uint) Option[U, D] {
+	return core.WithRecordTtl[U, D](recordTtl)
+}
+
+// WithClock sets the source of current time.
+func WithClock[U UidType, D DataType](clock Clock) Option[U, D] {
+	return core.WithClock[U, D](clock)
+}
+
+// WithOnEviction sets the eviction callback.
+func WithOnEviction[U UidType, D DataType](onEviction func(uid U, data D, reason EvictionReason)) Option[U, D] {
+	return core.WithOnEviction[U, D](onEviction)
+}
+
+// WithLogger sets the logger and settings of sampling of log events.
+func WithLogger[U UidType, D DataType](logger *slog.Logger, sampling LogSampling) Option[U, D] {
+	return core.WithLogger[U, D](logger, sampling)
+}
+
+// WithInstrumentation sets the instrumentation.
+func WithInstrumentation[U UidType, D DataType](instrumentation Instrumentation[U]) Option[U, D] {
+	return core.WithInstrumentation[U, D](instrumentation)
+}
+
+// WithStore sets the backing store and the mode of writing to it.
+func WithStore[U UidType, D DataType](store Store[U, D], writeMode WriteMode, writeBehind WriteBehindSettings) Option[U, D] {
+	return core.WithStore[U, D](store, writeMode, writeBehind)
+}
+
+// WithOnStoreError sets the store error callback.
+func WithOnStoreError[U UidType, D DataType](onStoreError func(uid U, err error)) Option[U, D] {
+	return core.WithOnStoreError[U, D](onStoreError)
+}
+
+// WithLoader sets the default loader of records.
+func WithLoader[U UidType, D DataType](loader Loader[U, D]) Option[U, D] {
+	return core.WithLoader[U, D](loader)
+}
+
+// WithRefreshAhead sets the fraction of the TTL after which a record read
+// from the cache is reloaded in background.
+func WithRefreshAhead[U UidType, D DataType](refreshAheadFactor float64) Option[U, D] {
+	return core.WithRefreshAhead[U, D](refreshAheadFactor)
+}
+
+// WithGracePeriod sets the period in seconds after the TTL during which an
+// outdated record may be served as stale.
+func WithGracePeriod[U UidType, D DataType](gracePeriod uint) Option[U, D] {
+	return core.WithGracePeriod[U, D](gracePeriod)
+}
+
+// WithStaleIfError enables serving of stale data when the loader fails.
+func WithStaleIfError[U UidType, D DataType]() Option[U, D] {
+	return core.WithStaleIfError[U, D]()
+}
@@ -0,0 +1,19 @@
+package nvl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// Outcome is an outcome of an observed operation of the cache.
+type Outcome = core.Outcome
+
+const (
+	OutcomeAdded   = core.OutcomeAdded
+	OutcomeUpdated = core.OutcomeUpdated
+	OutcomeHit     = core.OutcomeHit
+	OutcomeMiss    = core.OutcomeMiss
+	OutcomeExpired = core.OutcomeExpired
+	OutcomeStale   = core.OutcomeStale
+	OutcomeRemoved = core.OutcomeRemoved
+	OutcomeFailed  = core.OutcomeFailed
+)
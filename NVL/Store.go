@@ -0,0 +1,8 @@
+package nvl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// Store is a backing store of records.
+type Store[U UidType, D DataType] = core.Store[U, D]
@@ -0,0 +1,13 @@
+package nvl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// WriteMode is a mode of writing records to the store.
+type WriteMode = core.WriteMode
+
+const (
+	WriteModeThrough = core.WriteModeThrough
+	WriteModeBehind  = core.WriteModeBehind
+)
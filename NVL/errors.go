@@ -1,9 +1,22 @@
 package nvl
 
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
 const (
-	ErrBottomRecordDoesNotExist = "bottom record does not exist"
-	ErrUidIsEmpty               = "UID is empty" //TODO
-	ErrRecordIsNotFound         = `record is not found, uid=%v`
-	ErrRecordIsOutdated         = `record is outdated, uid=%v`
-	ErrTtlIsZero                = "zero TTL will totally disable the cache"
+	ErrBottomRecordDoesNotExist    = core.ErrBottomRecordDoesNotExist
+	ErrUidIsEmpty                  = core.ErrUidIsEmpty
+	ErrRecordIsNotFound            = core.ErrRecordIsNotFound
+	ErrRecordIsOutdated            = core.ErrRecordIsOutdated
+	ErrTtlIsZero                   = core.ErrTtlIsZero
+	ErrLoaderIsNotSet              = core.ErrLoaderIsNotSet
+	ErrSizeLimitIsNegative         = core.ErrSizeLimitIsNegative
+	ErrStoreIsNotSet               = core.ErrStoreIsNotSet
+	ErrWriteModeIsUnknown          = core.ErrWriteModeIsUnknown
+	ErrFlushIntervalIsNotPositive  = core.ErrFlushIntervalIsNotPositive
+	ErrBatchSizeIsNegative         = core.ErrBatchSizeIsNegative
+	ErrMaxRetriesIsNegative        = core.ErrMaxRetriesIsNegative
+	ErrRefreshAheadFactorIsInvalid = core.ErrRefreshAheadFactorIsInvalid
+	ErrMeasurementIsNotSet         = core.ErrMeasurementIsNotSet
 )
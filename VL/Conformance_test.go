@@ -0,0 +1,26 @@
+package vl
+
+import (
+	"testing"
+
+	cache "github.com/vault-thirteen/Cache"
+	"github.com/vault-thirteen/Cache/cachetest"
+)
+
+func Test_Conformance(t *testing.T) {
+	factory := func(t *testing.T, s cachetest.Settings) cache.Cache[string, string] {
+		c, err := New(
+			WithSizeLimit[string, string](s.SizeLimit),
+			WithRecordTtl[string, string](s.RecordTtl),
+			WithClock[string, string](s.Clock),
+			WithOnEviction[string, string](func(uid string, data string, reason EvictionReason) { s.OnEviction(uid) }),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return c
+	}
+
+	cachetest.Run(t, factory, cachetest.Features{SizeLimit: true, Ttl: true, OnEviction: true})
+}
@@ -0,0 +1,14 @@
+package vl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// EvictionReason is a reason of a record's removal made by the cache itself.
+type EvictionReason = core.EvictionReason
+
+const (
+	EvictionReasonSize    = core.EvictionReasonSize
+	EvictionReasonVolume  = core.EvictionReasonVolume
+	EvictionReasonExpired = core.EvictionReasonExpired
+)
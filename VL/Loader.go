@@ -0,0 +1,8 @@
+package vl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// Loader loads data of a record which is missing in the cache.
+type Loader[U UidType, D DataType] = core.Loader[U, D]
@@ -0,0 +1,8 @@
+package vl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// LogSampling contains settings of sampling of log events.
+type LogSampling = core.LogSampling
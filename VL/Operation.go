@@ -0,0 +1,15 @@
+package vl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// Operation is an observed operation of the cache.
+type Operation = core.Operation
+
+const (
+	OperationAddRecord    = core.OperationAddRecord
+	OperationGetRecord    = core.OperationGetRecord
+	OperationRemoveRecord = core.OperationRemoveRecord
+	OperationClear        = core.OperationClear
+)
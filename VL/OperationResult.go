@@ -0,0 +1,8 @@
+package vl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// OperationResult is a result of an observed operation.
+type OperationResult = core.OperationResult
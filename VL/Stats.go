@@ -0,0 +1,8 @@
+package vl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// Stats contains statistics of the cache's usage.
+type Stats = core.Stats
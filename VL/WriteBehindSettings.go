@@ -0,0 +1,8 @@
+package vl
+
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
+// WriteBehindSettings contains settings of the write-behind mode.
+type WriteBehindSettings = core.WriteBehindSettings
@@ -1,11 +1,24 @@
 package vl
 
+import (
+	"github.com/vault-thirteen/Cache/core"
+)
+
 const (
-	ErrBottomRecordDoesNotExist = "bottom record does not exist"
-	ErrUidIsEmpty               = "UID is empty" //TODO
-	ErrDataIsEmpty              = "data is empty"
-	ErrRecordIsNotFound         = `record is not found, uid=%v`
-	ErrRecordIsOutdated         = `record is outdated, uid=%v`
-	ErrRecordIsTooBig           = "record is too big"
-	ErrTtlIsZero                = "zero TTL will totally disable the cache"
+	ErrBottomRecordDoesNotExist    = core.ErrBottomRecordDoesNotExist
+	ErrUidIsEmpty                  = core.ErrUidIsEmpty
+	ErrDataIsEmpty                 = core.ErrDataIsEmpty
+	ErrRecordIsNotFound            = core.ErrRecordIsNotFound
+	ErrRecordIsOutdated            = core.ErrRecordIsOutdated
+	ErrRecordIsTooBig              = core.ErrRecordIsTooBig
+	ErrTtlIsZero                   = core.ErrTtlIsZero
+	ErrLoaderIsNotSet              = core.ErrLoaderIsNotSet
+	ErrSizeLimitIsNegative         = core.ErrSizeLimitIsNegative
+	ErrVolumeLimitIsNegative       = core.ErrVolumeLimitIsNegative
+	ErrStoreIsNotSet               = core.ErrStoreIsNotSet
+	ErrWriteModeIsUnknown          = core.ErrWriteModeIsUnknown
+	ErrFlushIntervalIsNotPositive  = core.ErrFlushIntervalIsNotPositive
+	ErrBatchSizeIsNegative         = core.ErrBatchSizeIsNegative
+	ErrMaxRetriesIsNegative        = core.ErrMaxRetriesIsNegative
+	ErrRefreshAheadFactorIsInvalid = core.ErrRefreshAheadFactorIsInvalid
 )
@@ -0,0 +1,20 @@
+package arena
+
+// ArenaStats contains statistics of the arena.
+type ArenaStats struct {
+	// Chunks is the number of allocated chunks.
+	Chunks int
+
+	// ChunkSize is the size of a chunk in bytes.
+	ChunkSize int
+
+	// LiveBytes is the number of bytes of stored UIDs and data.
+	LiveBytes int
+
+	// GarbageBytes is the number of bytes of freed records which are not
+	// reclaimed yet.
+	GarbageBytes int
+
+	// Compactions is the number of compactions of the arena.
+	Compactions uint64
+}
@@ -0,0 +1,518 @@
+// Package arena contains a cache of byte data which is friendly to the garbage
+// collector. Data is copied into large pre-allocated chunks of an arena,
+// records are kept in a slice of structures without pointers and are linked
+// into the LRU list by indices, and the index of UIDs maps hashes of UIDs to
+// indices of records. So the garbage collector sees only a few objects which
+// it does not need to scan, however many records the cache has.
+//
+// The cache is a separate type rather than a mode of the vl.Cache. It has the
+// basic methods of the vl.Cache for byte data, evicts records in the same
+// order and passes the conformance suite of the 'cachetest' package, but its
+// semantics differ:
+//   - data is copied when it is added and when it is read;
+//   - a record which does not fit a single chunk is rejected;
+//   - adding a record whose UID has the same hash as the UID of another
+//     record evicts the other record with the collision reason;
+//   - loaders, stores, a grace period, refresh-ahead and methods accepting a
+//     context are not supported.
+//
+// Records with empty data are rejected, like by the vl.Cache.
+package arena
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/internal/recordindex"
+	"github.com/vault-thirteen/Cache/internal/uidcodec"
+)
+
+// EvictionReasonCollision is used when a record is replaced by a record
+// having another UID with the same hash.
+const EvictionReasonCollision = recordindex.EvictionReasonCollision
+
+// Cache is an LRU cache of byte data stored in an arena. Data is copied when
+// it is added and when it is read, so callers may modify their slices.
+//
+// Freed space of the arena is reclaimed by compaction when there is as much
+// garbage as live data, so the arena uses at most about twice the volume of
+// stored UIDs and data.
+type Cache[U vl.UidType] struct {
+	sizeLimit   int
+	volumeLimit int
+	chunkSize   int
+	recordTtl   uint
+	clock       vl.Clock
+
+	lock *sync.Mutex
+
+	// index maps UIDs to indices of records.
+	index   *recordindex.Index
+	records recordindex.Records[record]
+	top     int32
+	bottom  int32
+	size    int
+	volume  int
+
+	// chunks are chunks of the arena, records are allocated in the current
+	// chunk. Empty chunks are reused.
+	chunks      []chunk
+	current     int
+	freeChunks  []int
+	garbage     int
+	compactions uint64
+
+	stats *recordindex.Stats[U]
+}
+
+// New creates a cache using the configuration.
+func New[U vl.UidType](cfg Config[U]) (c *Cache[U], err error) {
+	err = cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	chunkSize := cfg.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	c = &Cache[U]{
+		sizeLimit:   cfg.SizeLimit,
+		volumeLimit: cfg.VolumeLimit,
+		chunkSize:   chunkSize,
+		recordTtl:   cfg.RecordTtl,
+		clock:       cfg.Clock,
+		lock:        new(sync.Mutex),
+		index:       recordindex.NewIndex(),
+		stats:       recordindex.NewStats(cfg.OnEviction),
+	}
+	c.reset()
+
+	return c, nil
+}
+
+// reset removes all the records and releases the arena.
+func (c *Cache[U]) reset() {
+	c.index.Clear()
+	c.records.Clear()
+	c.top = noRecord
+	c.bottom = noRecord
+	c.size = 0
+	c.volume = 0
+	c.chunks = nil
+	c.current = -1
+	c.freeChunks = nil
+	c.garbage = 0
+}
+
+// now returns the current time of the cache's clock in seconds.
+func (c *Cache[U]) now() int64 {
+	if c.clock == nil {
+		return time.Now().Unix()
+	}
+
+	return c.clock.Now().Unix()
+}
+
+func (c *Cache[U]) isAlive(r *record) bool {
+	return c.now() < r.lastAccessTime+int64(c.recordTtl)
+}
+
+func (c *Cache[U]) uidBytes(i int32) []byte {
+	r := &c.records.Items[i]
+	buf := c.chunks[r.chunk].buf
+	return buf[r.offset : int(r.offset)+int(r.uidLen)]
+}
+
+func (c *Cache[U]) dataBytes(i int32) []byte {
+	r := &c.records.Items[i]
+	buf := c.chunks[r.chunk].buf
+	start := int(r.offset) + int(r.uidLen)
+	return buf[start : start+int(r.dataLen)]
+}
+
+// allocate reserves space for a record of the length in the arena.
+func (c *Cache[U]) allocate(length int) (chunkIdx int, offset int) {
+	for {
+		if c.current >= 0 {
+			ch := &c.chunks[c.current]
+			if ch.free() >= length {
+				offset = ch.used
+				ch.used += length
+				ch.live += length
+				return c.current, offset
+			}
+		}
+
+		if len(c.freeChunks) > 0 {
+			c.current = c.freeChunks[len(c.freeChunks)-1]
+			c.freeChunks = c.freeChunks[:len(c.freeChunks)-1]
+			continue
+		}
+
+		if (c.garbage >= length) && (c.garbage >= c.liveBytes()) {
+			c.compact()
+			continue
+		}
+
+		c.chunks = append(c.chunks, chunk{buf: make([]byte, c.chunkSize)})
+		c.current = len(c.chunks) - 1
+	}
+}
+
+// release frees space of the record in the arena. An empty chunk is reused.
+func (c *Cache[U]) release(r *record) {
+	ch := &c.chunks[r.chunk]
+	ch.live -= r.length()
+	c.garbage += r.length()
+
+	if ch.live == 0 {
+		c.garbage -= ch.used
+		ch.used = 0
+		if int(r.chunk) != c.current {
+			c.freeChunks = append(c.freeChunks, int(r.chunk))
+		}
+	}
+}
+
+func (c *Cache[U]) liveBytes() (n int) {
+	for i := range c.chunks {
+		n += c.chunks[i].live
+	}
+
+	return n
+}
+
+// compact moves records of each chunk to the beginning of the chunk, so that
+// all the garbage is reclaimed. The chunk having the most free space becomes
+// the current one.
+func (c *Cache[U]) compact() {
+	byChunk := make([][]int32, len(c.chunks))
+	for i := c.top; i != noRecord; i = c.records.Items[i].lowerRecord {
+		r := &c.records.Items[i]
+		byChunk[r.chunk] = append(byChunk[r.chunk], i)
+	}
+
+	for chunkIdx, indices := range byChunk {
+		slices.SortFunc(indices, func(a, b int32) int {
+			return int(c.records.Items[a].offset) - int(c.records.Items[b].offset)
+		})
+
+		ch := &c.chunks[chunkIdx]
+		var used int
+		for _, i := range indices {
+			r := &c.records.Items[i]
+			copy(ch.buf[used:], ch.buf[r.offset:int(r.offset)+r.length()])
+			r.offset = int32(used)
+			used += r.length()
+		}
+		ch.used = used
+	}
+
+	c.garbage = 0
+	c.compactions++
+
+	c.current = -1
+	c.freeChunks = c.freeChunks[:0]
+	for i := range c.chunks {
+		if c.chunks[i].used == 0 {
+			c.freeChunks = append(c.freeChunks, i)
+		} else if (c.current < 0) || (c.chunks[i].free() > c.chunks[c.current].free()) {
+			c.current = i
+		}
+	}
+}
+
+// store allocates space for the UID and the data and copies them there.
+func (c *Cache[U]) store(r *record, uid []byte, data []byte) {
+	chunkIdx, offset := c.allocate(len(uid) + len(data))
+	r.chunk = int32(chunkIdx)
+	r.offset = int32(offset)
+	r.uidLen = int32(len(uid))
+	r.dataLen = int32(len(data))
+
+	buf := c.chunks[chunkIdx].buf
+	copy(buf[offset:], uid)
+	copy(buf[offset+len(uid):], data)
+}
+
+func (c *Cache[U]) linkTop(i int32) {
+	r := &c.records.Items[i]
+	r.upperRecord = noRecord
+	r.lowerRecord = c.top
+	if c.top != noRecord {
+		c.records.Items[c.top].upperRecord = i
+	}
+	c.top = i
+	if c.bottom == noRecord {
+		c.bottom = i
+	}
+}
+
+func (c *Cache[U]) unlink(i int32) {
+	r := &c.records.Items[i]
+	if r.upperRecord != noRecord {
+		c.records.Items[r.upperRecord].lowerRecord = r.lowerRecord
+	} else {
+		c.top = r.lowerRecord
+	}
+	if r.lowerRecord != noRecord {
+		c.records.Items[r.lowerRecord].upperRecord = r.upperRecord
+	} else {
+		c.bottom = r.upperRecord
+	}
+	r.upperRecord = noRecord
+	r.lowerRecord = noRecord
+}
+
+func (c *Cache[U]) moveToTop(i int32) {
+	if c.top == i {
+		return
+	}
+
+	c.unlink(i)
+	c.linkTop(i)
+}
+
+// remove removes the record, its space and its index become free.
+func (c *Cache[U]) remove(i int32) {
+	r := &c.records.Items[i]
+	c.unlink(i)
+	c.index.Delete(r.hash)
+	c.release(r)
+	c.size--
+	c.volume -= int(r.dataLen)
+	c.records.Free(i)
+}
+
+// evict removes the record and notifies about it.
+func (c *Cache[U]) evict(i int32, reason vl.EvictionReason) {
+	c.stats.Evict(reason, c.uidBytes(i), c.dataBytes(i), func() { c.remove(i) })
+}
+
+// applyLimits evicts records from the bottom of the cache until the size and
+// volume limits hold.
+func (c *Cache[U]) applyLimits() {
+	for (c.sizeLimit > 0) && (c.size > c.sizeLimit) {
+		c.evict(c.bottom, vl.EvictionReasonSize)
+	}
+
+	for (c.volumeLimit > 0) && (c.volume > c.volumeLimit) {
+		c.evict(c.bottom, vl.EvictionReasonVolume)
+	}
+}
+
+// RecordExists checks whether the specified record exists or not. An
+// outdated record is treated as absent, and it is removed from the cache.
+func (c *Cache[U]) RecordExists(uid U) (recordExists bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	i, ok := c.index.Find(uidcodec.EncodeBinary(uid), c.uidBytes)
+	if !ok {
+		return false
+	}
+
+	if !c.isAlive(&c.records.Items[i]) {
+		c.evict(i, vl.EvictionReasonExpired)
+		return false
+	}
+
+	return true
+}
+
+// AddRecord either adds a new record to the top of the cache or moves an
+// existing record to the top of the cache. If the record already exists, its
+// data and LAT are updated.
+func (c *Cache[U]) AddRecord(uid U, data []byte) (err error) {
+	if len(data) == 0 {
+		return errors.New(ErrDataIsEmpty)
+	}
+
+	uidBuf := uidcodec.EncodeBinary(uid)
+	if (len(uidBuf)+len(data) > c.chunkSize) || ((c.volumeLimit > 0) && (len(data) > c.volumeLimit)) {
+		return errors.New(ErrRecordIsTooBig)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	h := c.index.Hash(uidBuf)
+	i, exists := c.index.Get(h)
+	if exists && !bytes.Equal(c.uidBytes(i), uidBuf) {
+		c.evict(i, EvictionReasonCollision)
+		exists = false
+	}
+
+	if exists {
+		r := &c.records.Items[i]
+		c.volume -= int(r.dataLen)
+		c.release(r)
+
+		// The old bytes are garbage now, compaction must not move them.
+		r.uidLen, r.dataLen = 0, 0
+		c.moveToTop(i)
+	} else {
+		i = c.records.New()
+		c.records.Items[i].hash = h
+		c.index.Set(h, i)
+		c.linkTop(i)
+		c.size++
+	}
+
+	r := &c.records.Items[i]
+	c.store(r, uidBuf, data)
+	r = &c.records.Items[i]
+	r.lastAccessTime = c.now()
+	c.volume += len(data)
+
+	c.applyLimits()
+
+	return nil
+}
+
+// GetRecord reads a copy of data of a record. An outdated record is not
+// returned, and it is removed from the cache.
+func (c *Cache[U]) GetRecord(uid U) (data []byte, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	i, ok := c.index.Find(uidcodec.EncodeBinary(uid), c.uidBytes)
+	if !ok {
+		c.stats.Misses++
+		return nil, fmt.Errorf(ErrRecordIsNotFound, uid)
+	}
+
+	r := &c.records.Items[i]
+	if !c.isAlive(r) {
+		c.evict(i, vl.EvictionReasonExpired)
+		c.stats.Misses++
+		c.stats.ExpiredOnRead++
+		return nil, fmt.Errorf(ErrRecordIsOutdated, uid)
+	}
+
+	c.moveToTop(i)
+	r.lastAccessTime = c.now()
+	c.stats.Hits++
+
+	return bytes.Clone(c.dataBytes(i)), nil
+}
+
+// RemoveRecord removes a record if it exists.
+func (c *Cache[U]) RemoveRecord(uid U) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	i, ok := c.index.Find(uidcodec.EncodeBinary(uid), c.uidBytes)
+	if ok {
+		c.remove(i)
+	}
+}
+
+// RemoveExistingRecord removes an existing record, it returns an error if
+// the record is not found.
+func (c *Cache[U]) RemoveExistingRecord(uid U) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	i, ok := c.index.Find(uidcodec.EncodeBinary(uid), c.uidBytes)
+	if !ok {
+		return fmt.Errorf(ErrRecordIsNotFound, uid)
+	}
+
+	c.remove(i)
+
+	return nil
+}
+
+// Clear removes all records and releases the arena.
+func (c *Cache[U]) Clear() (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.reset()
+
+	return nil
+}
+
+// GetSize returns the number of records and the size limit.
+func (c *Cache[U]) GetSize() (size int, sizeLimit int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.size, c.sizeLimit
+}
+
+// GetVolume returns the total length of records' data and the volume limit.
+func (c *Cache[U]) GetVolume() (usedVolume int, volumeLimit int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.volume, c.volumeLimit
+}
+
+// GetTtl returns the records' TTL in seconds.
+func (c *Cache[U]) GetTtl() (recordTtl uint) {
+	return c.recordTtl
+}
+
+// GetStats returns a copy of the statistics.
+func (c *Cache[U]) GetStats() (stats vl.Stats) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.stats.Copy()
+}
+
+// GetArenaStats returns statistics of the arena.
+func (c *Cache[U]) GetArenaStats() (stats ArenaStats) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return ArenaStats{
+		Chunks:       len(c.chunks),
+		ChunkSize:    c.chunkSize,
+		LiveBytes:    c.liveBytes(),
+		GarbageBytes: c.garbage,
+		Compactions:  c.compactions,
+	}
+}
+
+// PeekTopUids returns UIDs of at most n records from the top of the cache,
+// i.e. of the most recently used records.
+func (c *Cache[U]) PeekTopUids(n int) (uids []U) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	uids = make([]U, 0, max(min(n, c.size), 0))
+	for i := c.top; (i != noRecord) && (len(uids) < n); i = c.records.Items[i].lowerRecord {
+		uids = append(uids, uidcodec.DecodeBinary[U](c.uidBytes(i)))
+	}
+
+	return uids
+}
+
+// PeekBottomUids returns UIDs of at most n records from the bottom of the
+// cache, i.e. of the least recently used records.
+func (c *Cache[U]) PeekBottomUids(n int) (uids []U) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	uids = make([]U, 0, max(min(n, c.size), 0))
+	for i := c.bottom; (i != noRecord) && (len(uids) < n); i = c.records.Items[i].upperRecord {
+		uids = append(uids, uidcodec.DecodeBinary[U](c.uidBytes(i)))
+	}
+
+	return uids
+}
+
+// Close does nothing, it exists for compatibility with the vl.Cache.
+func (c *Cache[U]) Close() (err error) {
+	return nil
+}
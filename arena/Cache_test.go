@@ -0,0 +1,359 @@
+package arena
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	cache "github.com/vault-thirteen/Cache"
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/cachetest"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+// stringCache adapts the cache to string data for the conformance suite.
+type stringCache struct {
+	c *Cache[string]
+}
+
+func (sc stringCache) RecordExists(uid string) (recordExists bool) {
+	return sc.c.RecordExists(uid)
+}
+
+func (sc stringCache) AddRecord(uid string, data string) (err error) {
+	return sc.c.AddRecord(uid, []byte(data))
+}
+
+func (sc stringCache) GetRecord(uid string) (data string, err error) {
+	var buf []byte
+	buf, err = sc.c.GetRecord(uid)
+	return string(buf), err
+}
+
+func (sc stringCache) RemoveRecord(uid string) {
+	sc.c.RemoveRecord(uid)
+}
+
+func (sc stringCache) RemoveExistingRecord(uid string) (err error) {
+	return sc.c.RemoveExistingRecord(uid)
+}
+
+func (sc stringCache) Clear() (err error) {
+	return sc.c.Clear()
+}
+
+func Test_Conformance(t *testing.T) {
+	cachetest.Run(t, func(t *testing.T, s cachetest.Settings) cache.Cache[string, string] {
+		c, err := New(Config[string]{
+			SizeLimit: s.SizeLimit,
+			RecordTtl: s.RecordTtl,
+			ChunkSize: 256,
+			Clock:     s.Clock,
+			OnEviction: func(uid string, data []byte, reason vl.EvictionReason) {
+				if s.OnEviction != nil {
+					s.OnEviction(uid)
+				}
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return stringCache{c}
+	}, cachetest.Features{SizeLimit: true, Ttl: true, OnEviction: true})
+}
+
+func Test_New(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	// Test #1. Bad configurations.
+	_, err = New(Config[string]{})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrTtlIsZero)
+	_, err = New(Config[string]{RecordTtl: 1, SizeLimit: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrSizeLimitIsNegative)
+	_, err = New(Config[string]{RecordTtl: 1, VolumeLimit: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrVolumeLimitIsNegative)
+	_, err = New(Config[string]{RecordTtl: 1, ChunkSize: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrChunkSizeIsNegative)
+
+	// Test #2. Default chunk size.
+	c, err := New(Config[string]{RecordTtl: 1})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(c.GetArenaStats().ChunkSize, DefaultChunkSize)
+	aTest.MustBeEqual(c.GetArenaStats().Chunks, 0)
+	aTest.MustBeEqual(c.GetTtl(), uint(1))
+}
+
+func Test_record(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test #1. Records have no pointers, so the garbage collector does not
+	// scan them.
+	rt := reflect.TypeFor[record]()
+	for i := 0; i < rt.NumField(); i++ {
+		switch rt.Field(i).Type.Kind() {
+		case reflect.Int32, reflect.Int64, reflect.Uint64:
+		default:
+			t.Fatalf("field %s has a kind %v", rt.Field(i).Name, rt.Field(i).Type.Kind())
+		}
+	}
+	aTest.MustBeEqual(reflect.TypeFor[map[uint64]int32]().Elem().Kind(), reflect.Int32)
+}
+
+func Test_Cache(t *testing.T) {
+	aTest := tester.New(t)
+	var data []byte
+	var err error
+
+	c, _ := _test_new(t, Config[string]{})
+
+	// Test #1. Empty data and unknown records.
+	err = c.AddRecord("A", nil)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrDataIsEmpty)
+	_, err = c.GetRecord("A")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrRecordIsNotFound, "A"))
+	err = c.RemoveExistingRecord("A")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrRecordIsNotFound, "A"))
+
+	// Test #2. Data is copied.
+	buf := []byte("abc")
+	aTest.MustBeNoError(c.AddRecord("A", buf))
+	buf[0] = 'x'
+	data, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("abc"))
+	data[0] = 'y'
+	data, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("abc"))
+
+	// Test #3. Order of records, updates.
+	aTest.MustBeNoError(c.AddRecord("B", []byte("b")))
+	aTest.MustBeNoError(c.AddRecord("C", []byte("c")))
+	aTest.MustBeNoError(c.AddRecord("A", []byte("aaaa")))
+	aTest.MustBeEqual(c.PeekTopUids(5), []string{"A", "C", "B"})
+	aTest.MustBeEqual(c.PeekBottomUids(2), []string{"B", "C"})
+	size, _ := c.GetSize()
+	aTest.MustBeEqual(size, 3)
+	volume, _ := c.GetVolume()
+	aTest.MustBeEqual(volume, 6)
+	_test_check_accounting(t, c)
+
+	// Test #4. Removal.
+	c.RemoveRecord("C")
+	aTest.MustBeNoError(c.RemoveExistingRecord("B"))
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+	aTest.MustBeEqual(c.PeekTopUids(5), []string{"A"})
+	_test_check_accounting(t, c)
+
+	// Test #5. Clearing releases the arena.
+	aTest.MustBeNoError(c.Clear())
+	aTest.MustBeEqual(c.RecordExists("A"), false)
+	aTest.MustBeEqual(c.GetArenaStats().Chunks, 0)
+	aTest.MustBeNoError(c.AddRecord("A", []byte("a")))
+	aTest.MustBeEqual(c.RecordExists("A"), true)
+	aTest.MustBeNoError(c.Close())
+}
+
+func Test_Cache_limits(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	var evicted []string
+	onEviction := func(uid string, data []byte, reason vl.EvictionReason) {
+		evicted = append(evicted, uid+":"+string(data)+":"+string(reason))
+	}
+
+	// Test #1. Size limit.
+	c, _ := _test_new(t, Config[string]{SizeLimit: 2, OnEviction: onEviction})
+	aTest.MustBeNoError(c.AddRecord("A", []byte("a")))
+	aTest.MustBeNoError(c.AddRecord("B", []byte("b")))
+	aTest.MustBeNoError(c.AddRecord("C", []byte("c")))
+	aTest.MustBeEqual(evicted, []string{"A:a:size"})
+	aTest.MustBeEqual(c.GetStats().Evictions[vl.EvictionReasonSize], uint64(1))
+	_test_check_accounting(t, c)
+
+	// Test #2. Volume limit.
+	evicted = nil
+	c, _ = _test_new(t, Config[string]{VolumeLimit: 5, OnEviction: onEviction})
+	aTest.MustBeNoError(c.AddRecord("A", []byte("aa")))
+	aTest.MustBeNoError(c.AddRecord("B", []byte("bb")))
+	aTest.MustBeNoError(c.AddRecord("C", []byte("cc")))
+	aTest.MustBeEqual(evicted, []string{"A:aa:volume"})
+	volume, volumeLimit := c.GetVolume()
+	aTest.MustBeEqual(volume, 4)
+	aTest.MustBeEqual(volumeLimit, 5)
+	_test_check_accounting(t, c)
+
+	// Test #3. Records which are too big.
+	err = c.AddRecord("D", []byte("dddddd"))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrRecordIsTooBig)
+	c, _ = _test_new(t, Config[string]{ChunkSize: 8})
+	err = c.AddRecord("D", []byte("dddddddd"))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrRecordIsTooBig)
+	aTest.MustBeNoError(c.AddRecord("D", []byte("ddddddd")))
+}
+
+func Test_Cache_ttl(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	var evicted []string
+	c, clock := _test_new(t, Config[string]{
+		OnEviction: func(uid string, data []byte, reason vl.EvictionReason) {
+			evicted = append(evicted, uid+":"+string(reason))
+		},
+	})
+
+	// Test #1. Reading prolongs the life of a record.
+	aTest.MustBeNoError(c.AddRecord("A", []byte("a")))
+	aTest.MustBeNoError(c.AddRecord("B", []byte("b")))
+	clock.Add(time.Second * 40)
+	_, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	clock.Add(time.Second * 30)
+
+	// Test #2. Outdated records are removed on access.
+	_, err = c.GetRecord("B")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrRecordIsOutdated, "B"))
+	aTest.MustBeEqual(c.RecordExists("A"), true)
+	clock.Add(time.Second * 60)
+	aTest.MustBeEqual(c.RecordExists("A"), false)
+	aTest.MustBeEqual(evicted, []string{"B:expired", "A:expired"})
+
+	stats := c.GetStats()
+	aTest.MustBeEqual(stats.Hits, uint64(1))
+	aTest.MustBeEqual(stats.Misses, uint64(1))
+	aTest.MustBeEqual(stats.ExpiredOnRead, uint64(1))
+	aTest.MustBeEqual(stats.Evictions[vl.EvictionReasonExpired], uint64(2))
+	_test_check_accounting(t, c)
+}
+
+func Test_Cache_collision(t *testing.T) {
+	aTest := tester.New(t)
+
+	var evicted []string
+	c, _ := _test_new(t, Config[string]{
+		OnEviction: func(uid string, data []byte, reason vl.EvictionReason) {
+			evicted = append(evicted, uid+":"+string(reason))
+		},
+	})
+
+	// Test #1. A record having the same hash of the UID is replaced. The
+	// collision is simulated by moving the record to the hash of another
+	// UID.
+	aTest.MustBeNoError(c.AddRecord("A", []byte("a")))
+	i, _ := c.index.Get(c.index.Hash([]byte("A")))
+	c.index.Delete(c.records.Items[i].hash)
+	c.records.Items[i].hash = c.index.Hash([]byte("B"))
+	c.index.Set(c.records.Items[i].hash, i)
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+
+	aTest.MustBeNoError(c.AddRecord("B", []byte("b")))
+	aTest.MustBeEqual(evicted, []string{"A:" + string(EvictionReasonCollision)})
+	aTest.MustBeEqual(c.PeekTopUids(5), []string{"B"})
+	_test_check_accounting(t, c)
+}
+
+func Test_Cache_arena(t *testing.T) {
+	aTest := tester.New(t)
+	var data []byte
+	var err error
+
+	c, _ := _test_new(t, Config[string]{ChunkSize: 64})
+
+	// Test #1. Records are allocated in chunks.
+	for i := 0; i < 8; i++ {
+		aTest.MustBeNoError(c.AddRecord(fmt.Sprint(i), []byte(strings.Repeat("x", 15))))
+	}
+	stats := c.GetArenaStats()
+	aTest.MustBeEqual(stats.Chunks, 2)
+	aTest.MustBeEqual(stats.LiveBytes, 128)
+	aTest.MustBeEqual(stats.GarbageBytes, 0)
+
+	// Test #2. Garbage is compacted instead of allocating a new chunk.
+	for i := 0; i < 8; i += 2 {
+		c.RemoveRecord(fmt.Sprint(i))
+	}
+	stats = c.GetArenaStats()
+	aTest.MustBeEqual(stats.LiveBytes, 64)
+	aTest.MustBeEqual(stats.GarbageBytes, 64)
+	aTest.MustBeNoError(c.AddRecord("8", []byte(strings.Repeat("y", 15))))
+	stats = c.GetArenaStats()
+	aTest.MustBeEqual(stats.Chunks, 2)
+	aTest.MustBeEqual(stats.LiveBytes, 80)
+	aTest.MustBeEqual(stats.GarbageBytes, 0)
+	aTest.MustBeEqual(stats.Compactions, uint64(1))
+	for i := 1; i < 8; i += 2 {
+		data, err = c.GetRecord(fmt.Sprint(i))
+		aTest.MustBeNoError(err)
+		aTest.MustBeEqual(data, []byte(strings.Repeat("x", 15)))
+	}
+	_test_check_accounting(t, c)
+
+	// Test #3. Updates of records of various lengths.
+	for n := 0; n < 200; n++ {
+		uid := fmt.Sprint(n % 9)
+		aTest.MustBeNoError(c.AddRecord(uid, []byte(strings.Repeat(uid, 1+n%15))))
+		_test_check_accounting(t, c)
+	}
+	for n := 191; n < 200; n++ {
+		uid := fmt.Sprint(n % 9)
+		data, err = c.GetRecord(uid)
+		aTest.MustBeNoError(err)
+		aTest.MustBeEqual(data, []byte(strings.Repeat(uid, 1+n%15)))
+	}
+	stats = c.GetArenaStats()
+	aTest.MustBeEqual(stats.GarbageBytes <= stats.LiveBytes+64, true)
+
+	// Test #4. Empty chunks are reused.
+	for i := 0; i < 9; i++ {
+		c.RemoveRecord(fmt.Sprint(i))
+	}
+	stats = c.GetArenaStats()
+	aTest.MustBeEqual(stats.LiveBytes, 0)
+	aTest.MustBeEqual(stats.GarbageBytes, 0)
+	chunks := stats.Chunks
+	for i := 0; i < 8; i++ {
+		aTest.MustBeNoError(c.AddRecord(fmt.Sprint(i), []byte(strings.Repeat("z", 15))))
+	}
+	aTest.MustBeEqual(c.GetArenaStats().Chunks, chunks)
+	_test_check_accounting(t, c)
+}
+
+func Test_Cache_integerUids(t *testing.T) {
+	aTest := tester.New(t)
+	var data []byte
+	var err error
+
+	c, err := New(Config[int]{RecordTtl: 60, ChunkSize: 64})
+	aTest.MustBeNoError(err)
+
+	// Test #1. Integer UIDs, including negative ones.
+	aTest.MustBeNoError(c.AddRecord(-1, []byte("minus")))
+	aTest.MustBeNoError(c.AddRecord(1, []byte("plus")))
+	data, err = c.GetRecord(-1)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("minus"))
+	aTest.MustBeEqual(c.PeekTopUids(2), []int{-1, 1})
+	aTest.MustBeEqual(c.RecordExists(2), false)
+
+	// Test #2. Unsigned UIDs.
+	cu, err := New(Config[uint]{RecordTtl: 60})
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(cu.AddRecord(^uint(0), []byte("max")))
+	aTest.MustBeEqual(cu.PeekBottomUids(1), []uint{^uint(0)})
+}
@@ -0,0 +1,18 @@
+package arena
+
+// chunk is a large pre-allocated slice of bytes of the arena. Records are
+// allocated at the end of its used part, freed records become garbage until
+// the chunk is compacted or all its records are freed.
+type chunk struct {
+	buf []byte
+
+	// used is the length of the used part of the chunk.
+	used int
+
+	// live is the number of bytes of records stored in the chunk.
+	live int
+}
+
+func (ch *chunk) free() int {
+	return len(ch.buf) - ch.used
+}
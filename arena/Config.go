@@ -0,0 +1,57 @@
+package arena
+
+import (
+	"errors"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+// DefaultChunkSize is the default size of a chunk of the arena in bytes.
+const DefaultChunkSize = 4 * 1024 * 1024
+
+// Config contains settings of the cache.
+type Config[U vl.UidType] struct {
+	// SizeLimit is a maximum number of records, zero disables the limit.
+	SizeLimit int
+
+	// VolumeLimit is a maximum total length of records' data in bytes, zero
+	// disables the limit.
+	VolumeLimit int
+
+	// ChunkSize is a size of a chunk of the arena in bytes. A record, i.e.
+	// its UID and its data, must fit a single chunk. Zero selects the
+	// default size.
+	ChunkSize int
+
+	// RecordTtl is the records' TTL in seconds, it must not be zero.
+	RecordTtl uint
+
+	// Clock is an optional source of current time.
+	Clock vl.Clock
+
+	// OnEviction is an optional callback which is called when a record is
+	// evicted to fit the limits, removed as outdated or replaced by a record
+	// having the same hash of the UID. It receives a copy of the data. It is
+	// called while the cache is locked, so it must not use the cache.
+	OnEviction func(uid U, data []byte, reason vl.EvictionReason)
+}
+
+func (cfg Config[U]) validate() (err error) {
+	if cfg.SizeLimit < 0 {
+		return errors.New(ErrSizeLimitIsNegative)
+	}
+
+	if cfg.VolumeLimit < 0 {
+		return errors.New(ErrVolumeLimitIsNegative)
+	}
+
+	if cfg.ChunkSize < 0 {
+		return errors.New(ErrChunkSizeIsNegative)
+	}
+
+	if cfg.RecordTtl == 0 {
+		return errors.New(ErrTtlIsZero)
+	}
+
+	return nil
+}
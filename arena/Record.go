@@ -0,0 +1,28 @@
+package arena
+
+import (
+	"github.com/vault-thirteen/Cache/internal/recordindex"
+)
+
+// noRecord is an index meaning the absence of a record.
+const noRecord = recordindex.NoRecord
+
+// record is a record of the cache. It contains no pointers, so the garbage
+// collector does not scan records. The UID and the data of the record are
+// stored one after another in a chunk of the arena, records are linked into
+// the LRU list by indices.
+type record struct {
+	hash           uint64
+	chunk          int32
+	offset         int32
+	uidLen         int32
+	dataLen        int32
+	lastAccessTime int64
+	upperRecord    int32
+	lowerRecord    int32
+}
+
+// length returns the number of bytes of the record in its chunk.
+func (r *record) length() int {
+	return int(r.uidLen) + int(r.dataLen)
+}
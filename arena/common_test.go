@@ -0,0 +1,59 @@
+package arena
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/Cache/cachetest"
+)
+
+// _test_new creates a cache of string UIDs with the TTL of 60 seconds and
+// small chunks.
+func _test_new(t *testing.T, cfg Config[string]) (c *Cache[string], clock *cachetest.Clock) {
+	clock = cachetest.NewClock(time.Unix(1_700_000_000, 0))
+	cfg.Clock = clock
+	if cfg.RecordTtl == 0 {
+		cfg.RecordTtl = 60
+	}
+	if cfg.ChunkSize == 0 {
+		cfg.ChunkSize = 64
+	}
+
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c, clock
+}
+
+// _test_check_accounting checks that counters of the arena match its
+// records.
+func _test_check_accounting(t *testing.T, c *Cache[string]) {
+	t.Helper()
+
+	var size, volume int
+	live := make([]int, len(c.chunks))
+	for i := c.top; i != noRecord; i = c.records.Items[i].lowerRecord {
+		r := &c.records.Items[i]
+		size++
+		volume += int(r.dataLen)
+		live[r.chunk] += r.length()
+		if int(r.offset)+r.length() > c.chunks[r.chunk].used {
+			t.Fatalf("record %d is outside of the used part of its chunk", i)
+		}
+	}
+
+	var garbage int
+	for i := range c.chunks {
+		if c.chunks[i].live != live[i] {
+			t.Fatalf("chunk %d: live is %d, expected %d", i, c.chunks[i].live, live[i])
+		}
+		garbage += c.chunks[i].used - live[i]
+	}
+
+	if (size != c.size) || (volume != c.volume) || (c.index.Len() != size) || (garbage != c.garbage) {
+		t.Fatalf("size %d/%d, volume %d/%d, index %d, garbage %d/%d",
+			c.size, size, c.volume, volume, c.index.Len(), c.garbage, garbage)
+	}
+}
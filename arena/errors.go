@@ -0,0 +1,14 @@
+package arena
+
+import vl "github.com/vault-thirteen/Cache/VL"
+
+const (
+	ErrDataIsEmpty           = vl.ErrDataIsEmpty
+	ErrRecordIsNotFound      = vl.ErrRecordIsNotFound
+	ErrRecordIsOutdated      = vl.ErrRecordIsOutdated
+	ErrRecordIsTooBig        = vl.ErrRecordIsTooBig
+	ErrTtlIsZero             = vl.ErrTtlIsZero
+	ErrSizeLimitIsNegative   = vl.ErrSizeLimitIsNegative
+	ErrVolumeLimitIsNegative = vl.ErrVolumeLimitIsNegative
+	ErrChunkSizeIsNegative   = "chunk size is negative"
+)
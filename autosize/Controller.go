@@ -0,0 +1,219 @@
+package autosize
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Controller watches memory usage of the process and dynamically lowers or
+// raises effective volume limits of registered caches. When the used memory
+// approaches the memory limit, volume limits are lowered, so that records are
+// evicted from the bottom of the caches. When memory pressure is gone, volume
+// limits are gradually raised back. Without a memory limit the controller
+// keeps the maximum volume limits.
+type Controller struct {
+	settings Settings
+	targets  map[string]*target
+	lock     *sync.Mutex
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// Adjustment is a result of a single adjustment.
+type Adjustment struct {
+	Memory MemoryStats
+
+	// VolumeLimits are new volume limits of caches by name.
+	VolumeLimits map[string]int
+
+	// DroppedCount is a number of records evicted from all the caches.
+	DroppedCount int
+
+	// Errors are errors returned by caches by name.
+	Errors map[string]error
+}
+
+// NewController creates a new controller.
+func NewController(settings Settings) (c *Controller, err error) {
+	err = settings.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Controller{
+		settings: settings,
+		targets:  make(map[string]*target),
+		lock:     new(sync.Mutex),
+	}
+
+	return c, nil
+}
+
+// Register registers a cache having the specified name. The effective volume
+// limit of the cache is kept within the range and is initially set to the
+// maximum.
+func (c *Controller) Register(name string, cache Target, minVolumeLimit int, maxVolumeLimit int) (err error) {
+	if len(name) == 0 {
+		return errors.New(ErrNameIsEmpty)
+	}
+	if cache == nil {
+		return errors.New(ErrTargetIsNil)
+	}
+	if (minVolumeLimit <= 0) || (maxVolumeLimit < minVolumeLimit) {
+		return errors.New(ErrVolumeLimitsAreBad)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, isUsed := c.targets[name]
+	if isUsed {
+		return fmt.Errorf(ErrNameIsUsed, name)
+	}
+
+	_, err = cache.SetVolumeLimit(maxVolumeLimit)
+	if err != nil {
+		return err
+	}
+
+	c.targets[name] = &target{
+		cache:          cache,
+		minVolumeLimit: minVolumeLimit,
+		maxVolumeLimit: maxVolumeLimit,
+		volumeLimit:    maxVolumeLimit,
+	}
+
+	return nil
+}
+
+// Unregister stops controlling a cache having the specified name. The volume
+// limit of the cache is left as is.
+func (c *Controller) Unregister(name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.targets, name)
+}
+
+// Adjust reads memory usage and adjusts volume limits of the registered
+// caches once.
+func (c *Controller) Adjust() (a Adjustment) {
+	a.Memory = c.settings.MemoryReader()
+	if c.settings.MemoryLimit > 0 {
+		a.Memory.LimitBytes = c.settings.MemoryLimit
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var factor = 1.0
+	if a.Memory.LimitBytes > 0 {
+		usage := float64(a.Memory.UsedBytes) / float64(a.Memory.LimitBytes)
+		if usage >= c.settings.HighWatermark {
+			factor = c.settings.ShrinkFactor
+		} else if usage < c.settings.LowWatermark {
+			factor = c.settings.GrowFactor
+		}
+	} else {
+		factor = math.Inf(1)
+	}
+
+	a.VolumeLimits = make(map[string]int, len(c.targets))
+	for name, t := range c.targets {
+		volumeLimit := t.nextVolumeLimit(factor)
+		if volumeLimit != t.volumeLimit {
+			droppedCount, err := t.cache.SetVolumeLimit(volumeLimit)
+			a.DroppedCount += droppedCount
+			if err != nil {
+				if a.Errors == nil {
+					a.Errors = make(map[string]error)
+				}
+				a.Errors[name] = err
+			} else {
+				t.volumeLimit = volumeLimit
+			}
+		}
+		a.VolumeLimits[name] = t.volumeLimit
+	}
+
+	return a
+}
+
+// nextVolumeLimit returns the volume limit multiplied by the factor and
+// clamped to the range of the target.
+func (t *target) nextVolumeLimit(factor float64) (volumeLimit int) {
+	if factor == 1 {
+		return t.volumeLimit
+	}
+	if math.IsInf(factor, 1) {
+		return t.maxVolumeLimit
+	}
+
+	var next float64
+	if factor > 1 {
+		next = math.Ceil(float64(t.volumeLimit) * factor)
+	} else {
+		next = math.Floor(float64(t.volumeLimit) * factor)
+	}
+
+	if next > float64(t.maxVolumeLimit) {
+		return t.maxVolumeLimit
+	}
+	if next < float64(t.minVolumeLimit) {
+		return t.minVolumeLimit
+	}
+
+	return int(next)
+}
+
+// Start starts periodic adjustments in a separate goroutine.
+func (c *Controller) Start() (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.stop != nil {
+		return errors.New(ErrControllerIsStarted)
+	}
+
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.run(c.stop, c.done)
+
+	return nil
+}
+
+// Stop stops periodic adjustments and waits for the goroutine to finish.
+func (c *Controller) Stop() (err error) {
+	c.lock.Lock()
+	stop, done := c.stop, c.done
+	c.stop, c.done = nil, nil
+	c.lock.Unlock()
+
+	if stop == nil {
+		return errors.New(ErrControllerIsStopped)
+	}
+
+	close(stop)
+	<-done
+
+	return nil
+}
+
+func (c *Controller) run(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(c.settings.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.Adjust()
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package autosize
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+// _test_memory is a memory reader which reports the stored values.
+type _test_memory struct {
+	used  atomic.Uint64
+	limit atomic.Uint64
+}
+
+func (tm *_test_memory) read() MemoryStats {
+	return MemoryStats{UsedBytes: tm.used.Load(), LimitBytes: tm.limit.Load()}
+}
+
+func _test_prepare_controller(aTest *tester.Test, tm *_test_memory) (c *Controller) {
+	s := DefaultSettings()
+	s.Interval = time.Millisecond * 10
+	s.ShrinkFactor = 0.5
+	s.GrowFactor = 2
+	s.MemoryReader = tm.read
+	c, err := NewController(s)
+	aTest.MustBeNoError(err)
+	return c
+}
+
+func _test_prepare_cache(aTest *tester.Test) (cache *vl.Cache[int, string]) {
+	cache = vl.NewCache[int, string](0, 0, 60)
+	for i := 1; i <= 10; i++ {
+		err := cache.AddRecord(i, "0123456789")
+		aTest.MustBeNoError(err)
+	}
+	return cache
+}
+
+// _test_failing_target is a target which fails to change its volume limit.
+type _test_failing_target struct{}
+
+func (_test_failing_target) SetVolumeLimit(volumeLimit int) (int, error) {
+	if volumeLimit < 100 {
+		return 0, errors.New("failure")
+	}
+	return 0, nil
+}
+
+func Test_NewController(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	// Test #1. Bad settings.
+	_, err = NewController(Settings{})
+	aTest.MustBeAnError(err)
+
+	// Test #2. OK.
+	_, err = NewController(DefaultSettings())
+	aTest.MustBeNoError(err)
+}
+
+func Test_Register(t *testing.T) {
+	aTest := tester.New(t)
+	var tm = new(_test_memory)
+	var c = _test_prepare_controller(aTest, tm)
+	var cache = _test_prepare_cache(aTest)
+	var err error
+
+	// Test #1. Bad arguments.
+	aTest.MustBeEqual(c.Register("", cache, 1, 1).Error(), ErrNameIsEmpty)
+	aTest.MustBeEqual(c.Register("a", nil, 1, 1).Error(), ErrTargetIsNil)
+	aTest.MustBeEqual(c.Register("a", cache, 0, 1).Error(), ErrVolumeLimitsAreBad)
+	aTest.MustBeEqual(c.Register("a", cache, 2, 1).Error(), ErrVolumeLimitsAreBad)
+
+	// Test #2. The maximum limit is applied.
+	err = c.Register("a", cache, 10, 50)
+	aTest.MustBeNoError(err)
+	usedVolume, volumeLimit := cache.GetVolume()
+	aTest.MustBeEqual(usedVolume, 50)
+	aTest.MustBeEqual(volumeLimit, 50)
+
+	// Test #3. Name is used.
+	err = c.Register("a", cache, 10, 50)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrNameIsUsed, "a"))
+
+	// Test #4. Unregistered cache is not controlled.
+	c.Unregister("a")
+	tm.limit.Store(100)
+	tm.used.Store(100)
+	a := c.Adjust()
+	aTest.MustBeEqual(len(a.VolumeLimits), 0)
+}
+
+func Test_Adjust(t *testing.T) {
+	aTest := tester.New(t)
+	var tm = new(_test_memory)
+	var c = _test_prepare_controller(aTest, tm)
+	var cache = _test_prepare_cache(aTest)
+	var a Adjustment
+	var err error
+
+	err = c.Register("a", cache, 20, 100)
+	aTest.MustBeNoError(err)
+
+	// Test #1. No memory limit.
+	tm.used.Store(1000)
+	a = c.Adjust()
+	aTest.MustBeEqual(a.VolumeLimits, map[string]int{"a": 100})
+	aTest.MustBeEqual(a.DroppedCount, 0)
+
+	// Test #2. Memory pressure.
+	tm.limit.Store(1000)
+	tm.used.Store(950)
+	a = c.Adjust()
+	aTest.MustBeEqual(a.VolumeLimits, map[string]int{"a": 50})
+	aTest.MustBeEqual(a.DroppedCount, 5)
+	aTest.MustBeEqual(cache.PeekTopUids(10), []int{10, 9, 8, 7, 6})
+
+	// Test #3. The minimum limit holds.
+	a = c.Adjust()
+	aTest.MustBeEqual(a.VolumeLimits, map[string]int{"a": 25})
+	a = c.Adjust()
+	aTest.MustBeEqual(a.VolumeLimits, map[string]int{"a": 20})
+	aTest.MustBeEqual(cache.PeekTopUids(10), []int{10, 9})
+
+	// Test #4. Normal usage keeps the limit.
+	tm.used.Store(800)
+	a = c.Adjust()
+	aTest.MustBeEqual(a.VolumeLimits, map[string]int{"a": 20})
+
+	// Test #5. Memory pressure is gone, the maximum limit holds.
+	tm.used.Store(100)
+	a = c.Adjust()
+	aTest.MustBeEqual(a.VolumeLimits, map[string]int{"a": 40})
+	a = c.Adjust()
+	a = c.Adjust()
+	aTest.MustBeEqual(a.VolumeLimits, map[string]int{"a": 100})
+	_, volumeLimit := cache.GetVolume()
+	aTest.MustBeEqual(volumeLimit, 100)
+
+	// Test #6. Memory limit override.
+	c.settings.MemoryLimit = 100
+	a = c.Adjust()
+	aTest.MustBeEqual(a.Memory.LimitBytes, uint64(100))
+	aTest.MustBeEqual(a.VolumeLimits, map[string]int{"a": 50})
+
+	// Test #7. Failing cache keeps its limit.
+	c.Unregister("a")
+	err = c.Register("f", _test_failing_target{}, 10, 100)
+	aTest.MustBeNoError(err)
+	a = c.Adjust()
+	aTest.MustBeEqual(a.VolumeLimits, map[string]int{"f": 100})
+	aTest.MustBeEqual(a.Errors["f"].Error(), "failure")
+}
+
+func Test_StartStop(t *testing.T) {
+	aTest := tester.New(t)
+	var tm = new(_test_memory)
+	var c = _test_prepare_controller(aTest, tm)
+	var cache = _test_prepare_cache(aTest)
+	var err error
+
+	err = c.Register("a", cache, 20, 100)
+	aTest.MustBeNoError(err)
+
+	// Test #1. Controller is not started.
+	err = c.Stop()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrControllerIsStopped)
+
+	// Test #2. Periodic adjustments.
+	tm.limit.Store(1000)
+	tm.used.Store(1000)
+	err = c.Start()
+	aTest.MustBeNoError(err)
+	err = c.Start()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrControllerIsStarted)
+	time.Sleep(time.Millisecond * 100)
+	err = c.Stop()
+	aTest.MustBeNoError(err)
+	_, volumeLimit := cache.GetVolume()
+	aTest.MustBeEqual(volumeLimit, 20)
+}
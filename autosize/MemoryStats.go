@@ -0,0 +1,60 @@
+package autosize
+
+import (
+	"math"
+	"runtime/metrics"
+)
+
+// MemoryStats contains memory usage of the process.
+type MemoryStats struct {
+	// UsedBytes is the amount of memory mapped by the Go runtime and not
+	// released to the OS. This is the value which is compared with the soft
+	// memory limit by the Go runtime.
+	UsedBytes uint64
+
+	// LimitBytes is the soft memory limit. Zero means that the memory is not
+	// limited.
+	LimitBytes uint64
+}
+
+// MemoryReader reads memory usage of the process.
+type MemoryReader func() (ms MemoryStats)
+
+const (
+	metricTotalMemory    = "/memory/classes/total:bytes"
+	metricReleasedMemory = "/memory/classes/heap/released:bytes"
+	metricMemoryLimit    = "/gc/gomemlimit:bytes"
+)
+
+// ReadRuntimeMemory reads memory usage of the process from the
+// 'runtime/metrics' package. The limit is the soft memory limit set by the
+// GOMEMLIMIT environment variable or by the debug.SetMemoryLimit function.
+func ReadRuntimeMemory() (ms MemoryStats) {
+	samples := []metrics.Sample{
+		{Name: metricTotalMemory},
+		{Name: metricReleasedMemory},
+		{Name: metricMemoryLimit},
+	}
+	metrics.Read(samples)
+
+	total := readUint64(samples[0])
+	released := readUint64(samples[1])
+	if total > released {
+		ms.UsedBytes = total - released
+	}
+
+	ms.LimitBytes = readUint64(samples[2])
+	if ms.LimitBytes == math.MaxInt64 {
+		ms.LimitBytes = 0
+	}
+
+	return ms
+}
+
+func readUint64(s metrics.Sample) uint64 {
+	if s.Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+
+	return s.Value.Uint64()
+}
@@ -0,0 +1,26 @@
+package autosize
+
+import (
+	"math"
+	"runtime/debug"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_ReadRuntimeMemory(t *testing.T) {
+	aTest := tester.New(t)
+	var ms MemoryStats
+
+	// Test #1. No limit.
+	oldLimit := debug.SetMemoryLimit(math.MaxInt64)
+	defer debug.SetMemoryLimit(oldLimit)
+	ms = ReadRuntimeMemory()
+	aTest.MustBeEqual(ms.UsedBytes > 0, true)
+	aTest.MustBeEqual(ms.LimitBytes, uint64(0))
+
+	// Test #2. Soft limit.
+	debug.SetMemoryLimit(1 << 40)
+	ms = ReadRuntimeMemory()
+	aTest.MustBeEqual(ms.LimitBytes, uint64(1<<40))
+}
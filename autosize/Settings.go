@@ -0,0 +1,70 @@
+package autosize
+
+import (
+	"errors"
+	"time"
+)
+
+// Settings contains settings of the controller.
+type Settings struct {
+	// Interval is a period between adjustments of the started controller.
+	Interval time.Duration
+
+	// HighWatermark is a fraction of the memory limit. When the used memory
+	// reaches it, volume limits are lowered.
+	HighWatermark float64
+
+	// LowWatermark is a fraction of the memory limit. When the used memory
+	// falls below it, volume limits are raised.
+	LowWatermark float64
+
+	// ShrinkFactor is a multiplier of volume limits used for lowering.
+	ShrinkFactor float64
+
+	// GrowFactor is a multiplier of volume limits used for raising.
+	GrowFactor float64
+
+	// MemoryLimit is an optional memory limit in bytes which overrides the
+	// limit reported by the memory reader.
+	MemoryLimit uint64
+
+	// MemoryReader reads memory usage of the process.
+	MemoryReader MemoryReader
+}
+
+// DefaultSettings returns default settings of the controller which use the
+// 'runtime/metrics' package and the soft memory limit.
+func DefaultSettings() (s Settings) {
+	return Settings{
+		Interval:      time.Second,
+		HighWatermark: 0.9,
+		LowWatermark:  0.7,
+		ShrinkFactor:  0.75,
+		GrowFactor:    1.1,
+		MemoryReader:  ReadRuntimeMemory,
+	}
+}
+
+func (s Settings) validate() (err error) {
+	if s.Interval <= 0 {
+		return errors.New(ErrIntervalIsNotPositive)
+	}
+
+	if (s.LowWatermark <= 0) || (s.LowWatermark >= s.HighWatermark) || (s.HighWatermark > 1) {
+		return errors.New(ErrWatermarksAreBad)
+	}
+
+	if (s.ShrinkFactor <= 0) || (s.ShrinkFactor >= 1) {
+		return errors.New(ErrShrinkFactorIsBad)
+	}
+
+	if s.GrowFactor <= 1 {
+		return errors.New(ErrGrowFactorIsBad)
+	}
+
+	if s.MemoryReader == nil {
+		return errors.New(ErrMemoryReaderIsNotSet)
+	}
+
+	return nil
+}
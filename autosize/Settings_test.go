@@ -0,0 +1,43 @@
+package autosize
+
+import (
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_validate(t *testing.T) {
+	aTest := tester.New(t)
+	var s Settings
+	var err error
+
+	// Test #1. Default settings.
+	s = DefaultSettings()
+	err = s.validate()
+	aTest.MustBeNoError(err)
+
+	// Test #2. Bad settings.
+	s = DefaultSettings()
+	s.Interval = 0
+	aTest.MustBeEqual(s.validate().Error(), ErrIntervalIsNotPositive)
+
+	s = DefaultSettings()
+	s.LowWatermark = s.HighWatermark
+	aTest.MustBeEqual(s.validate().Error(), ErrWatermarksAreBad)
+
+	s = DefaultSettings()
+	s.HighWatermark = 1.5
+	aTest.MustBeEqual(s.validate().Error(), ErrWatermarksAreBad)
+
+	s = DefaultSettings()
+	s.ShrinkFactor = 1
+	aTest.MustBeEqual(s.validate().Error(), ErrShrinkFactorIsBad)
+
+	s = DefaultSettings()
+	s.GrowFactor = 1
+	aTest.MustBeEqual(s.validate().Error(), ErrGrowFactorIsBad)
+
+	s = DefaultSettings()
+	s.MemoryReader = nil
+	aTest.MustBeEqual(s.validate().Error(), ErrMemoryReaderIsNotSet)
+}
@@ -0,0 +1,15 @@
+package autosize
+
+// Target is a cache whose volume limit is controlled, e.g. a cache with
+// volume calculation of the 'vl' package.
+type Target interface {
+	SetVolumeLimit(volumeLimit int) (droppedCount int, err error)
+}
+
+// target is a registered cache with its range of volume limits.
+type target struct {
+	cache          Target
+	minVolumeLimit int
+	maxVolumeLimit int
+	volumeLimit    int
+}
@@ -0,0 +1,15 @@
+package autosize
+
+const (
+	ErrIntervalIsNotPositive = "interval is not positive"
+	ErrWatermarksAreBad      = "watermarks must satisfy 0 < low < high <= 1"
+	ErrShrinkFactorIsBad     = "shrink factor must be in range (0, 1)"
+	ErrGrowFactorIsBad       = "grow factor must be greater than 1"
+	ErrMemoryReaderIsNotSet  = "memory reader is not set"
+	ErrNameIsEmpty           = "name is empty"
+	ErrNameIsUsed            = `name is already used: %v`
+	ErrTargetIsNil           = "target is nil"
+	ErrVolumeLimitsAreBad    = "volume limits must satisfy 0 < min <= max"
+	ErrControllerIsStarted   = "controller is already started"
+	ErrControllerIsStopped   = "controller is not started"
+)
@@ -0,0 +1,37 @@
+package cachetest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a clock which is moved manually. It may be used as a clock of
+// caches of both variants.
+type Clock struct {
+	t    time.Time
+	lock *sync.Mutex
+}
+
+// NewClock creates a new clock showing the specified time.
+func NewClock(t time.Time) (c *Clock) {
+	return &Clock{
+		t:    t,
+		lock: new(sync.Mutex),
+	}
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.t
+}
+
+// Add moves the clock forward.
+func (c *Clock) Add(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.t = c.t.Add(d)
+}
@@ -0,0 +1,18 @@
+package cachetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_Clock(t *testing.T) {
+	aTest := tester.New(t)
+	var c = NewClock(time.Unix(100, 0))
+
+	// Test.
+	aTest.MustBeEqual(c.Now(), time.Unix(100, 0))
+	c.Add(time.Second)
+	aTest.MustBeEqual(c.Now(), time.Unix(101, 0))
+}
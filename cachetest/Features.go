@@ -0,0 +1,16 @@
+package cachetest
+
+// Features are optional features of an implementation which are checked by
+// the suite. Basic operations and concurrent use are always checked.
+type Features struct {
+	// SizeLimit means that the size limit is supported, and that records are
+	// evicted in the least recently used order.
+	SizeLimit bool
+
+	// Ttl means that the TTL is supported, and that a record lives for the
+	// TTL after its last access.
+	Ttl bool
+
+	// OnEviction means that the eviction callback is supported.
+	OnEviction bool
+}
@@ -0,0 +1,20 @@
+package cachetest
+
+// Settings are settings of a cache created by a factory. A factory of an
+// implementation which does not support some settings ignores them, and the
+// features of the implementation tell the suite not to rely on them.
+type Settings struct {
+	// SizeLimit is a maximum number of records, zero disables the limit.
+	SizeLimit int
+
+	// RecordTtl is the records' TTL in seconds.
+	RecordTtl uint
+
+	// Clock is a source of current time which is moved by the suite.
+	Clock *Clock
+
+	// OnEviction must be called when a record is removed by the cache
+	// itself, i.e. when it is evicted to fit the size limit or is removed as
+	// outdated.
+	OnEviction func(uid string)
+}
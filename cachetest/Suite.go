@@ -0,0 +1,273 @@
+// Package cachetest contains a behavioural conformance suite for
+// implementations of the cache.Cache interface.
+package cachetest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cache "github.com/vault-thirteen/Cache"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+// Factory creates an empty cache using the settings. It may register clean-up
+// of the cache using the t.Cleanup method.
+type Factory func(t *testing.T, s Settings) (c cache.Cache[string, string])
+
+// Run runs the suite against caches created by the factory. Checks of
+// features which are not supported by the implementation are skipped.
+func Run(t *testing.T, factory Factory, features Features) {
+	t.Run("Basic", func(t *testing.T) {
+		testBasic(t, factory)
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		testConcurrency(t, factory)
+	})
+
+	t.Run("Ordering", func(t *testing.T) {
+		if !features.SizeLimit {
+			t.Skip("size limit is not supported")
+		}
+		testOrdering(t, factory, features)
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		if !features.SizeLimit {
+			t.Skip("size limit is not supported")
+		}
+		testLimits(t, factory)
+	})
+
+	t.Run("Ttl", func(t *testing.T) {
+		if !features.Ttl {
+			t.Skip("TTL is not supported")
+		}
+		testTtl(t, factory, features)
+	})
+}
+
+// evictionLog collects UIDs passed to the eviction callback.
+type evictionLog struct {
+	uids []string
+	lock sync.Mutex
+}
+
+func (el *evictionLog) add(uid string) {
+	el.lock.Lock()
+	defer el.lock.Unlock()
+
+	el.uids = append(el.uids, uid)
+}
+
+func (el *evictionLog) get() (uids []string) {
+	el.lock.Lock()
+	defer el.lock.Unlock()
+
+	return append([]string{}, el.uids...)
+}
+
+func newCache(t *testing.T, factory Factory, sizeLimit int, el *evictionLog) (c cache.Cache[string, string], clock *Clock) {
+	clock = NewClock(time.Unix(1_000_000, 0))
+	c = factory(t, Settings{
+		SizeLimit:  sizeLimit,
+		RecordTtl:  10,
+		Clock:      clock,
+		OnEviction: el.add,
+	})
+
+	return c, clock
+}
+
+func testBasic(t *testing.T, factory Factory) {
+	aTest := tester.New(t)
+	var el = new(evictionLog)
+	var data string
+	var err error
+
+	c, _ := newCache(t, factory, 0, el)
+
+	// Test #1. Empty cache.
+	aTest.MustBeEqual(c.RecordExists("A"), false)
+	_, err = c.GetRecord("A")
+	aTest.MustBeAnError(err)
+	err = c.RemoveExistingRecord("A")
+	aTest.MustBeAnError(err)
+	c.RemoveRecord("A")
+
+	// Test #2. Adding and updating.
+	aTest.MustBeNoError(c.AddRecord("A", "1"))
+	aTest.MustBeNoError(c.AddRecord("B", "2"))
+	aTest.MustBeEqual(c.RecordExists("A"), true)
+	data, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "1")
+	aTest.MustBeNoError(c.AddRecord("A", "11"))
+	data, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "11")
+
+	// Test #3. Removal is not an eviction.
+	c.RemoveRecord("A")
+	aTest.MustBeEqual(c.RecordExists("A"), false)
+	aTest.MustBeNoError(c.RemoveExistingRecord("B"))
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+	err = c.RemoveExistingRecord("B")
+	aTest.MustBeAnError(err)
+
+	// Test #4. Clearing is not an eviction.
+	for i := 0; i < 10; i++ {
+		aTest.MustBeNoError(c.AddRecord(fmt.Sprint(i), "x"))
+	}
+	aTest.MustBeNoError(c.Clear())
+	for i := 0; i < 10; i++ {
+		aTest.MustBeEqual(c.RecordExists(fmt.Sprint(i)), false)
+	}
+	aTest.MustBeEqual(len(el.get()), 0)
+}
+
+func testConcurrency(t *testing.T, factory Factory) {
+	const (
+		goroutinesCount = 8
+		operationsCount = 500
+		uidsCount       = 20
+	)
+
+	var el = new(evictionLog)
+	c, _ := newCache(t, factory, 0, el)
+
+	var wg sync.WaitGroup
+	var errs = make(chan error, goroutinesCount*operationsCount)
+	for g := 0; g < goroutinesCount; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < operationsCount; i++ {
+				uid := fmt.Sprint((g + i) % uidsCount)
+				switch i % 4 {
+				case 0, 1:
+					err := c.AddRecord(uid, "data-"+uid)
+					if err != nil {
+						errs <- err
+					}
+				case 2:
+					data, err := c.GetRecord(uid)
+					if (err == nil) && (data != "data-"+uid) {
+						errs <- fmt.Errorf("record %v has foreign data: %v", uid, data)
+					}
+				case 3:
+					c.RemoveRecord(uid)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func testOrdering(t *testing.T, factory Factory, features Features) {
+	aTest := tester.New(t)
+	var el = new(evictionLog)
+	var err error
+
+	c, _ := newCache(t, factory, 3, el)
+
+	// Test #1. Reading moves a record to the top.
+	for _, uid := range []string{"A", "B", "C"} {
+		aTest.MustBeNoError(c.AddRecord(uid, uid))
+	}
+	_, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(c.AddRecord("D", "D"))
+	aTest.MustBeEqual(existing(c, "A", "B", "C", "D"), "A,C,D")
+
+	// Test #2. Updating moves a record to the top.
+	aTest.MustBeNoError(c.AddRecord("C", "CC"))
+	aTest.MustBeNoError(c.AddRecord("E", "E"))
+	aTest.MustBeEqual(existing(c, "A", "C", "D", "E"), "C,D,E")
+
+	// Test #3. Checking existence does not move a record.
+	aTest.MustBeEqual(c.RecordExists("D"), true)
+	aTest.MustBeNoError(c.AddRecord("F", "F"))
+	aTest.MustBeEqual(existing(c, "C", "D", "E", "F"), "C,E,F")
+
+	// Test #4. Callbacks.
+	if features.OnEviction {
+		aTest.MustBeEqual(el.get(), []string{"B", "A", "D"})
+	}
+}
+
+func testLimits(t *testing.T, factory Factory) {
+	aTest := tester.New(t)
+	var el = new(evictionLog)
+	var uids []string
+
+	c, _ := newCache(t, factory, 5, el)
+
+	// Test.
+	for i := 0; i < 20; i++ {
+		uid := fmt.Sprint(i)
+		uids = append(uids, uid)
+		aTest.MustBeNoError(c.AddRecord(uid, uid))
+	}
+	aTest.MustBeEqual(existing(c, uids...), "15,16,17,18,19")
+}
+
+func testTtl(t *testing.T, factory Factory, features Features) {
+	aTest := tester.New(t)
+	var el = new(evictionLog)
+	var err error
+
+	c, clock := newCache(t, factory, 0, el)
+
+	// Test #1. Record is alive during the TTL.
+	aTest.MustBeNoError(c.AddRecord("A", "1"))
+	aTest.MustBeNoError(c.AddRecord("B", "2"))
+	clock.Add(time.Second * 9)
+	aTest.MustBeEqual(c.RecordExists("A"), true)
+
+	// Test #2. Reading prolongs the life.
+	_, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	clock.Add(time.Second * 2)
+	aTest.MustBeEqual(c.RecordExists("A"), true)
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+
+	// Test #3. Outdated record is not returned.
+	clock.Add(time.Second * 10)
+	_, err = c.GetRecord("A")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(c.RecordExists("A"), false)
+
+	// Test #4. Updating prolongs the life.
+	aTest.MustBeNoError(c.AddRecord("C", "3"))
+	clock.Add(time.Second * 9)
+	aTest.MustBeNoError(c.AddRecord("C", "33"))
+	clock.Add(time.Second * 9)
+	aTest.MustBeEqual(c.RecordExists("C"), true)
+
+	// Test #5. Callbacks.
+	if features.OnEviction {
+		aTest.MustBeEqual(el.get(), []string{"B", "A"})
+	}
+}
+
+// existing returns a comma-separated list of existing records.
+func existing(c cache.Cache[string, string], uids ...string) (list string) {
+	var found []string
+	for _, uid := range uids {
+		if c.RecordExists(uid) {
+			found = append(found, uid)
+		}
+	}
+
+	return strings.Join(found, ",")
+}
@@ -0,0 +1,349 @@
+// Package cluster distributes records across several cache servers of the
+// 'resp' package. Record UIDs are mapped to servers, the nodes, by a
+// consistent hashing ring with virtual nodes, so that adding or removing a
+// node moves only a small part of the records. A node which fails is not
+// used for some time, its records are served by the next nodes on the ring.
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vault-thirteen/Cache/remote"
+	"github.com/vault-thirteen/Cache/resp"
+)
+
+// Cluster is a client of a cluster of cache servers. It has the method set of
+// the cache.Cache interface, each record is stored on a single node chosen
+// by the UID of the record.
+//
+// A node fails when a command fails with a network error. Errors which the
+// client of a node reports by itself, e.g. errors of tracking keys for its
+// near cache, do not change the state of the node. Records of a failed
+// node are served by the next nodes on the ring until the retry interval
+// passes, then the node is tried again. Records are not replicated, so
+// records of a failed node are missed while it is failed.
+type Cluster struct {
+	settings Settings
+
+	// lock guards the ring and the nodes.
+	lock     *sync.RWMutex
+	ring     *Ring
+	nodes    map[string]*node
+	isClosed bool
+}
+
+// Dial creates a client of the cluster and connects to its nodes.
+func Dial(settings Settings) (c *Cluster, err error) {
+	err = settings.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.RetryInterval == 0 {
+		settings.RetryInterval = DefaultRetryInterval
+	}
+
+	c = &Cluster{
+		settings: settings,
+		lock:     new(sync.RWMutex),
+		ring:     NewRing(settings.VirtualNodes),
+		nodes:    make(map[string]*node),
+	}
+
+	for _, address := range settings.Addresses {
+		err = c.AddNode(address)
+		if err != nil {
+			_ = c.Close()
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func (c *Cluster) now() time.Time {
+	if c.settings.Clock == nil {
+		return time.Now()
+	}
+
+	return c.settings.Clock.Now()
+}
+
+// AddNode connects to the server and adds it to the ring.
+func (c *Cluster) AddNode(address string) (err error) {
+	if len(address) == 0 {
+		return errors.New(ErrAddressIsNotSet)
+	}
+
+	c.lock.RLock()
+	_, exists := c.nodes[address]
+	c.lock.RUnlock()
+	if exists {
+		return fmt.Errorf(ErrNodeIsAlreadyAdded, address)
+	}
+
+	n := newNode(address)
+	settings := c.settings.Client
+	settings.Address = address
+	settings.OnError = func(err error) {
+		c.reportError(fmt.Errorf(ErrNodeReportsError, address, err))
+	}
+
+	n.client, err = remote.Dial(settings)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.isClosed {
+		_ = n.client.Close()
+		return errors.New(ErrClusterIsClosed)
+	}
+	if _, exists = c.nodes[address]; exists {
+		_ = n.client.Close()
+		return fmt.Errorf(ErrNodeIsAlreadyAdded, address)
+	}
+
+	c.nodes[address] = n
+	c.ring.Add(address)
+
+	return nil
+}
+
+// RemoveNode removes the node from the ring and disconnects from it. Records
+// of the node are not moved to other nodes.
+func (c *Cluster) RemoveNode(address string) (err error) {
+	c.lock.Lock()
+	n, exists := c.nodes[address]
+	if !exists {
+		c.lock.Unlock()
+		return fmt.Errorf(ErrNodeIsNotFound, address)
+	}
+	delete(c.nodes, address)
+	c.ring.Remove(address)
+	c.lock.Unlock()
+
+	return n.client.Close()
+}
+
+// Nodes returns addresses of the nodes sorted in ascending order.
+func (c *Cluster) Nodes() (addresses []string) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.ring.Nodes()
+}
+
+// NodeOf returns the address of the node which currently serves the record,
+// i.e. of the first node on the ring which is not failed.
+func (c *Cluster) NodeOf(uid string) (address string, err error) {
+	var nodes []*node
+	nodes, err = c.sequence(uid)
+	if err != nil {
+		return "", err
+	}
+
+	for _, n := range nodes {
+		if n.isWorking() {
+			return n.address, nil
+		}
+	}
+
+	return "", errors.New(ErrNoNodeIsAvailable)
+}
+
+// sequence returns the nodes in the order of their use for the record.
+func (c *Cluster) sequence(uid string) (nodes []*node, err error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.isClosed {
+		return nil, errors.New(ErrClusterIsClosed)
+	}
+
+	for _, address := range c.ring.Sequence(uid) {
+		nodes = append(nodes, c.nodes[address])
+	}
+
+	return nodes, nil
+}
+
+// route executes the operation on the first node of the record which
+// works. When the node fails, the operation is executed on the next node.
+func (c *Cluster) route(uid string, op func(n *node) (err error)) (err error) {
+	var nodes []*node
+	nodes, err = c.sequence(uid)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range nodes {
+		if !c.isUsable(n) {
+			continue
+		}
+
+		err = op(n)
+		if isNodeError(err, uid) {
+			c.fail(n, err)
+			continue
+		}
+
+		return err
+	}
+
+	return errors.New(ErrNoNodeIsAvailable)
+}
+
+// isUsable tells whether the node may be used. A failed node is tried again
+// after the retry interval, it is cleared first if it is set so.
+func (c *Cluster) isUsable(n *node) bool {
+	now := c.now()
+	isUsable, isRetry := n.claimRetry(now, now.Add(c.settings.RetryInterval))
+	if !isUsable || !isRetry {
+		return isUsable
+	}
+
+	if c.settings.ClearOnRecovery {
+		err := n.client.Clear()
+		if err != nil {
+			c.fail(n, err)
+			return false
+		}
+	}
+
+	n.recover()
+
+	return true
+}
+
+// fail marks the node as failed and reports the error.
+func (c *Cluster) fail(n *node, err error) {
+	n.fail(c.now().Add(c.settings.RetryInterval))
+	c.reportError(fmt.Errorf(ErrNodeIsFailed, n.address, err))
+}
+
+// isNodeError tells whether the error is caused by a failure of the node, not
+// by the absence of the record or by rejection of the command by the
+// server.
+func isNodeError(err error, uid string) bool {
+	if err == nil {
+		return false
+	}
+	if err.Error() == fmt.Sprintf(remote.ErrRecordIsNotFound, uid) {
+		return false
+	}
+
+	var replyErr resp.Error
+	return !errors.As(err, &replyErr)
+}
+
+func (c *Cluster) reportError(err error) {
+	if c.settings.OnError != nil {
+		c.settings.OnError(err)
+	}
+}
+
+// RecordExists checks whether the specified record exists or not. Errors
+// are reported to the OnError callback, a record is considered absent then.
+func (c *Cluster) RecordExists(uid string) (recordExists bool) {
+	err := c.route(uid, func(n *node) (err error) {
+		recordExists, err = n.client.CheckRecord(uid)
+		return err
+	})
+	if err != nil {
+		c.reportError(err)
+		return false
+	}
+
+	return recordExists
+}
+
+// AddRecord adds a new record or updates an existing one.
+func (c *Cluster) AddRecord(uid string, data []byte) (err error) {
+	return c.route(uid, func(n *node) (err error) {
+		return n.client.AddRecord(uid, data)
+	})
+}
+
+// GetRecord reads a record.
+func (c *Cluster) GetRecord(uid string) (data []byte, err error) {
+	err = c.route(uid, func(n *node) (err error) {
+		data, err = n.client.GetRecord(uid)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// RemoveRecord removes a record if it exists. Errors are reported to the
+// OnError callback.
+func (c *Cluster) RemoveRecord(uid string) {
+	err := c.route(uid, func(n *node) (err error) {
+		return n.client.RemoveExistingRecord(uid)
+	})
+	if (err != nil) && (err.Error() != fmt.Sprintf(remote.ErrRecordIsNotFound, uid)) {
+		c.reportError(err)
+	}
+}
+
+// RemoveExistingRecord removes an existing record, it returns an error if
+// the record is not found.
+func (c *Cluster) RemoveExistingRecord(uid string) (err error) {
+	return c.route(uid, func(n *node) (err error) {
+		return n.client.RemoveExistingRecord(uid)
+	})
+}
+
+// Clear removes all records of all the nodes. Errors of failed nodes are
+// joined.
+func (c *Cluster) Clear() (err error) {
+	c.lock.RLock()
+	if c.isClosed {
+		c.lock.RUnlock()
+		return errors.New(ErrClusterIsClosed)
+	}
+	nodes := make([]*node, 0, len(c.nodes))
+	for _, n := range c.nodes {
+		nodes = append(nodes, n)
+	}
+	c.lock.RUnlock()
+
+	var errs []error
+	for _, n := range nodes {
+		err = n.client.Clear()
+		if err != nil {
+			if isNodeError(err, "") {
+				c.fail(n, err)
+			}
+			errs = append(errs, fmt.Errorf(ErrNodeIsFailed, n.address, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close disconnects from all the nodes.
+func (c *Cluster) Close() (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.isClosed {
+		return nil
+	}
+	c.isClosed = true
+
+	var errs []error
+	for _, n := range c.nodes {
+		errs = append(errs, n.client.Close())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,339 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	cache "github.com/vault-thirteen/Cache"
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/cachetest"
+	"github.com/vault-thirteen/Cache/remote"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+// stringCache adapts the cluster to string data for the conformance suite.
+type stringCache struct {
+	c *Cluster
+}
+
+func (sc stringCache) RecordExists(uid string) (recordExists bool) {
+	return sc.c.RecordExists(uid)
+}
+
+func (sc stringCache) AddRecord(uid string, data string) (err error) {
+	return sc.c.AddRecord(uid, []byte(data))
+}
+
+func (sc stringCache) GetRecord(uid string) (data string, err error) {
+	var buf []byte
+	buf, err = sc.c.GetRecord(uid)
+	return string(buf), err
+}
+
+func (sc stringCache) RemoveRecord(uid string) {
+	sc.c.RemoveRecord(uid)
+}
+
+func (sc stringCache) RemoveExistingRecord(uid string) (err error) {
+	return sc.c.RemoveExistingRecord(uid)
+}
+
+func (sc stringCache) Clear() (err error) {
+	return sc.c.Clear()
+}
+
+// errorLog collects errors passed to the error callback.
+type errorLog struct {
+	errs []error
+	lock sync.Mutex
+}
+
+func (el *errorLog) add(err error) {
+	el.lock.Lock()
+	defer el.lock.Unlock()
+
+	el.errs = append(el.errs, err)
+}
+
+func (el *errorLog) count() int {
+	el.lock.Lock()
+	defer el.lock.Unlock()
+
+	return len(el.errs)
+}
+
+func Test_Conformance(t *testing.T) {
+	cachetest.Run(t, func(t *testing.T, s cachetest.Settings) cache.Cache[string, string] {
+		var addresses []string
+		for i := 0; i < 3; i++ {
+			_, address := _test_serve(t, "127.0.0.1:0")
+			addresses = append(addresses, address)
+		}
+
+		return stringCache{_test_dial(t, Settings{Addresses: addresses})}
+	}, cachetest.Features{})
+}
+
+func Test_Dial(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	_, address := _test_serve(t, "127.0.0.1:0")
+
+	// Test #1. Bad settings.
+	_, err = Dial(Settings{VirtualNodes: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrVirtualNodesNegative)
+	_, err = Dial(Settings{RetryInterval: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrRetryIntervalIsBad)
+	_, err = Dial(Settings{Addresses: []string{address, ""}})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrAddressIsNotSet)
+	_, err = Dial(Settings{Addresses: []string{address, address}})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrNodeIsAlreadyAdded, address))
+
+	// Test #2. Unreachable node.
+	s, unreachable := _test_serve(t, "127.0.0.1:0")
+	aTest.MustBeNoError(s.Close())
+	_, err = Dial(Settings{Addresses: []string{address, unreachable}})
+	aTest.MustBeAnError(err)
+
+	// Test #3. Empty cluster.
+	c := _test_dial(t, Settings{})
+	aTest.MustBeEqual(c.settings.RetryInterval, DefaultRetryInterval)
+	err = c.AddRecord("a", []byte("1"))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrNoNodeIsAvailable)
+
+	// Test #4. Closed cluster.
+	aTest.MustBeNoError(c.Close())
+	_, err = c.GetRecord("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrClusterIsClosed)
+	err = c.AddNode(address)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrClusterIsClosed)
+}
+
+func Test_Cluster_nodes(t *testing.T) {
+	aTest := tester.New(t)
+	const keysCount = 300
+	var err error
+
+	var addresses []string
+	for i := 0; i < 3; i++ {
+		_, address := _test_serve(t, "127.0.0.1:0")
+		addresses = append(addresses, address)
+	}
+	c := _test_dial(t, Settings{Addresses: addresses[:2]})
+
+	// Test #1. Records are distributed across the nodes.
+	for i := 0; i < keysCount; i++ {
+		aTest.MustBeNoError(c.AddRecord(fmt.Sprint(i), []byte("x")))
+	}
+	size0, size1 := _test_size(t, addresses[0]), _test_size(t, addresses[1])
+	aTest.MustBeEqual(size0+size1, int64(keysCount))
+	aTest.MustBeDifferent(size0, int64(0))
+	aTest.MustBeDifferent(size1, int64(0))
+
+	// Test #2. Adding a node.
+	aTest.MustBeNoError(c.AddNode(addresses[2]))
+	err = c.AddNode(addresses[2])
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrNodeIsAlreadyAdded, addresses[2]))
+	var missed int
+	for i := 0; i < keysCount; i++ {
+		uid := fmt.Sprint(i)
+		address, err := c.NodeOf(uid)
+		aTest.MustBeNoError(err)
+		if !c.RecordExists(uid) {
+			aTest.MustBeEqual(address, addresses[2])
+			missed++
+		}
+	}
+	aTest.MustBeDifferent(missed, 0)
+
+	// Test #3. Removing a node.
+	aTest.MustBeNoError(c.RemoveNode(addresses[0]))
+	err = c.RemoveNode(addresses[0])
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrNodeIsNotFound, addresses[0]))
+	aTest.MustBeEqual(len(c.Nodes()), 2)
+	for i := 0; i < keysCount; i++ {
+		address, err := c.NodeOf(fmt.Sprint(i))
+		aTest.MustBeNoError(err)
+		aTest.MustBeDifferent(address, addresses[0])
+	}
+
+	// Test #4. Clearing clears all the nodes.
+	aTest.MustBeNoError(c.Clear())
+	aTest.MustBeEqual(_test_size(t, addresses[1]), int64(0))
+	aTest.MustBeEqual(_test_size(t, addresses[2]), int64(0))
+}
+
+func Test_Cluster_failover(t *testing.T) {
+	aTest := tester.New(t)
+	var data []byte
+	var err error
+
+	var servers = map[string]func() error{}
+	var addresses []string
+	for i := 0; i < 3; i++ {
+		s, address := _test_serve(t, "127.0.0.1:0")
+		servers[address] = s.Close
+		addresses = append(addresses, address)
+	}
+	clock := cachetest.NewClock(time.Unix(1_700_000_000, 0))
+	el := new(errorLog)
+	c := _test_dial(t, Settings{
+		Addresses:     addresses,
+		Client:        remote.Settings{DialTimeout: time.Second, Timeout: time.Second},
+		RetryInterval: time.Second * 10,
+		Clock:         clock,
+		OnError:       el.add,
+	})
+
+	aTest.MustBeNoError(c.AddRecord("a", []byte("1")))
+	owner, err := c.NodeOf("a")
+	aTest.MustBeNoError(err)
+
+	// Test #1. Records of a failed node are served by the next node.
+	aTest.MustBeNoError(servers[owner]())
+	_, err = c.GetRecord("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(remote.ErrRecordIsNotFound, "a"))
+	aTest.MustBeEqual(el.count(), 1)
+	next, err := c.NodeOf("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeDifferent(next, owner)
+
+	aTest.MustBeNoError(c.AddRecord("a", []byte("2")))
+	data, err = c.GetRecord("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("2"))
+	aTest.MustBeEqual(_test_size(t, next), int64(1))
+
+	// Test #2. The failed node is not used during the retry interval.
+	clock.Add(time.Second * 9)
+	aTest.MustBeEqual(c.RecordExists("a"), true)
+	aTest.MustBeEqual(el.count(), 1)
+
+	// Test #3. The failed node is tried after the retry interval.
+	clock.Add(time.Second)
+	aTest.MustBeEqual(c.RecordExists("a"), true)
+	aTest.MustBeEqual(el.count(), 2)
+
+	// Test #4. The recovered node is used.
+	s, _ := _test_serve(t, owner)
+	clock.Add(time.Second * 10)
+	aTest.MustBeEqual(c.RecordExists("a"), false)
+	address, err := c.NodeOf("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(address, owner)
+	aTest.MustBeEqual(el.count(), 2)
+
+	// Test #5. No node is available.
+	aTest.MustBeNoError(s.Close())
+	for _, address := range addresses {
+		if address != owner {
+			aTest.MustBeNoError(servers[address]())
+		}
+	}
+	err = c.AddRecord("a", []byte("3"))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrNoNodeIsAvailable)
+	_, err = c.NodeOf("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrNoNodeIsAvailable)
+	err = c.Clear()
+	aTest.MustBeAnError(err)
+}
+
+func Test_Cluster_clearOnRecovery(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	var addresses []string
+	for i := 0; i < 2; i++ {
+		_, address := _test_serve(t, "127.0.0.1:0")
+		addresses = append(addresses, address)
+	}
+	clock := cachetest.NewClock(time.Unix(1_700_000_000, 0))
+	c := _test_dial(t, Settings{
+		Addresses:       addresses,
+		RetryInterval:   time.Second,
+		ClearOnRecovery: true,
+		Clock:           clock,
+	})
+
+	aTest.MustBeNoError(c.AddRecord("a", []byte("1")))
+	owner, _ := c.NodeOf("a")
+
+	// Test #1. A record changed during a failure is not stale.
+	c.lock.RLock()
+	n := c.nodes[owner]
+	c.lock.RUnlock()
+	c.fail(n, errors.New("test"))
+	aTest.MustBeNoError(c.AddRecord("a", []byte("2")))
+	clock.Add(time.Second)
+	_, err = c.GetRecord("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(_test_size(t, owner), int64(0))
+}
+
+func Test_Cluster_route(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	var addresses []string
+	for i := 0; i < 2; i++ {
+		_, address := _test_serve(t, "127.0.0.1:0")
+		addresses = append(addresses, address)
+	}
+	c := _test_dial(t, Settings{Addresses: addresses})
+
+	// Test. A failure of the node reported during a successful operation
+	// does not move the operation to the next node.
+	var calls []string
+	err = c.route("a", func(n *node) (err error) {
+		calls = append(calls, n.address)
+		c.fail(n, errors.New("test"))
+		return nil
+	})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(len(calls), 1)
+}
+
+func Test_Cluster_trackingErrors(t *testing.T) {
+	aTest := tester.New(t)
+
+	s, address := _test_serve(t, "127.0.0.1:0")
+	el := new(errorLog)
+	c := _test_dial(t, Settings{
+		Addresses: []string{address},
+		Client: remote.Settings{
+			DialTimeout:       time.Second,
+			NearCache:         &vl.Config[string, []byte]{RecordTtl: 60},
+			ReconnectInterval: time.Millisecond * 10,
+		},
+		OnError: el.add,
+	})
+
+	// Test. Errors of tracking are reported, but the node is not failed.
+	aTest.MustBeNoError(s.Close())
+	deadline := time.Now().Add(time.Second * 5)
+	for el.count() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("error is not reported")
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+	address2, err := c.NodeOf("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(address2, address)
+}
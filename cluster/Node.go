@@ -0,0 +1,72 @@
+package cluster
+
+import (
+	"sync"
+	"time"
+
+	"github.com/vault-thirteen/Cache/remote"
+)
+
+// node is a cache server of the cluster.
+type node struct {
+	address string
+	client  *remote.Client
+
+	// lock guards the state of the node.
+	lock *sync.Mutex
+
+	// A failed node is not used until the retry time.
+	isFailed bool
+	retryAt  time.Time
+}
+
+func newNode(address string) (n *node) {
+	return &node{
+		address: address,
+		lock:    new(sync.Mutex),
+	}
+}
+
+// fail marks the node as failed until the retry time.
+func (n *node) fail(retryAt time.Time) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.isFailed = true
+	n.retryAt = retryAt
+}
+
+// claimRetry tells whether the node may be used. A failed node may be used
+// after the retry time by a single caller, which moves the retry time to the
+// next one and tries the node.
+func (n *node) claimRetry(now time.Time, nextRetryAt time.Time) (isUsable bool, isRetry bool) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if !n.isFailed {
+		return true, false
+	}
+	if now.Before(n.retryAt) {
+		return false, false
+	}
+
+	n.retryAt = nextRetryAt
+
+	return true, true
+}
+
+// recover marks the failed node as working.
+func (n *node) recover() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.isFailed = false
+}
+
+// isWorking tells whether the node is not failed.
+func (n *node) isWorking() bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return !n.isFailed
+}
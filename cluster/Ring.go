@@ -0,0 +1,166 @@
+package cluster
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+)
+
+// DefaultVirtualNodes is the default number of points of a node on the ring.
+const DefaultVirtualNodes = 160
+
+// Ring is a consistent hashing ring. Each node is placed on the ring at
+// several points, the virtual nodes, and a key belongs to the node of the
+// first point following the hash of the key. Adding or removing a node moves
+// only the keys of its points, i.e. about 1/N of all the keys. Hashes are
+// stable, so rings of different processes having the same nodes agree on
+// owners of keys. The ring is not safe for concurrent use.
+type Ring struct {
+	virtualNodes int
+	points       []point
+	nodes        map[string]struct{}
+}
+
+// point is a virtual node.
+type point struct {
+	hash uint64
+	node string
+}
+
+// NewRing creates an empty ring placing each node at the specified number of
+// points. A non-positive number is replaced with the default value.
+func NewRing(virtualNodes int) (r *Ring) {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+
+	return &Ring{
+		virtualNodes: virtualNodes,
+		nodes:        make(map[string]struct{}),
+	}
+}
+
+// Add adds a node to the ring. Adding an existing node does nothing.
+func (r *Ring) Add(node string) {
+	if _, exists := r.nodes[node]; exists {
+		return
+	}
+	r.nodes[node] = struct{}{}
+
+	for i := 0; i < r.virtualNodes; i++ {
+		r.points = append(r.points, point{
+			hash: hashOf(node + "#" + strconv.Itoa(i)),
+			node: node,
+		})
+	}
+
+	slices.SortFunc(r.points, comparePoints)
+}
+
+// Remove removes a node from the ring. Removing an absent node does nothing.
+func (r *Ring) Remove(node string) {
+	if _, exists := r.nodes[node]; !exists {
+		return
+	}
+	delete(r.nodes, node)
+
+	r.points = slices.DeleteFunc(r.points, func(p point) bool {
+		return p.node == node
+	})
+}
+
+// Nodes returns the nodes of the ring sorted by name.
+func (r *Ring) Nodes() (nodes []string) {
+	nodes = make([]string, 0, len(r.nodes))
+	for node := range r.nodes {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+
+	return nodes
+}
+
+// Get returns the node owning the key, it fails when the ring is empty.
+func (r *Ring) Get(key string) (node string, ok bool) {
+	if len(r.points) == 0 {
+		return "", false
+	}
+
+	return r.points[r.search(key)].node, true
+}
+
+// Sequence returns all the nodes in the order of their first points following
+// the hash of the key. The first node owns the key, the following nodes are
+// its successors used when preceding nodes fail.
+func (r *Ring) Sequence(key string) (nodes []string) {
+	if len(r.points) == 0 {
+		return nil
+	}
+
+	nodes = make([]string, 0, len(r.nodes))
+	seen := make(map[string]struct{}, len(r.nodes))
+	start := r.search(key)
+	for i := 0; (i < len(r.points)) && (len(nodes) < len(r.nodes)); i++ {
+		node := r.points[(start+i)%len(r.points)].node
+		if _, isSeen := seen[node]; isSeen {
+			continue
+		}
+		seen[node] = struct{}{}
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// search returns the index of the first point following the hash of the key.
+func (r *Ring) search(key string) (i int) {
+	h := hashOf(key)
+	i, _ = slices.BinarySearchFunc(r.points, h, func(p point, h uint64) int {
+		switch {
+		case p.hash < h:
+			return -1
+		case p.hash > h:
+			return 1
+		default:
+			return 0
+		}
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+
+	return i
+}
+
+func comparePoints(a, b point) int {
+	switch {
+	case a.hash < b.hash:
+		return -1
+	case a.hash > b.hash:
+		return 1
+	case a.node < b.node:
+		return -1
+	case a.node > b.node:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// hashOf returns the FNV-1a hash of the string with its bits mixed, as
+// hashes of similar strings, e.g. names of virtual nodes, differ in few
+// bits.
+func hashOf(s string) (h uint64) {
+	f := fnv.New64a()
+	_, _ = f.Write([]byte(s))
+	h = f.Sum64()
+
+	// The finalizer of SplitMix64.
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+
+	return h
+}
@@ -0,0 +1,136 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+// _test_owners returns owners of the keys.
+func _test_owners(r *Ring, keysCount int) (owners []string) {
+	owners = make([]string, 0, keysCount)
+	for i := 0; i < keysCount; i++ {
+		node, _ := r.Get(fmt.Sprint("key-", i))
+		owners = append(owners, node)
+	}
+
+	return owners
+}
+
+func Test_NewRing(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test #1. Default number of virtual nodes.
+	r := NewRing(0)
+	aTest.MustBeEqual(r.virtualNodes, DefaultVirtualNodes)
+
+	// Test #2. Empty ring.
+	_, ok := r.Get("a")
+	aTest.MustBeEqual(ok, false)
+	aTest.MustBeEqual(len(r.Sequence("a")), 0)
+	aTest.MustBeEqual(r.Nodes(), []string{})
+}
+
+func Test_Ring_Add(t *testing.T) {
+	aTest := tester.New(t)
+	const keysCount = 10_000
+
+	r := NewRing(0)
+	for _, node := range []string{"n1", "n2", "n3", "n4"} {
+		r.Add(node)
+	}
+
+	// Test #1. Nodes.
+	r.Add("n1")
+	aTest.MustBeEqual(r.Nodes(), []string{"n1", "n2", "n3", "n4"})
+	aTest.MustBeEqual(len(r.points), 4*DefaultVirtualNodes)
+
+	// Test #2. Keys are distributed evenly.
+	owners := _test_owners(r, keysCount)
+	counts := map[string]int{}
+	for _, owner := range owners {
+		counts[owner]++
+	}
+	for node, count := range counts {
+		if (count < keysCount/4*7/10) || (count > keysCount/4*13/10) {
+			t.Errorf("node %v owns %v keys", node, count)
+		}
+	}
+
+	// Test #3. Only keys of the new node are moved.
+	r.Add("n5")
+	var moved int
+	for i, owner := range _test_owners(r, keysCount) {
+		if owner != owners[i] {
+			aTest.MustBeEqual(owner, "n5")
+			moved++
+		}
+	}
+	if (moved < keysCount/5*7/10) || (moved > keysCount/5*13/10) {
+		t.Errorf("%v keys are moved", moved)
+	}
+}
+
+func Test_Ring_Remove(t *testing.T) {
+	aTest := tester.New(t)
+	const keysCount = 10_000
+
+	r := NewRing(50)
+	for _, node := range []string{"n1", "n2", "n3"} {
+		r.Add(node)
+	}
+	owners := _test_owners(r, keysCount)
+
+	// Test #1. Only keys of the removed node are moved.
+	r.Remove("n2")
+	r.Remove("n4")
+	aTest.MustBeEqual(r.Nodes(), []string{"n1", "n3"})
+	aTest.MustBeEqual(len(r.points), 2*50)
+	for i, owner := range _test_owners(r, keysCount) {
+		if owners[i] != "n2" {
+			aTest.MustBeEqual(owner, owners[i])
+		} else {
+			aTest.MustBeDifferent(owner, "n2")
+		}
+	}
+
+	// Test #2. The ring returns to its previous state.
+	r.Add("n2")
+	aTest.MustBeEqual(_test_owners(r, keysCount), owners)
+}
+
+func Test_Ring_Sequence(t *testing.T) {
+	aTest := tester.New(t)
+
+	r := NewRing(10)
+	for _, node := range []string{"n1", "n2", "n3"} {
+		r.Add(node)
+	}
+
+	// Test #1. All the nodes follow the owner.
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprint("key-", i)
+		nodes := r.Sequence(key)
+		aTest.MustBeEqual(len(nodes), 3)
+		owner, _ := r.Get(key)
+		aTest.MustBeEqual(nodes[0], owner)
+		aTest.MustBeDifferent(nodes[1], nodes[0])
+		aTest.MustBeDifferent(nodes[2], nodes[0])
+		aTest.MustBeDifferent(nodes[2], nodes[1])
+
+		// Test #2. The successor owns the key without the owner.
+		r.Remove(owner)
+		next, _ := r.Get(key)
+		aTest.MustBeEqual(next, nodes[1])
+		r.Add(owner)
+	}
+}
+
+func Test_hashOf(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test #1. Hashes are stable.
+	aTest.MustBeEqual(hashOf("a"), hashOf("a"))
+	aTest.MustBeDifferent(hashOf("n1#1"), hashOf("n1#2"))
+}
@@ -0,0 +1,59 @@
+package cluster
+
+import (
+	"errors"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/remote"
+)
+
+// DefaultRetryInterval is the default period during which a failed node is
+// not used.
+const DefaultRetryInterval = time.Second * 10
+
+// Settings contains settings of the cluster.
+type Settings struct {
+	// Addresses are TCP addresses of the RESP servers of nodes. More nodes
+	// may be added later using the AddNode method.
+	Addresses []string
+
+	// Client is a template of settings of clients of nodes. The address and
+	// the error callback of the template are ignored.
+	Client remote.Settings
+
+	// VirtualNodes is a number of points of each node on the ring. Zero
+	// selects the default number.
+	VirtualNodes int
+
+	// RetryInterval is a period during which a failed node is not used, its
+	// keys are served by the next nodes on the ring. Zero selects the
+	// default interval.
+	RetryInterval time.Duration
+
+	// ClearOnRecovery makes the cluster clear a failed node before it is
+	// used again. Keys changed on other nodes during the failure are stale
+	// on the recovered node, clearing removes them at the cost of losing
+	// all the records of the node.
+	ClearOnRecovery bool
+
+	// Clock is an optional source of current time used for retry intervals.
+	Clock vl.Clock
+
+	// OnError is an optional callback which receives errors which can not be
+	// returned to a caller, i.e. errors of nodes which are served by other
+	// nodes, errors of methods without errors and errors which clients of
+	// nodes report by themselves.
+	OnError func(err error)
+}
+
+func (s Settings) validate() (err error) {
+	if s.VirtualNodes < 0 {
+		return errors.New(ErrVirtualNodesNegative)
+	}
+	if s.RetryInterval < 0 {
+		return errors.New(ErrRetryIntervalIsBad)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package cluster
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/resp"
+)
+
+// _test_serve starts a RESP server of a new cache at the address. Port zero
+// selects a free port.
+func _test_serve(t *testing.T, address string) (s *resp.Server, serverAddress string) {
+	s, err := resp.NewServer([]*vl.Cache[string, []byte]{vl.NewCache[string, []byte](0, 0, 3600)}, resp.Settings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var l net.Listener
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		l, err = net.Listen("tcp", address)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	go func() { _ = s.Serve(l) }()
+	t.Cleanup(func() { _ = s.Close() })
+
+	return s, l.Addr().String()
+}
+
+// _test_size returns the number of keys of the server.
+func _test_size(t *testing.T, address string) (size int64) {
+	client, err := resp.Dial(address, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	reply, err := client.Do("DBSIZE")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return reply.(int64)
+}
+
+// _test_dial creates a client of the cluster which is closed at the end of
+// the test.
+func _test_dial(t *testing.T, settings Settings) (c *Cluster) {
+	c, err := Dial(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c
+}
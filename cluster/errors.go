@@ -0,0 +1,13 @@
+package cluster
+
+const (
+	ErrAddressIsNotSet      = "address is not set"
+	ErrNodeIsAlreadyAdded   = "node is already added: %v"
+	ErrNodeIsNotFound       = "node is not found: %v"
+	ErrNoNodeIsAvailable    = "no node is available"
+	ErrVirtualNodesNegative = "number of virtual nodes is negative"
+	ErrRetryIntervalIsBad   = "retry interval is negative"
+	ErrNodeIsFailed         = "node is failed: %v: %w"
+	ErrNodeReportsError     = "node reports an error: %v: %w"
+	ErrClusterIsClosed      = "cluster is closed"
+)
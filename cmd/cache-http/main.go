@@ -0,0 +1,71 @@
+// Command cache-http serves a cache over the HTTP interface of the 'rest'
+// package.
+//
+// Usage:
+//
+//	cache-http [-addr :8080] [-size 0] [-volume 67108864] [-ttl 3600] [-max-body-size 16777216]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/rest"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "TCP address of the HTTP server")
+	sizeLimit := flag.Int("size", 0, "maximum number of records, zero disables the limit")
+	volumeLimit := flag.Int("volume", 64*1024*1024, "maximum total volume of records in bytes, zero disables the limit")
+	recordTtl := flag.Uint("ttl", 3600, "records' TTL in seconds")
+	maxBodySize := flag.Int64("max-body-size", rest.DefaultMaxBodySize, "maximum size of a record's data in bytes")
+	flag.Parse()
+
+	c, err := vl.New(
+		vl.WithSizeLimit[string, []byte](*sizeLimit),
+		vl.WithVolumeLimit[string, []byte](*volumeLimit),
+		vl.WithRecordTtl[string, []byte](*recordTtl),
+	)
+	mustBeNoError(err)
+
+	handler, err := rest.NewHandler(c, rest.Settings{MaxBodySize: *maxBodySize})
+	mustBeNoError(err)
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: time.Second * 10,
+	}
+
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+		mustBeNoError(server.Shutdown(ctx))
+	}()
+
+	log.Printf("serving on %v", *addr)
+	err = server.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+
+	mustBeNoError(c.Close())
+}
+
+func mustBeNoError(err error) {
+	if err != nil {
+		log.Fatal(err)
+	}
+}
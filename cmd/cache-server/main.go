@@ -0,0 +1,120 @@
+// Command cache-server serves caches over the memcached text protocol and,
+// optionally, over the Redis protocol. Each protocol has its own caches, and
+// each logical database of the Redis protocol is a separate cache with the
+// same limits.
+//
+// Usage:
+//
+//	cache-server [-addr :11211] [-resp-addr :6379] [-resp-databases 16]
+//	             [-size 0] [-volume 67108864] [-ttl 3600] [-max-value-size 1048576]
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/memcached"
+	"github.com/vault-thirteen/Cache/resp"
+)
+
+// server is a protocol server of the command.
+type server interface {
+	ListenAndServe(address string) (err error)
+	Close() (err error)
+}
+
+func main() {
+	addr := flag.String("addr", ":11211", "TCP address of the memcached protocol")
+	respAddr := flag.String("resp-addr", "", "TCP address of the Redis protocol, empty disables the protocol")
+	respDatabases := flag.Int("resp-databases", 16, "number of logical databases of the Redis protocol")
+	sizeLimit := flag.Int("size", 0, "maximum number of records of a cache, zero disables the limit")
+	volumeLimit := flag.Int("volume", 64*1024*1024, "maximum total volume of records of a cache in bytes, zero disables the limit")
+	recordTtl := flag.Uint("ttl", 3600, "records' TTL in seconds")
+	maxValueSize := flag.Int("max-value-size", memcached.DefaultMaxValueSize, "maximum size of a value in bytes")
+	flag.Parse()
+
+	// Servers are keyed by their addresses.
+	if *respAddr == *addr {
+		log.Fatalf("addresses of the protocols are equal: %v", *addr)
+	}
+
+	var caches []*vl.Cache[string, []byte]
+	newCache := func() *vl.Cache[string, []byte] {
+		c, err := vl.New(
+			vl.WithSizeLimit[string, []byte](*sizeLimit),
+			vl.WithVolumeLimit[string, []byte](*volumeLimit),
+			vl.WithRecordTtl[string, []byte](*recordTtl),
+		)
+		mustBeNoError(err)
+		caches = append(caches, c)
+
+		return c
+	}
+	onError := func(err error) {
+		log.Println(err)
+	}
+
+	servers := map[string]server{}
+
+	mcServer, err := memcached.NewServer(newCache(), memcached.Settings{
+		MaxValueSize: *maxValueSize,
+		OnError:      onError,
+	})
+	mustBeNoError(err)
+	servers[*addr] = mcServer
+
+	if len(*respAddr) > 0 {
+		var databases []*vl.Cache[string, []byte]
+		for i := 0; i < *respDatabases; i++ {
+			databases = append(databases, newCache())
+		}
+
+		var respServer *resp.Server
+		respServer, err = resp.NewServer(databases, resp.Settings{
+			MaxBulkSize: *maxValueSize,
+			OnError:     onError,
+		})
+		mustBeNoError(err)
+		servers[*respAddr] = respServer
+	}
+
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+
+		for _, s := range servers {
+			mustBeNoError(s.Close())
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for address, s := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			log.Printf("serving on %v", address)
+			err := s.ListenAndServe(address)
+			if (err.Error() != memcached.ErrServerIsClosed) && (err.Error() != resp.ErrServerIsClosed) {
+				log.Fatal(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, c := range caches {
+		mustBeNoError(c.Close())
+	}
+}
+
+func mustBeNoError(err error) {
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,1086 @@
+// Package core contains the cache which is common for both cache variants.
+// Variants differ only in the measurement of records' volume.
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Cache is cache. Surprisingly, but it is true.
+type Cache[U comparable, D any] struct {
+	top          *Record[U, D]
+	bottom       *Record[U, D]
+	size         int
+	sizeLimit    int
+	volume       int
+	volumeLimit  int
+	recordsByUid map[U]*Record[U, D]
+	recordTtl    uint
+	lock         *sync.RWMutex
+	storeLock    *sync.Mutex
+	loads        map[U]*loadCall[D]
+	stats        Stats
+
+	instrumentation Instrumentation[U]
+	logger          *slog.Logger
+	logSampler      *logSampler
+	clock           Clock
+	onEviction      func(uid U, data D, reason EvictionReason)
+	store           Store[U, D]
+	writeBehind     *writeBehind[U, D]
+	onStoreError    func(uid U, err error)
+
+	loader             Loader[U, D]
+	refreshAheadFactor float64
+	gracePeriod        uint
+	staleIfError       bool
+	measurement        Measurement[D]
+}
+
+// New creates a new cache configured by the options.
+func New[U comparable, D any](opts ...Option[U, D]) (cache *Cache[U, D], err error) {
+	var cfg Config[U, D]
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return NewFromConfig(cfg)
+}
+
+// NewFromConfig creates a new cache using the configuration.
+func NewFromConfig[U comparable, D any](cfg Config[U, D]) (cache *Cache[U, D], err error) {
+	err = cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	cache = new(Cache[U, D])
+	cache.measurement = cfg.Measurement
+	cache.initialize(cfg.SizeLimit, cfg.VolumeLimit, cfg.RecordTtl)
+	cache.clock = cfg.Clock
+	cache.onEviction = cfg.OnEviction
+	cache.instrumentation = cfg.Instrumentation
+	cache.logger = cfg.Logger
+	cache.logSampler = newLogSampler(cfg.LogSampling)
+	cache.store = cfg.Store
+	cache.onStoreError = cfg.OnStoreError
+	cache.loader = cfg.Loader
+	cache.refreshAheadFactor = cfg.RefreshAheadFactor
+	cache.gracePeriod = cfg.GracePeriod
+	cache.staleIfError = cfg.StaleIfError
+	if (cfg.Store != nil) && (cfg.WriteMode == WriteModeBehind) {
+		cache.writeBehind = newWriteBehind(cfg.Store, cfg.WriteBehind, cfg.OnStoreError)
+	}
+
+	return cache, nil
+}
+
+func (c *Cache[U, D]) initialize(sizeLimit int, volumeLimit int, recordTtl uint) {
+	c.top = nil
+	c.bottom = nil
+	c.size = 0
+	c.sizeLimit = sizeLimit
+	c.volume = 0
+	c.volumeLimit = volumeLimit
+	c.recordsByUid = make(map[U]*Record[U, D])
+	c.recordTtl = recordTtl
+	c.lock = new(sync.RWMutex)
+	c.storeLock = new(sync.Mutex)
+	c.loads = make(map[U]*loadCall[D])
+	c.stats = Stats{
+		Evictions: make(map[EvictionReason]uint64),
+	}
+}
+
+// now returns the current time of the cache's clock in seconds. A cache
+// without a clock, e.g. a nil cache of a detached record, uses system time.
+func (c *Cache[U, D]) now() uint {
+	if (c == nil) || (c.clock == nil) {
+		return uint(time.Now().Unix())
+	}
+
+	return uint(c.clock.Now().Unix())
+}
+
+// measure returns the volume of the data. A cache without a measurement, e.g.
+// a nil cache of a detached record, does not measure data.
+func (c *Cache[U, D]) measure(data D) (volume int, err error) {
+	if (c == nil) || (c.measurement == nil) {
+		return 0, nil
+	}
+
+	return c.measurement.Measure(data)
+}
+
+func (c *Cache[U, D]) hasLimitedSize() bool {
+	return c.sizeLimit > 0
+}
+
+func (c *Cache[U, D]) isEmpty() bool {
+	return c.size == 0
+}
+
+func (c *Cache[U, D]) isNotEmpty() bool {
+	return c.size > 0
+}
+
+func (c *Cache[U, D]) hasLimitedVolume() bool {
+	return c.volumeLimit > 0
+}
+
+func (c *Cache[U, D]) linkNewTopRecord(rec *Record[U, D]) {
+	if c.isNotEmpty() {
+		rec.lowerRecord = c.top
+		c.top.upperRecord = rec
+		c.top = rec
+	} else {
+		c.top = rec
+		c.bottom = rec
+	}
+
+	c.size++
+	c.volume += rec.volume
+	c.recordsByUid[rec.uid] = rec
+}
+
+func (c *Cache[U, D]) unlinkBottomRecord() (rec *Record[U, D], err error) {
+	rec = c.bottom
+
+	if c.size > 1 {
+		c.bottom = rec.upperRecord
+		c.bottom.lowerRecord = nil
+	} else if c.size == 1 {
+		c.top = nil
+		c.bottom = nil
+	} else { // size = 0.
+		return nil, errors.New(ErrBottomRecordDoesNotExist)
+	}
+
+	rec.upperRecord = nil
+	rec.lowerRecord = nil
+
+	c.size--
+	c.volume -= rec.volume
+	delete(c.recordsByUid, rec.uid)
+
+	return rec, nil
+}
+
+// evictBottomRecord removes the bottom record from the cache and registers
+// the eviction in the statistics.
+func (c *Cache[U, D]) evictBottomRecord(reason EvictionReason) (err error) {
+	var rec *Record[U, D]
+	rec, err = c.unlinkBottomRecord()
+	if err != nil {
+		return err
+	}
+
+	c.stats.Evictions[reason]++
+	c.logRecordEvent(slog.LevelDebug, logMsgRecordIsEvicted, rec, string(reason))
+	c.notifyEviction(rec, reason)
+
+	return nil
+}
+
+// removeOutdatedRecord removes an expired record from the cache and
+// registers the eviction in the statistics.
+func (c *Cache[U, D]) removeOutdatedRecord(rec *Record[U, D]) {
+	rec.unlink()
+	c.stats.Evictions[EvictionReasonExpired]++
+	c.logRecordEvent(slog.LevelDebug, logMsgRecordIsExpired, rec, string(EvictionReasonExpired))
+	c.notifyEviction(rec, EvictionReasonExpired)
+}
+
+// expireRecord removes an outdated record from the cache unless the record is
+// in its grace period.
+func (c *Cache[U, D]) expireRecord(rec *Record[U, D]) {
+	if rec.isServable() {
+		return
+	}
+
+	c.removeOutdatedRecord(rec)
+}
+
+// notifyEviction calls the eviction callback if it is set. Eviction of a
+// dirty record, i.e. a record whose data is not written to the store yet,
+// wakes the write-behind flusher up.
+func (c *Cache[U, D]) notifyEviction(rec *Record[U, D], reason EvictionReason) {
+	if (c.writeBehind != nil) && c.writeBehind.isDirty(rec.uid) {
+		c.writeBehind.wakeUp()
+	}
+
+	if c.onEviction == nil {
+		return
+	}
+
+	c.onEviction(rec.uid, rec.data, reason)
+}
+
+func (c *Cache[U, D]) getFreeVolume() int {
+	return c.volumeLimit - c.volume
+}
+
+// GetVolume returns current volume of the cache.
+func (c *Cache[U, D]) GetVolume() (usedVolume int, volumeLimit int) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.volume, c.volumeLimit
+}
+
+// GetSize returns current size of the cache.
+func (c *Cache[U, D]) GetSize() (size int, sizeLimit int) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.size, c.sizeLimit
+}
+
+// GetStats returns a copy of the cache's statistics.
+func (c *Cache[U, D]) GetStats() (stats Stats) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	stats = c.stats
+	stats.Evictions = make(map[EvictionReason]uint64, len(c.stats.Evictions))
+	for reason, count := range c.stats.Evictions {
+		stats.Evictions[reason] = count
+	}
+
+	return stats
+}
+
+// GetTtl returns the records' TTL in seconds.
+func (c *Cache[U, D]) GetTtl() (recordTtl uint) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.recordTtl
+}
+
+// PeekTopUids returns UIDs of at most n records starting from the top of the
+// cache, i.e. the most recently used records go first. The order of records,
+// their LATs and the statistics are not changed.
+func (c *Cache[U, D]) PeekTopUids(n int) (uids []U) {
+	if n <= 0 {
+		return []U{}
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	uids = make([]U, 0, min(n, c.size))
+	for rec := c.top; (rec != nil) && (len(uids) < n); rec = rec.lowerRecord {
+		uids = append(uids, rec.uid)
+	}
+
+	return uids
+}
+
+// PeekBottomUids returns UIDs of at most n records starting from the bottom
+// of the cache, i.e. the records to be evicted next go first. The order of
+// records, their LATs and the statistics are not changed.
+func (c *Cache[U, D]) PeekBottomUids(n int) (uids []U) {
+	if n <= 0 {
+		return []U{}
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	uids = make([]U, 0, min(n, c.size))
+	for rec := c.bottom; (rec != nil) && (len(uids) < n); rec = rec.upperRecord {
+		uids = append(uids, rec.uid)
+	}
+
+	return uids
+}
+
+// WalkRecords calls the function for each alive record from the bottom of the
+// cache to its top, i.e. starting with the least recently used record, and
+// passes the time of the last access to the record. Records are not moved and
+// are not touched, statistics are not affected. The walk stops when the
+// function returns false. The function must not use the cache.
+func (c *Cache[U, D]) WalkRecords(fn func(uid U, data D, lastAccessTime time.Time) (next bool)) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	for rec := c.bottom; rec != nil; rec = rec.upperRecord {
+		if !rec.isAlive() {
+			continue
+		}
+
+		if !fn(rec.uid, rec.data, time.Unix(int64(rec.lastAccessTime), 0)) {
+			return
+		}
+	}
+}
+
+// RecordExists checks whether the specified record exists or not. An
+// outdated record is treated as absent, and it is removed from the cache
+// unless it is in its grace period.
+func (c *Cache[U, D]) RecordExists(uid U) (recordExists bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var rec *Record[U, D]
+	rec, recordExists = c.recordsByUid[uid]
+	if !recordExists {
+		return false
+	}
+
+	if !rec.isAlive() {
+		c.expireRecord(rec)
+		return false
+	}
+
+	return true
+}
+
+// PeekRecord reads data of an alive record like the RecordExists method does,
+// i.e. without moving the record to the top of the cache and without
+// prolonging its life. Statistics and instrumentation are not affected.
+func (c *Cache[U, D]) PeekRecord(uid U) (data D, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	rec, recExists := c.recordsByUid[uid]
+	if !recExists {
+		return data, fmt.Errorf(ErrRecordIsNotFound, uid)
+	}
+
+	if !rec.isAlive() {
+		c.expireRecord(rec)
+		return data, fmt.Errorf(ErrRecordIsOutdated, uid)
+	}
+
+	return rec.data, nil
+}
+
+// AddRecord either adds a new record to the top of the cache or moves an
+// existing record to the top of the cache. If the record already exists, its
+// data and LAT are updated.
+func (c *Cache[U, D]) AddRecord(uid U, data D) (err error) {
+	return c.writeRecord(context.Background(), uid, data)
+}
+
+// AddRecordCtx is a variant of the AddRecord method which stops waiting for
+// the cache's lock when the context is done.
+func (c *Cache[U, D]) AddRecordCtx(ctx context.Context, uid U, data D) (err error) {
+	return c.writeRecord(ctx, uid, data)
+}
+
+// isWriteThrough checks whether records are written to the store
+// synchronously.
+func (c *Cache[U, D]) isWriteThrough() bool {
+	return (c.store != nil) && (c.writeBehind == nil)
+}
+
+// writeRecord locks the cache, adds a record to it and writes the record to
+// the store, if the store is set. In the write-through mode, the record is
+// written to the store first, without locking the cache, and the cache is not
+// changed if the store fails. Writes of the store and of the cache are
+// serialized by the store lock, so that they are applied in the same order.
+// In the write-behind mode, the record is queued for an asynchronous write.
+// In both modes, a record which is rejected by the cache is still written to
+// the store.
+func (c *Cache[U, D]) writeRecord(ctx context.Context, uid U, data D) (err error) {
+	if c.isWriteThrough() {
+		err = lockWithContext(ctx, c.storeLock)
+		if err != nil {
+			return err
+		}
+		defer c.storeLock.Unlock()
+
+		err = c.store.Store(ctx, uid, data)
+		if err != nil {
+			return err
+		}
+
+		// The store is changed, so the cache must be changed too, even if
+		// the context is done.
+		c.lock.Lock()
+	} else {
+		err = c.lockWithContext(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	defer c.lock.Unlock()
+
+	if c.writeBehind != nil {
+		c.writeBehind.put(uid, data)
+	}
+
+	return c.observeAddRecord(ctx, uid, data)
+}
+
+// deleteRecord locks the cache and removes a record from the cache and from
+// the store, if the store is set. The record is removed from the store even
+// if it is absent in the cache, and it is removed from the cache even if the
+// store fails. In the write-through mode, the store is changed without
+// locking the cache.
+func (c *Cache[U, D]) deleteRecord(ctx context.Context, uid U) (recExists bool, err error) {
+	if c.isWriteThrough() {
+		c.storeLock.Lock()
+		defer c.storeLock.Unlock()
+
+		err = c.store.Delete(ctx, uid)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.writeBehind != nil {
+		c.writeBehind.delete(uid)
+	}
+
+	return c.observeRemoveRecord(ctx, uid), err
+}
+
+func (c *Cache[U, D]) addRecord(uid U, data D) (err error) {
+	var rec *Record[U, D]
+	var recExists bool
+	rec, recExists = c.recordsByUid[uid]
+	if recExists {
+		// If the UID is already used,
+		// we update data of the record having this UID.
+		rec.moveToTop()
+		err = rec.update(data)
+		if err != nil {
+			return err
+		}
+	} else {
+		// UID is not found,
+		// we add a new record.
+		rec, err = NewRecord(c, uid, data)
+		if err != nil {
+			return err
+		}
+
+		if c.hasLimitedVolume() && (rec.volume > c.volumeLimit) {
+			c.logRecordEvent(slog.LevelInfo, logMsgRecordIsRejected, rec, ErrRecordIsTooBig)
+			return errors.New(ErrRecordIsTooBig)
+		}
+
+		c.linkNewTopRecord(rec)
+	}
+
+	// Now we need (or do not need) to apply various constraints.
+	_, err = c.applyLimits()
+	return err
+}
+
+// applyLimits evicts records from the bottom of the cache until the size and
+// volume limits hold.
+func (c *Cache[U, D]) applyLimits() (droppedCount int, err error) {
+	if c.hasLimitedSize() {
+		for c.size > c.sizeLimit {
+			err = c.evictBottomRecord(EvictionReasonSize)
+			if err != nil {
+				return droppedCount, err
+			}
+			droppedCount++
+		}
+	}
+
+	if c.hasLimitedVolume() {
+		for c.getFreeVolume() < 0 {
+			err = c.evictBottomRecord(EvictionReasonVolume)
+			if err != nil {
+				return droppedCount, err
+			}
+			droppedCount++
+		}
+	}
+
+	return droppedCount, nil
+}
+
+// applyTtl removes outdated records which are not in their grace period from
+// the bottom of the cache. As records are touched when they are moved to the
+// top, the LATs do not increase from the top to the bottom of the cache, so
+// the first alive record stops the process.
+func (c *Cache[U, D]) applyTtl() (droppedCount int) {
+	for c.isNotEmpty() && !c.bottom.isServable() {
+		c.removeOutdatedRecord(c.bottom)
+		droppedCount++
+	}
+
+	return droppedCount
+}
+
+// GetRecord reads a record from the cache. An outdated record is not
+// returned, and it is removed from the cache unless it is in its grace
+// period.
+func (c *Cache[U, D]) GetRecord(uid U) (data D, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.observeGetRecord(context.Background(), uid)
+}
+
+// GetRecordCtx is a variant of the GetRecord method which stops waiting for
+// the cache's lock when the context is done.
+func (c *Cache[U, D]) GetRecordCtx(ctx context.Context, uid U) (data D, err error) {
+	err = c.lockWithContext(ctx)
+	if err != nil {
+		return data, err
+	}
+	defer c.lock.Unlock()
+
+	return c.observeGetRecord(ctx, uid)
+}
+
+func (c *Cache[U, D]) getRecord(uid U) (data D, err error) {
+	var rec *Record[U, D]
+	var ok bool
+	rec, ok = c.recordsByUid[uid]
+	if !ok {
+		c.stats.Misses++
+		return data, fmt.Errorf(ErrRecordIsNotFound, uid)
+	}
+
+	if !rec.isAlive() {
+		c.expireRecord(rec)
+		c.stats.Misses++
+		c.stats.ExpiredOnRead++
+		return data, fmt.Errorf(ErrRecordIsOutdated, uid)
+	}
+
+	rec.moveToTop()
+	rec.touch()
+	c.stats.Hits++
+	c.refreshAhead(rec, c.defaultLoader())
+
+	return rec.data, nil
+}
+
+// GetOrLoadCtx reads a record from the cache. If the record is not found or
+// is outdated, it is loaded using the loader and is then added to the cache.
+// Concurrent requests for the same UID share a single load. The loader
+// receives the context of the request which has started the load without its
+// cancellation, so that a cancelled request does not fail other requests
+// waiting for the same load. Waiting for the lock or for an in-flight load is
+// stopped when the context of the request is done.
+// If the loader is not set, the default loader is used. If stale-if-error is
+// enabled and the loader fails, data of an outdated record in its grace
+// period is returned instead of the error.
+func (c *Cache[U, D]) GetOrLoadCtx(ctx context.Context, uid U, loader Loader[U, D]) (data D, err error) {
+	if loader == nil {
+		loader = c.defaultLoader()
+	}
+	if loader == nil {
+		return data, errors.New(ErrLoaderIsNotSet)
+	}
+
+	err = c.lockWithContext(ctx)
+	if err != nil {
+		return data, err
+	}
+
+	var rec *Record[U, D]
+	var recExists bool
+	var staleData D
+	var hasStaleData bool
+	rec, recExists = c.recordsByUid[uid]
+	if recExists {
+		if rec.isAlive() {
+			rec.moveToTop()
+			rec.touch()
+			c.stats.Hits++
+			c.refreshAhead(rec, loader)
+			data = rec.data
+			c.lock.Unlock()
+			return data, nil
+		}
+
+		if c.staleIfError && rec.isServable() {
+			staleData, hasStaleData = rec.data, true
+		}
+		c.expireRecord(rec)
+		c.stats.ExpiredOnRead++
+	}
+	c.stats.Misses++
+
+	var call *loadCall[D]
+	var isInFlight bool
+	call, isInFlight = c.loads[uid]
+	if !isInFlight {
+		call = newLoadCall[D]()
+		c.loads[uid] = call
+		go c.load(context.WithoutCancel(ctx), uid, loader, call)
+	}
+	call.isAwaited = true
+	c.lock.Unlock()
+
+	data, err = call.wait(ctx)
+	if (err != nil) && hasStaleData && (ctx.Err() == nil) {
+		c.lock.Lock()
+		c.stats.StaleHits++
+		c.lock.Unlock()
+		return staleData, nil
+	}
+
+	return data, err
+}
+
+// GetRecordAllowStale reads a record from the cache. Unlike the GetRecord
+// method, it returns data of an outdated record which is in its grace period,
+// flagging the data as stale. A stale record is not moved to the top of the
+// cache and is not touched, and it is reloaded in background by the default
+// loader, if the loader is set.
+func (c *Cache[U, D]) GetRecordAllowStale(uid U) (data D, freshness Freshness, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.instrumentation == nil {
+		return c.getRecordAllowStale(uid)
+	}
+
+	ctx := c.instrumentation.StartOperation(context.Background(), OperationGetRecord, uid)
+	_, recExists := c.recordsByUid[uid]
+
+	data, freshness, err = c.getRecordAllowStale(uid)
+
+	result := OperationResult{Err: err}
+	if err == nil {
+		if freshness == FreshnessStale {
+			result.Outcome = OutcomeStale
+		} else {
+			result.Outcome = OutcomeHit
+		}
+	} else if recExists {
+		result.Outcome = OutcomeExpired
+	} else {
+		result.Outcome = OutcomeMiss
+	}
+	c.instrumentation.EndOperation(ctx, OperationGetRecord, uid, result)
+
+	return data, freshness, err
+}
+
+func (c *Cache[U, D]) getRecordAllowStale(uid U) (data D, freshness Freshness, err error) {
+	rec, recExists := c.recordsByUid[uid]
+	if recExists && !rec.isAlive() && rec.isServable() {
+		c.stats.StaleHits++
+		c.startRefresh(rec, c.defaultLoader())
+		return rec.data, FreshnessStale, nil
+	}
+
+	data, err = c.getRecord(uid)
+	if err != nil {
+		return data, freshness, err
+	}
+
+	return data, FreshnessFresh, nil
+}
+
+// load runs the loader and stores its result in the cache. If a record with
+// the same UID has been added while the loader was running, the added record
+// is kept and is not overwritten.
+func (c *Cache[U, D]) load(ctx context.Context, uid U, loader Loader[U, D], call *loadCall[D]) {
+	data, err := loader(ctx, uid)
+
+	c.lock.Lock()
+	delete(c.loads, uid)
+	if err == nil {
+		rec, recExists := c.recordsByUid[uid]
+		if recExists && rec.isAlive() {
+			data = rec.data
+		} else {
+			err = c.addRecord(uid, data)
+		}
+	}
+	c.lock.Unlock()
+
+	call.finish(data, err)
+}
+
+// defaultLoader returns the loader of the configuration or, if it is not set,
+// a loader of the store. If neither is set, nil is returned.
+func (c *Cache[U, D]) defaultLoader() (loader Loader[U, D]) {
+	if c.loader != nil {
+		return c.loader
+	}
+
+	if c.store != nil {
+		return c.loadFromStore
+	}
+
+	return nil
+}
+
+// refreshAhead starts a background reload of the record if its data is
+// getting old. The cache must be locked for writing.
+func (c *Cache[U, D]) refreshAhead(rec *Record[U, D], loader Loader[U, D]) {
+	if !rec.needsRefresh() {
+		return
+	}
+
+	c.startRefresh(rec, loader)
+}
+
+// startRefresh starts a background reload of the record. A record is not
+// reloaded when a load of its UID is already in flight or when the loader is
+// not set. The cache must be locked for writing.
+func (c *Cache[U, D]) startRefresh(rec *Record[U, D], loader Loader[U, D]) {
+	if loader == nil {
+		return
+	}
+
+	_, isInFlight := c.loads[rec.uid]
+	if isInFlight {
+		return
+	}
+
+	call := newLoadCall[D]()
+	c.loads[rec.uid] = call
+	go c.refresh(rec, loader, call)
+}
+
+// refresh runs the loader and replaces data of the record with the reloaded
+// data. The record is moved to the top of the cache, as its LAT is updated
+// and the LATs must not increase from the top to the bottom. If the record
+// has been removed or replaced while the loader was running, e.g. by the
+// RemoveRecord or the Clear method, the reloaded data is discarded, unless a
+// request of the GetOrLoadCtx method waits for the reload; then the data is
+// stored as a loaded one. If the loader fails, the record is not changed and
+// expires as usual, which lets a stale record be served until its grace
+// period ends.
+func (c *Cache[U, D]) refresh(rec *Record[U, D], loader Loader[U, D], call *loadCall[D]) {
+	uid := rec.uid
+	data, err := loader(context.Background(), uid)
+
+	c.lock.Lock()
+	delete(c.loads, uid)
+	current, recExists := c.recordsByUid[uid]
+	isSameRecord := recExists && (current == rec)
+	if err != nil {
+		if isSameRecord {
+			c.logRecordEvent(slog.LevelWarn, logMsgRecordIsNotRefreshed, rec, err.Error())
+		}
+	} else if isSameRecord {
+		rec.moveToTop()
+		err = rec.update(data)
+		if err == nil {
+			_, err = c.applyLimits()
+		}
+	} else if call.isAwaited {
+		if recExists && current.isAlive() {
+			data = current.data
+		} else {
+			err = c.addRecord(uid, data)
+		}
+	}
+	c.lock.Unlock()
+
+	call.finish(data, err)
+}
+
+// lockWithContext acquires the cache's lock for writing. If the context is
+// done before the lock is acquired, an error is returned and the lock is
+// released as soon as it is acquired by the abandoned attempt.
+func (c *Cache[U, D]) lockWithContext(ctx context.Context) (err error) {
+	return lockWithContext(ctx, c.lock)
+}
+
+// RemoveRecord safely removes a record from the cache. If the store is set,
+// the record is also removed from the store; an error of the store is passed
+// to the store error callback.
+func (c *Cache[U, D]) RemoveRecord(uid U) {
+	_, err := c.deleteRecord(context.Background(), uid)
+	if (err != nil) && (c.onStoreError != nil) {
+		c.onStoreError(uid, err)
+	}
+
+	return
+}
+
+// RemoveExistingRecord removes an existing record from the cache. If the
+// store is set, the record is also removed from the store.
+func (c *Cache[U, D]) RemoveExistingRecord(uid U) (err error) {
+	var recExists bool
+	recExists, err = c.deleteRecord(context.Background(), uid)
+	if err != nil {
+		return err
+	}
+
+	if !recExists {
+		return fmt.Errorf(ErrRecordIsNotFound, uid)
+	}
+
+	return nil
+}
+
+func (c *Cache[U, D]) removeRecord(uid U) (recExists bool) {
+	var rec *Record[U, D]
+	rec, recExists = c.recordsByUid[uid]
+	if !recExists {
+		return false
+	}
+
+	rec.unlink()
+
+	return true
+}
+
+// Clear removes all records from the cache.
+func (c *Cache[U, D]) Clear() (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.observeClear(context.Background())
+}
+
+func (c *Cache[U, D]) clear() (err error) {
+	for {
+		if c.isEmpty() {
+			break
+		}
+
+		_, err = c.unlinkBottomRecord()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetInstrumentation sets the instrumentation which observes operations of
+// the cache. Nil disables the instrumentation. Hooks of the instrumentation
+// are called while the cache is locked, so they must not use the cache.
+func (c *Cache[U, D]) SetInstrumentation(instrumentation Instrumentation[U]) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.instrumentation = instrumentation
+}
+
+func (c *Cache[U, D]) observeAddRecord(ctx context.Context, uid U, data D) (err error) {
+	if c.instrumentation == nil {
+		return c.addRecord(uid, data)
+	}
+
+	ctx = c.instrumentation.StartOperation(ctx, OperationAddRecord, uid)
+	_, recExists := c.recordsByUid[uid]
+	oldVolume := c.volume
+
+	err = c.addRecord(uid, data)
+
+	result := OperationResult{Err: err}
+	result.VolumeDelta = c.volume - oldVolume
+	if err != nil {
+		result.Outcome = OutcomeFailed
+	} else if recExists {
+		result.Outcome = OutcomeUpdated
+	} else {
+		result.Outcome = OutcomeAdded
+	}
+	c.instrumentation.EndOperation(ctx, OperationAddRecord, uid, result)
+
+	return err
+}
+
+func (c *Cache[U, D]) observeGetRecord(ctx context.Context, uid U) (data D, err error) {
+	if c.instrumentation == nil {
+		return c.getRecord(uid)
+	}
+
+	ctx = c.instrumentation.StartOperation(ctx, OperationGetRecord, uid)
+	_, recExists := c.recordsByUid[uid]
+	oldVolume := c.volume
+
+	data, err = c.getRecord(uid)
+
+	result := OperationResult{Err: err}
+	result.VolumeDelta = c.volume - oldVolume
+	if err == nil {
+		result.Outcome = OutcomeHit
+	} else if recExists {
+		result.Outcome = OutcomeExpired
+	} else {
+		result.Outcome = OutcomeMiss
+	}
+	c.instrumentation.EndOperation(ctx, OperationGetRecord, uid, result)
+
+	return data, err
+}
+
+func (c *Cache[U, D]) observeRemoveRecord(ctx context.Context, uid U) (recExists bool) {
+	if c.instrumentation == nil {
+		return c.removeRecord(uid)
+	}
+
+	ctx = c.instrumentation.StartOperation(ctx, OperationRemoveRecord, uid)
+	oldVolume := c.volume
+
+	recExists = c.removeRecord(uid)
+
+	var result OperationResult
+	result.VolumeDelta = c.volume - oldVolume
+	if recExists {
+		result.Outcome = OutcomeRemoved
+	} else {
+		result.Outcome = OutcomeMiss
+	}
+	c.instrumentation.EndOperation(ctx, OperationRemoveRecord, uid, result)
+
+	return recExists
+}
+
+func (c *Cache[U, D]) observeClear(ctx context.Context) (err error) {
+	if c.instrumentation == nil {
+		return c.clear()
+	}
+
+	var uid U
+	ctx = c.instrumentation.StartOperation(ctx, OperationClear, uid)
+	oldVolume := c.volume
+
+	err = c.clear()
+
+	result := OperationResult{Err: err}
+	result.VolumeDelta = c.volume - oldVolume
+	if err != nil {
+		result.Outcome = OutcomeFailed
+	} else {
+		result.Outcome = OutcomeRemoved
+	}
+	c.instrumentation.EndOperation(ctx, OperationClear, uid, result)
+
+	return err
+}
+
+// SetLogger sets the logger which receives events about evictions, rejections
+// of records, and expirations of records. Nil disables logging. Events of each
+// kind are sampled according to the sampling settings, a zero value of the
+// settings disables sampling.
+func (c *Cache[U, D]) SetLogger(logger *slog.Logger, sampling LogSampling) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.logger = logger
+	c.logSampler = newLogSampler(sampling)
+}
+
+// logRecordEvent writes an event about the record into the log. The cache
+// must be locked for writing.
+func (c *Cache[U, D]) logRecordEvent(level slog.Level, msg string, rec *Record[U, D], reason string) {
+	if c.logger == nil {
+		return
+	}
+
+	ctx := context.Background()
+	if !c.logger.Enabled(ctx, level) || !c.logSampler.allow(msg) {
+		return
+	}
+
+	c.logger.LogAttrs(ctx, level, msg,
+		slog.Any("uid", rec.uid),
+		slog.String("reason", reason),
+		slog.Int("recordVolume", rec.volume),
+		slog.Group("cache",
+			slog.Int("size", c.size),
+			slog.Int("sizeLimit", c.sizeLimit),
+			slog.Int("volume", c.volume),
+			slog.Int("volumeLimit", c.volumeLimit),
+		),
+	)
+}
+
+// SetSizeLimit changes the size limit at runtime and immediately evicts
+// records from the bottom of the cache until the new limit holds. Zero
+// disables the limit. The number of evicted records is returned.
+func (c *Cache[U, D]) SetSizeLimit(sizeLimit int) (droppedCount int, err error) {
+	if sizeLimit < 0 {
+		return 0, errors.New(ErrSizeLimitIsNegative)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.sizeLimit = sizeLimit
+
+	return c.applyLimits()
+}
+
+// SetVolumeLimit changes the volume limit at runtime and immediately evicts
+// records from the bottom of the cache until the new limit holds. Zero
+// disables the limit. The number of evicted records is returned.
+func (c *Cache[U, D]) SetVolumeLimit(volumeLimit int) (droppedCount int, err error) {
+	if volumeLimit < 0 {
+		return 0, errors.New(ErrVolumeLimitIsNegative)
+	}
+	if (volumeLimit > 0) && (c.measurement == nil) {
+		return 0, errors.New(ErrMeasurementIsNotSet)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.volumeLimit = volumeLimit
+
+	return c.applyLimits()
+}
+
+// SetTtl changes the records' TTL at runtime and immediately removes records
+// which are outdated according to the new TTL. The number of removed records
+// is returned.
+func (c *Cache[U, D]) SetTtl(recordTtl uint) (droppedCount int, err error) {
+	if recordTtl == 0 {
+		return 0, errors.New(ErrTtlIsZero)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.recordTtl = recordTtl
+
+	return c.applyTtl(), nil
+}
+
+// loadFromStore loads a record from the store. A record which has a queued
+// write is taken from the queue, so that the store's outdated data is not
+// loaded.
+func (c *Cache[U, D]) loadFromStore(ctx context.Context, uid U) (data D, err error) {
+	if c.writeBehind != nil {
+		pw, isQueued := c.writeBehind.peek(uid)
+		if isQueued {
+			if pw.isDeletion {
+				return data, fmt.Errorf(ErrRecordIsNotFound, uid)
+			}
+			return pw.data, nil
+		}
+	}
+
+	return c.store.Load(ctx, uid)
+}
+
+// Flush writes all the records queued in the write-behind mode to the store.
+// Records which have failed to be written stay in the queue, and their errors
+// are returned. In other modes it does nothing.
+func (c *Cache[U, D]) Flush(ctx context.Context) (err error) {
+	if c.writeBehind == nil {
+		return nil
+	}
+
+	return c.writeBehind.flush(ctx)
+}
+
+// Close stops the write-behind flusher and writes all the queued records to
+// the store. The cache must not be modified after closing. In other modes it
+// does nothing.
+func (c *Cache[U, D]) Close() (err error) {
+	if c.writeBehind == nil {
+		return nil
+	}
+
+	return c.writeBehind.close()
+}
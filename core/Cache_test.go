@@ -0,0 +1,1484 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_initialize(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test.
+	var c = new(Cache[string, string])
+	aTest.MustBeEqual(c.lock, (*sync.RWMutex)(nil))
+	c.initialize(1, 2, 3)
+
+	aTest.MustBeEqual(c.top, (*Record[string, string])(nil))
+	aTest.MustBeEqual(c.bottom, (*Record[string, string])(nil))
+	aTest.MustBeEqual(c.size, 0)
+	aTest.MustBeEqual(c.sizeLimit, 1)
+	aTest.MustBeEqual(c.volume, 0)
+	aTest.MustBeEqual(c.volumeLimit, 2)
+	aTest.MustBeEqual(c.recordTtl, uint(3))
+	aTest.MustBeDifferent(c.lock, (*sync.RWMutex)(nil))
+}
+
+func Test_hasLimitedSize(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+
+	// Test #1.
+	c = _test_new_cache[string, string](0, 0, 60)
+	aTest.MustBeEqual(c.hasLimitedSize(), false)
+
+	// Test #2.
+	c = _test_new_cache[string, string](1, 0, 60)
+	aTest.MustBeEqual(c.hasLimitedSize(), true)
+}
+
+func Test_isEmpty(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+
+	// Test #1.
+	c = _test_new_cache[string, string](0, 0, 60)
+	aTest.MustBeEqual(c.isEmpty(), true)
+
+	// Test #2.
+	c.size++
+	aTest.MustBeEqual(c.isEmpty(), false)
+}
+
+func Test_isNotEmpty(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+
+	// Test #1.
+	c = _test_new_cache[string, string](0, 0, 60)
+	aTest.MustBeEqual(c.isNotEmpty(), false)
+
+	// Test #2.
+	c.size++
+	aTest.MustBeEqual(c.isNotEmpty(), true)
+}
+
+func Test_hasLimitedVolume(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+
+	// Test #1.
+	c = _test_new_cache[string, string](0, 0, 60)
+	aTest.MustBeEqual(c.hasLimitedVolume(), false)
+
+	// Test #2.
+	c = _test_new_cache[string, string](0, 1, 60)
+	aTest.MustBeEqual(c.hasLimitedVolume(), true)
+}
+
+func Test_linkNewTopRecord(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var r *Record[string, string]
+	var ok bool
+
+	// Test. 0R -> 1R.
+	c = _test_prepare_0_cache()
+	r = _test_prepare_record_Q(aTest, c)
+	c.linkNewTopRecord(r) // {} -> Q.
+	ok = _test_ensure_order_1_record(c, "Q", "W")
+	aTest.MustBeEqual(ok, true)
+
+	// Test. 1R -> 2R.
+	c = _test_prepare_A_cache(aTest)
+	r = _test_prepare_record_Q(aTest, c)
+	c.linkNewTopRecord(r) // A -> QA.
+	ok = _test_ensure_order_2_records(c, [2]string{"Q", "A"}, [2]string{"W", "1"})
+	aTest.MustBeEqual(ok, true)
+
+	// Test. 2R -> 3R.
+	c = _test_prepare_AB_cache(aTest)
+	r = _test_prepare_record_Q(aTest, c)
+	c.linkNewTopRecord(r) // AB -> QAB.
+	ok = _test_ensure_order_3_records(c, [3]string{"Q", "A", "B"}, [3]string{"W", "1", "2"})
+	aTest.MustBeEqual(ok, true)
+}
+
+func Test_unlinkBottomRecord(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var r *Record[string, string]
+	var ok bool
+	var err error
+
+	// Test. 0R -> 0R.
+	c = _test_prepare_0_cache()
+	r, err = c.unlinkBottomRecord() // {} -> {}.
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrBottomRecordDoesNotExist)
+	aTest.MustBeEqual(r, (*Record[string, string])(nil))
+
+	// Test. 1R -> 0R.
+	c = _test_prepare_A_cache(aTest)
+	r, err = c.unlinkBottomRecord() // A -> {}.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_0_records(c)
+	aTest.MustBeEqual(ok, true)
+	aTest.MustBeEqual(r.uid, "A")
+
+	// Test. 2R -> 1R.
+	c = _test_prepare_AB_cache(aTest)
+	r, err = c.unlinkBottomRecord() // AB -> A.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_1_record(c, "A", "1")
+	aTest.MustBeEqual(ok, true)
+	aTest.MustBeEqual(r.uid, "B")
+
+	// Test. 3R -> 2R.
+	c = _test_prepare_ABC_cache(aTest)
+	r, err = c.unlinkBottomRecord() // ABC -> AB.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_2_records(c, [2]string{"A", "B"}, [2]string{"1", "2"})
+	aTest.MustBeEqual(ok, true)
+	aTest.MustBeEqual(r.uid, "C")
+}
+
+func Test_getFreeVolume(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+
+	// Test #1.
+	c = _test_new_cache[string, string](0, 10, 60)
+	aTest.MustBeEqual(c.getFreeVolume(), 10)
+
+	// Test #2.
+	c.volume++
+	aTest.MustBeEqual(c.getFreeVolume(), 9)
+}
+
+func Test_GetVolume(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+
+	// Test.
+	c = _test_new_cache[string, string](0, 10, 60)
+	c.volume = 3
+	var usedVolume int
+	var volumeLimit int
+	usedVolume, volumeLimit = c.GetVolume()
+	aTest.MustBeEqual(usedVolume, 3)
+	aTest.MustBeEqual(volumeLimit, 10)
+}
+
+func Test_RecordExists(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var recExists bool
+	var ok bool
+
+	c = _test_prepare_ABC_cache_with_low_ttl(aTest) // ABC.
+
+	// Test #1. Record is not found.
+	recExists = c.RecordExists("Junk")
+	aTest.MustBeEqual(recExists, false)
+	ok = _test_ensure_order_3_records(c, [3]string{"A", "B", "C"}, [3]string{"1", "2", "3"})
+	aTest.MustBeEqual(ok, true)
+
+	// Test #2. Record is outdated.
+	// Wait for the record to become outdated. N.B.: TTL is 3 Seconds.
+	time.Sleep(time.Second * (3 + 1))
+	recExists = c.RecordExists("B") // ABC -> AC.
+	aTest.MustBeEqual(recExists, false)
+	ok = _test_ensure_order_2_records(c, [2]string{"A", "C"}, [2]string{"1", "3"})
+	aTest.MustBeEqual(ok, true)
+
+	c = _test_prepare_ABC_cache_with_low_ttl(aTest) // ABC.
+
+	// Test #3. Record is found.
+	recExists = c.RecordExists("B")
+	aTest.MustBeEqual(recExists, true)
+	ok = _test_ensure_order_3_records(c, [3]string{"A", "B", "C"}, [3]string{"1", "2", "3"})
+	aTest.MustBeEqual(ok, true)
+}
+
+func Test_PeekRecord(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var data string
+	var ok bool
+	var err error
+
+	c = _test_prepare_ABC_cache(aTest) // ABC.
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	c.clock = clock
+	for _, rec := range c.recordsByUid {
+		rec.touch()
+	}
+
+	// Test #1. Record is not found.
+	_, err = c.PeekRecord("Junk")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrRecordIsNotFound, "Junk"))
+
+	// Test #2. Record is found, it is not moved and is not touched.
+	clock.Add(time.Second * 10)
+	data, err = c.PeekRecord("C")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "3")
+	ok = _test_ensure_order_3_records(c, [3]string{"A", "B", "C"}, [3]string{"1", "2", "3"})
+	aTest.MustBeEqual(ok, true)
+	aTest.MustBeEqual(c.recordsByUid["C"].lastAccessTime, uint(1000))
+	aTest.MustBeEqual(c.stats.Hits, uint64(0))
+
+	// Test #3. Record is outdated.
+	clock.Add(time.Second * 50)
+	_, err = c.PeekRecord("B") // ABC -> AC.
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrRecordIsOutdated, "B"))
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+}
+
+func Test_WalkRecords(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var uids []string
+	var times []int64
+
+	c = _test_prepare_ABC_cache(aTest) // ABC.
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	c.clock = clock
+	for _, uid := range c.PeekBottomUids(3) {
+		clock.Add(time.Second)
+		c.recordsByUid[uid].touch()
+	}
+	walk := func(n int) {
+		uids, times = nil, nil
+		c.WalkRecords(func(uid string, data string, lastAccessTime time.Time) bool {
+			uids = append(uids, uid)
+			times = append(times, lastAccessTime.Unix())
+			return len(uids) < n
+		})
+	}
+
+	// Test #1. Records are walked from the bottom.
+	walk(3)
+	aTest.MustBeEqual(uids, c.PeekBottomUids(3))
+	aTest.MustBeEqual(times, []int64{1001, 1002, 1003})
+	aTest.MustBeEqual(c.stats.Hits, uint64(0))
+
+	// Test #2. The walk is stopped.
+	walk(1)
+	aTest.MustBeEqual(uids, c.PeekBottomUids(1))
+
+	// Test #3. Outdated records are skipped.
+	clock.Add(time.Second * 59)
+	walk(3)
+	aTest.MustBeEqual(uids, c.PeekTopUids(1))
+}
+
+func Test_AddRecord(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ok bool
+	var err error
+	var oldLatOfRecordB, newLatOfRecordB uint
+
+	// Preparation for Test #1.
+	c = _test_prepare_AB_cache(aTest) // AB.
+	aTest.MustBeEqual(c.size, 2)
+	aTest.MustBeEqual(c.volume, 2)
+	oldLatOfRecordB = c.recordsByUid["B"].lastAccessTime
+
+	// Test #1. Record already exists.
+	time.Sleep(time.Second * 1)    // LAT++
+	err = c.AddRecord("B", "test") // AB -> BA.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_2_records(c, [2]string{"B", "A"}, [2]string{"test", "1"})
+	aTest.MustBeEqual(ok, true)
+	// Also check new values of size, volume and record's TTL.
+	aTest.MustBeEqual(c.size, 2)
+	aTest.MustBeEqual(c.volume, 1+4)
+	newLatOfRecordB = c.recordsByUid["B"].lastAccessTime
+	aTest.MustBeEqual(newLatOfRecordB-oldLatOfRecordB > 0, true)
+
+	// Preparation for Test #2.
+	c = _test_prepare_AB_cache(aTest) // AB.
+	aTest.MustBeEqual(c.size, 2)
+	aTest.MustBeEqual(c.volume, 2)
+
+	// Test #2. Record is new. Records is bad.
+	err = c.AddRecord("Q", "") // AB -> AB.
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrDataIsEmpty)
+	ok = _test_ensure_order_2_records(c, [2]string{"A", "B"}, [2]string{"1", "2"})
+	aTest.MustBeEqual(ok, true)
+	// Also check new values of size, volume and record's TTL.
+	aTest.MustBeEqual(c.size, 2)
+	aTest.MustBeEqual(c.volume, 2)
+
+	// Preparation for Test #3.
+	c = _test_prepare_AB_cache(aTest) // AB.
+	aTest.MustBeEqual(c.size, 2)
+	aTest.MustBeEqual(c.volume, 2)
+	c.volumeLimit = 3
+
+	// Test #3. Record is new. Record is too big.
+	err = c.AddRecord("Q", "test") // AB -> AB.
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrRecordIsTooBig)
+	ok = _test_ensure_order_2_records(c, [2]string{"A", "B"}, [2]string{"1", "2"})
+	aTest.MustBeEqual(ok, true)
+	// Also check new values of size, volume and record's TTL.
+	aTest.MustBeEqual(c.size, 2)
+	aTest.MustBeEqual(c.volume, 2)
+
+	// Preparation for Test #4.
+	c = _test_prepare_AB_cache(aTest) // AB.
+	aTest.MustBeEqual(c.size, 2)
+	aTest.MustBeEqual(c.volume, 2)
+
+	// Test #4. Record is new. No limits.
+	err = c.AddRecord("Q", "test") // AB -> QAB.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_3_records(c, [3]string{"Q", "A", "B"}, [3]string{"test", "1", "2"})
+	aTest.MustBeEqual(ok, true)
+	// Also check new values of size, volume and record's TTL.
+	aTest.MustBeEqual(c.size, 3)
+	aTest.MustBeEqual(c.volume, 4+1+1)
+
+	// Preparation for Test #5.
+	c = _test_prepare_AB_cache(aTest) // AB.
+	aTest.MustBeEqual(c.size, 2)
+	aTest.MustBeEqual(c.volume, 2)
+	c.sizeLimit = 2
+
+	// Test #5. Record is new. Size is limited.
+	err = c.AddRecord("Q", "test") // AB -> QA.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_2_records(c, [2]string{"Q", "A"}, [2]string{"test", "1"})
+	aTest.MustBeEqual(ok, true)
+	// Also check new values of size, volume and record's TTL.
+	aTest.MustBeEqual(c.size, 2)
+	aTest.MustBeEqual(c.volume, 4+1)
+
+	// Preparation for Test #6.
+	c = _test_prepare_AB_cache(aTest) // AB.
+	aTest.MustBeEqual(c.size, 2)
+	aTest.MustBeEqual(c.volume, 2)
+	c.volumeLimit = 4
+
+	// Test #6. Record is new. Volume is limited, 1 record is deleted.
+	err = c.AddRecord("Q", "xxx") // AB -> QAB -> QA.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_2_records(c, [2]string{"Q", "A"}, [2]string{"xxx", "1"})
+	aTest.MustBeEqual(ok, true)
+	// Also check new values of size, volume and record's TTL.
+	aTest.MustBeEqual(c.size, 2)
+	aTest.MustBeEqual(c.volume, 3+1)
+
+	// Preparation for Test #7.
+	c = _test_prepare_AB_cache(aTest) // AB.
+	aTest.MustBeEqual(c.size, 2)
+	aTest.MustBeEqual(c.volume, 2)
+	c.volumeLimit = 3
+
+	// Test #7. Record is new. Volume is limited, 2 records are deleted.
+	err = c.AddRecord("Q", "xxx") // AB -> QAB -> Q.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_1_record(c, "Q", "xxx")
+	aTest.MustBeEqual(ok, true)
+	// Also check new values of size, volume and record's TTL.
+	aTest.MustBeEqual(c.size, 1)
+	aTest.MustBeEqual(c.volume, 3)
+}
+
+func Test_GetRecord(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ok bool
+	var data string
+	var err error
+
+	c = _test_prepare_ABC_cache_with_low_ttl(aTest) // ABC.
+
+	// Test #1. 3R, Record is not found.
+	data, err = c.GetRecord("Junk") // ABC -> ABC.
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), `record is not found, uid=Junk`)
+	ok = _test_ensure_order_3_records(c, [3]string{"A", "B", "C"}, [3]string{"1", "2", "3"})
+	aTest.MustBeEqual(ok, true)
+
+	// Test #2. 3R, Record is outdated.
+	// Wait for the record to become outdated. N.B.: TTL is 3 Seconds.
+	time.Sleep(time.Second * (3 + 1))
+	data, err = c.GetRecord("B") // ABC -> AC.
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), `record is outdated, uid=B`)
+	ok = _test_ensure_order_2_records(c, [2]string{"A", "C"}, [2]string{"1", "3"})
+	aTest.MustBeEqual(ok, true)
+
+	c = _test_prepare_ABC_cache_with_low_ttl(aTest) // ABC.
+	oldLatOfRecordB := c.recordsByUid["B"].lastAccessTime
+
+	// Test #3. 3R, Record is alive.
+	// Wait a bit, but not more than TTL period. N.B.: TTL is 3 Seconds.
+	time.Sleep(time.Second * 1)  // 1 Sec.
+	data, err = c.GetRecord("B") // ABC -> BAC.
+	newLatOfRecordB := c.recordsByUid["B"].lastAccessTime
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "2")
+	// Ensure that the requested record has been moved to top and its LAT has
+	// been updated.
+	ok = _test_ensure_order_3_records(c, [3]string{"B", "A", "C"}, [3]string{"2", "1", "3"})
+	aTest.MustBeEqual(ok, true)
+	aTest.MustBeEqual(newLatOfRecordB-oldLatOfRecordB > 0, true)
+}
+
+func Test_RemoveRecord(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ok bool
+
+	c = _test_prepare_ABC_cache(aTest) // ABC.
+
+	// Test #1. Non-existent record.
+	c.RemoveRecord("Junk") // ABC -> ABC.
+	ok = _test_ensure_order_3_records(c, [3]string{"A", "B", "C"}, [3]string{"1", "2", "3"})
+	aTest.MustBeEqual(ok, true)
+
+	// Test #2. Existing record.
+	c.RemoveRecord("B") // ABC -> AC.
+	ok = _test_ensure_order_2_records(c, [2]string{"A", "C"}, [2]string{"1", "3"})
+	aTest.MustBeEqual(ok, true)
+}
+
+func Test_RemoveExistingRecord(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ok bool
+	var err error
+
+	c = _test_prepare_ABC_cache(aTest) // ABC.
+
+	// Test #1. Non-existent record.
+	err = c.RemoveExistingRecord("Junk") // ABC -> ABC.
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), `record is not found, uid=Junk`)
+	ok = _test_ensure_order_3_records(c, [3]string{"A", "B", "C"}, [3]string{"1", "2", "3"})
+	aTest.MustBeEqual(ok, true)
+
+	// Test #2. Existing record.
+	err = c.RemoveExistingRecord("B") // ABC -> AC.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_2_records(c, [2]string{"A", "C"}, [2]string{"1", "3"})
+	aTest.MustBeEqual(ok, true)
+}
+
+func Test_Clear(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ok bool
+	var err error
+
+	// Test. 0R -> 0R.
+	c = _test_prepare_0_cache()
+	err = c.Clear() // {} -> {}.
+	aTest.MustBeNoError(err)
+
+	// Test. 1R -> 0R.
+	c = _test_prepare_A_cache(aTest)
+	err = c.Clear() // A -> {}.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_0_records(c)
+	aTest.MustBeEqual(ok, true)
+
+	// Test. 2R -> 0R.
+	c = _test_prepare_AB_cache(aTest)
+	err = c.Clear() // AB -> {}.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_0_records(c)
+	aTest.MustBeEqual(ok, true)
+
+	// Test. 3R -> 0R.
+	c = _test_prepare_ABC_cache(aTest)
+	err = c.Clear() // ABC -> {}.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_0_records(c)
+	aTest.MustBeEqual(ok, true)
+}
+
+func Test_AddRecordCtx(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ok bool
+	var err error
+
+	// Test #1. Context is alive.
+	c = _test_prepare_AB_cache(aTest)                       // AB.
+	err = c.AddRecordCtx(context.Background(), "Q", "test") // AB -> QAB.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_3_records(c, [3]string{"Q", "A", "B"}, [3]string{"test", "1", "2"})
+	aTest.MustBeEqual(ok, true)
+
+	// Test #2. Context is cancelled while the lock is busy.
+	c = _test_prepare_AB_cache(aTest) // AB.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	c.lock.Lock()
+	err = c.AddRecordCtx(ctx, "Q", "test") // AB -> AB.
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(errors.Is(err, context.DeadlineExceeded), true)
+	c.lock.Unlock()
+	ok = _test_ensure_order_2_records(c, [2]string{"A", "B"}, [2]string{"1", "2"})
+	aTest.MustBeEqual(ok, true)
+}
+
+func Test_GetRecordCtx(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ok bool
+	var data string
+	var err error
+
+	c = _test_prepare_ABC_cache(aTest) // ABC.
+
+	// Test #1. Context is alive.
+	data, err = c.GetRecordCtx(context.Background(), "B") // ABC -> BAC.
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "2")
+	ok = _test_ensure_order_3_records(c, [3]string{"B", "A", "C"}, [3]string{"2", "1", "3"})
+	aTest.MustBeEqual(ok, true)
+
+	// Test #2. Context is already cancelled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	data, err = c.GetRecordCtx(ctx, "C") // BAC -> BAC.
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(errors.Is(err, context.Canceled), true)
+	ok = _test_ensure_order_3_records(c, [3]string{"B", "A", "C"}, [3]string{"2", "1", "3"})
+	aTest.MustBeEqual(ok, true)
+}
+
+func Test_GetOrLoadCtx(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ok bool
+	var data string
+	var err error
+	var loadsCount atomic.Int32
+	loader := func(ctx context.Context, uid string) (data string, err error) {
+		loadsCount.Add(1)
+		time.Sleep(time.Millisecond * 100)
+		return "L:" + uid, nil
+	}
+
+	// Test #1. Loader is not set.
+	c = _test_prepare_AB_cache(aTest) // AB.
+	data, err = c.GetOrLoadCtx(context.Background(), "Q", nil)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrLoaderIsNotSet)
+
+	// Test #2. Record exists.
+	data, err = c.GetOrLoadCtx(context.Background(), "B", loader) // AB -> BA.
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "2")
+	aTest.MustBeEqual(loadsCount.Load(), int32(0))
+	ok = _test_ensure_order_2_records(c, [2]string{"B", "A"}, [2]string{"2", "1"})
+	aTest.MustBeEqual(ok, true)
+
+	// Test #3. Record is absent, concurrent requests share a single load.
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d, e := c.GetOrLoadCtx(context.Background(), "Q", loader)
+			aTest.MustBeNoError(e)
+			aTest.MustBeEqual(d, "L:Q")
+		}()
+	}
+	wg.Wait() // BA -> QBA.
+	aTest.MustBeEqual(loadsCount.Load(), int32(1))
+	ok = _test_ensure_order_3_records(c, [3]string{"Q", "B", "A"}, [3]string{"L:Q", "2", "1"})
+	aTest.MustBeEqual(ok, true)
+	aTest.MustBeEqual(len(c.loads), 0)
+
+	// Test #4. Context is done while the load is in flight. The shared load
+	// is not cancelled, other requests receive its result.
+	var release = make(chan struct{})
+	var loaderCtxErr error
+	slowLoader := func(ctx context.Context, uid string) (data string, err error) {
+		<-release
+		loaderCtxErr = ctx.Err()
+		return "L:" + uid, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	data, err = c.GetOrLoadCtx(ctx, "W", slowLoader)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(errors.Is(err, context.DeadlineExceeded), true)
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		close(release)
+	}()
+	data, err = c.GetOrLoadCtx(context.Background(), "W", slowLoader)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "L:W")
+	aTest.MustBeEqual(loaderCtxErr, nil)
+
+	// Test #5. Loader fails.
+	data, err = c.GetOrLoadCtx(context.Background(), "E", func(ctx context.Context, uid string) (data string, err error) {
+		return data, errors.New("failure")
+	})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "failure")
+	aTest.MustBeEqual(c.RecordExists("E"), false)
+}
+
+func Test_lockWithContext(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var err error
+
+	c = _test_prepare_0_cache()
+
+	// Test #1. Lock is free.
+	err = c.lockWithContext(context.Background())
+	aTest.MustBeNoError(err)
+
+	// Test #2. Lock is busy, context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	err = c.lockWithContext(ctx)
+	aTest.MustBeAnError(err)
+
+	// Test #3. Lock is released, the abandoned attempt must not hold it.
+	c.lock.Unlock()
+	err = c.lockWithContext(context.Background())
+	aTest.MustBeNoError(err)
+	c.lock.Unlock()
+}
+
+func Test_evictBottomRecord(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ok bool
+	var err error
+
+	// Test #1. Empty cache.
+	c = _test_prepare_0_cache()
+	err = c.evictBottomRecord(EvictionReasonSize) // {} -> {}.
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrBottomRecordDoesNotExist)
+	aTest.MustBeEqual(c.stats.Evictions[EvictionReasonSize], uint64(0))
+
+	// Test #2. 2R -> 1R.
+	c = _test_prepare_AB_cache(aTest)
+	err = c.evictBottomRecord(EvictionReasonSize) // AB -> A.
+	aTest.MustBeNoError(err)
+	ok = _test_ensure_order_1_record(c, "A", "1")
+	aTest.MustBeEqual(ok, true)
+	aTest.MustBeEqual(c.stats.Evictions[EvictionReasonSize], uint64(1))
+}
+
+func Test_removeOutdatedRecord(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ok bool
+
+	// Test.
+	c = _test_prepare_ABC_cache(aTest)
+	c.removeOutdatedRecord(c.recordsByUid["B"]) // ABC -> AC.
+	ok = _test_ensure_order_2_records(c, [2]string{"A", "C"}, [2]string{"1", "3"})
+	aTest.MustBeEqual(ok, true)
+	aTest.MustBeEqual(c.stats.Evictions[EvictionReasonExpired], uint64(1))
+}
+
+func Test_GetSize(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+
+	// Test.
+	c = _test_new_cache[string, string](10, 0, 60)
+	c.size = 3
+	var size int
+	var sizeLimit int
+	size, sizeLimit = c.GetSize()
+	aTest.MustBeEqual(size, 3)
+	aTest.MustBeEqual(sizeLimit, 10)
+}
+
+func Test_GetStats(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var stats Stats
+	var err error
+
+	// Test #1. Hits, misses and expirations.
+	c = _test_prepare_ABC_cache_with_low_ttl(aTest) // ABC.
+	_, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	_, err = c.GetRecord("Junk")
+	aTest.MustBeAnError(err)
+	time.Sleep(time.Second * (3 + 1))
+	_, err = c.GetRecord("B")
+	aTest.MustBeAnError(err)
+	stats = c.GetStats()
+	aTest.MustBeEqual(stats.Hits, uint64(1))
+	aTest.MustBeEqual(stats.Misses, uint64(2))
+	aTest.MustBeEqual(stats.ExpiredOnRead, uint64(1))
+	aTest.MustBeEqual(stats.Evictions[EvictionReasonExpired], uint64(1))
+
+	// The returned statistics must be a copy.
+	stats.Evictions[EvictionReasonExpired] = 100
+	aTest.MustBeEqual(c.stats.Evictions[EvictionReasonExpired], uint64(1))
+
+	// Test #2. Size limit.
+	c = _test_prepare_AB_cache(aTest) // AB.
+	c.sizeLimit = 2
+	err = c.AddRecord("Q", "x") // AB -> QAB -> QA.
+	aTest.MustBeNoError(err)
+	stats = c.GetStats()
+	aTest.MustBeEqual(stats.Evictions[EvictionReasonSize], uint64(1))
+
+	// Test #3. Volume limit.
+	c = _test_prepare_AB_cache(aTest) // AB.
+	c.volumeLimit = 3
+	err = c.AddRecord("Q", "xx") // AB -> QAB -> QA.
+	aTest.MustBeNoError(err)
+	stats = c.GetStats()
+	aTest.MustBeEqual(stats.Evictions[EvictionReasonVolume], uint64(1))
+}
+
+func Test_GetTtl(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+
+	// Test.
+	c = _test_new_cache[string, string](0, 0, 60)
+	aTest.MustBeEqual(c.GetTtl(), uint(60))
+}
+
+func Test_PeekTopUids(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ok bool
+
+	// Test #1. Empty cache.
+	c = _test_prepare_0_cache()
+	aTest.MustBeEqual(c.PeekTopUids(2), []string{})
+
+	// Test #2. Partial list.
+	c = _test_prepare_ABC_cache(aTest)
+	aTest.MustBeEqual(c.PeekTopUids(2), []string{"A", "B"})
+
+	// Test #3. Full list. Order must not change.
+	aTest.MustBeEqual(c.PeekTopUids(10), []string{"A", "B", "C"})
+	ok = _test_ensure_order_3_records(c, [3]string{"A", "B", "C"}, [3]string{"1", "2", "3"})
+	aTest.MustBeEqual(ok, true)
+}
+
+func Test_PeekBottomUids(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ok bool
+
+	// Test #1. Empty cache.
+	c = _test_prepare_0_cache()
+	aTest.MustBeEqual(c.PeekBottomUids(2), []string{})
+
+	// Test #2. Partial list.
+	c = _test_prepare_ABC_cache(aTest)
+	aTest.MustBeEqual(c.PeekBottomUids(2), []string{"C", "B"})
+
+	// Test #3. Full list. Order must not change.
+	aTest.MustBeEqual(c.PeekBottomUids(10), []string{"C", "B", "A"})
+	ok = _test_ensure_order_3_records(c, [3]string{"A", "B", "C"}, [3]string{"1", "2", "3"})
+	aTest.MustBeEqual(ok, true)
+}
+
+func Test_SetInstrumentation(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var err error
+	var ti = new(_test_instrumentation)
+
+	c = _test_prepare_AB_cache(aTest) // AB.
+	c.SetInstrumentation(ti)
+
+	// Test.
+	err = c.AddRecord("Q", "test")
+	aTest.MustBeNoError(err)
+	err = c.AddRecordCtx(context.Background(), "Q", "x")
+	aTest.MustBeNoError(err)
+	c.volumeLimit = 3
+	err = c.AddRecord("E", "toobig")
+	aTest.MustBeAnError(err)
+	c.volumeLimit = 0
+	_, err = c.GetRecordCtx(context.Background(), "Q")
+	aTest.MustBeNoError(err)
+	_, err = c.GetRecord("Junk")
+	aTest.MustBeAnError(err)
+	c.RemoveRecord("Junk")
+	err = c.RemoveExistingRecord("A")
+	aTest.MustBeNoError(err)
+	err = c.Clear()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(ti.events, []string{
+		"start AddRecord Q",
+		"end AddRecord Q added 4 false true",
+		"start AddRecord Q",
+		"end AddRecord Q updated -3 false true",
+		"start AddRecord E",
+		"end AddRecord E failed 0 true true",
+		"start GetRecord Q",
+		"end GetRecord Q hit 0 false true",
+		"start GetRecord Junk",
+		"end GetRecord Junk miss 0 true true",
+		"start RemoveRecord Junk",
+		"end RemoveRecord Junk miss 0 false true",
+		"start RemoveRecord A",
+		"end RemoveRecord A removed -1 false true",
+		"start Clear ",
+		"end Clear  removed -2 false true",
+	})
+
+	// Test. Instrumentation is disabled.
+	c.SetInstrumentation(nil)
+	err = c.AddRecord("Q", "test")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(len(ti.events), 16)
+}
+
+func Test_SetLogger(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var err error
+	var buf = new(bytes.Buffer)
+	var logger = slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	// Test #1. Eviction.
+	c = _test_prepare_AB_cache(aTest) // AB.
+	c.SetLogger(logger, LogSampling{})
+	c.sizeLimit = 2
+	err = c.AddRecord("Q", "xx") // AB -> QAB -> QA.
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(buf.String(), `level=DEBUG msg="record is evicted" uid=B reason=size recordVolume=1 cache.size=2 cache.sizeLimit=2 cache.volume=3 cache.volumeLimit=0`+"\n")
+
+	// Test #2. Rejection.
+	buf.Reset()
+	c.volumeLimit = 3
+	err = c.AddRecord("W", "toobig")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(buf.String(), `level=INFO msg="record is rejected" uid=W reason="record is too big" recordVolume=6 cache.size=2 cache.sizeLimit=2 cache.volume=3 cache.volumeLimit=3`+"\n")
+
+	// Test #3. Expiration.
+	buf.Reset()
+	c.removeOutdatedRecord(c.recordsByUid["A"])
+	aTest.MustBeEqual(buf.String(), `level=DEBUG msg="record is expired" uid=A reason=expired recordVolume=1 cache.size=1 cache.sizeLimit=2 cache.volume=2 cache.volumeLimit=3`+"\n")
+
+	// Test. Sampling.
+	buf.Reset()
+	c = _test_prepare_0_cache()
+	c.SetLogger(logger, LogSampling{Initial: 1, Interval: time.Hour})
+	c.sizeLimit = 1
+	for _, uid := range []string{"A", "B", "C", "D"} {
+		err = c.AddRecord(uid, uid)
+		aTest.MustBeNoError(err)
+	}
+	aTest.MustBeEqual(bytes.Count(buf.Bytes(), []byte("record is evicted")), 1)
+
+	// Test. Logger is disabled.
+	buf.Reset()
+	c.SetLogger(nil, LogSampling{})
+	err = c.AddRecord("E", "E")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(buf.Len(), 0)
+}
+
+func Test_New(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var err error
+
+	// Test #1. Bad settings.
+	c, err = New[string, string]()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrTtlIsZero)
+	aTest.MustBeEqual(c, (*Cache[string, string])(nil))
+
+	c, err = New(WithVolumeLimit[string, string](2), WithRecordTtl[string, string](3))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrMeasurementIsNotSet)
+
+	// Test #2. OK.
+	c, err = New(
+		WithSizeLimit[string, string](1),
+		WithVolumeLimit[string, string](2),
+		WithRecordTtl[string, string](3),
+		WithMeasurement[string, string](ByteLength[string]{}),
+	)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(c.sizeLimit, 1)
+	aTest.MustBeEqual(c.volumeLimit, 2)
+	aTest.MustBeEqual(c.recordTtl, uint(3))
+}
+
+func Test_NewFromConfig(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var err error
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	var evictions []string
+
+	// Test #1. Bad settings.
+	c, err = NewFromConfig(Config[string, string]{SizeLimit: -1, RecordTtl: 60})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrSizeLimitIsNegative)
+
+	// Test #2. Clock and eviction callback.
+	c, err = NewFromConfig(Config[string, string]{SizeLimit: 2, VolumeLimit: 10, RecordTtl: 60,
+		Measurement: ByteLength[string]{},
+		Clock:       clock,
+		OnEviction: func(uid string, data string, reason EvictionReason) {
+			evictions = append(evictions, fmt.Sprintf("%s=%s %s", uid, data, reason))
+		},
+	})
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("A", "1")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(c.top.lastAccessTime, uint(1000))
+	err = c.AddRecord("B", "2")
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("C", "3") // BA -> CBA -> CB.
+	aTest.MustBeNoError(err)
+	clock.Add(time.Second * 60)
+	_, err = c.GetRecord("B") // CB -> C.
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(evictions, []string{"A=1 size", "B=2 expired"})
+}
+
+func Test_now(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+
+	// Test #1. No cache.
+	aTest.MustBeEqual(c.now() >= uint(time.Now().Unix())-1, true)
+
+	// Test #2. No clock.
+	c = _test_prepare_0_cache()
+	aTest.MustBeEqual(c.now() >= uint(time.Now().Unix())-1, true)
+
+	// Test #3. Clock.
+	c.clock = &_test_clock{t: time.Unix(123, 0)}
+	aTest.MustBeEqual(c.now(), uint(123))
+}
+
+func Test_SetSizeLimit(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var droppedCount int
+	var ok bool
+	var err error
+
+	c = _test_prepare_ABC_cache(aTest) // ABC.
+
+	// Test #1. Negative limit.
+	droppedCount, err = c.SetSizeLimit(-1)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrSizeLimitIsNegative)
+	aTest.MustBeEqual(droppedCount, 0)
+
+	// Test #2. Limit is not exceeded.
+	droppedCount, err = c.SetSizeLimit(3) // ABC -> ABC.
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(droppedCount, 0)
+	ok = _test_ensure_order_3_records(c, [3]string{"A", "B", "C"}, [3]string{"1", "2", "3"})
+	aTest.MustBeEqual(ok, true)
+
+	// Test #3. Limit is shrunk.
+	droppedCount, err = c.SetSizeLimit(1) // ABC -> A.
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(droppedCount, 2)
+	ok = _test_ensure_order_1_record(c, "A", "1")
+	aTest.MustBeEqual(ok, true)
+	aTest.MustBeEqual(c.sizeLimit, 1)
+	aTest.MustBeEqual(c.stats.Evictions[EvictionReasonSize], uint64(2))
+}
+
+func Test_SetVolumeLimit(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var droppedCount int
+	var ok bool
+	var err error
+
+	c = _test_prepare_ABC_cache(aTest) // ABC.
+
+	// Test #1. Negative limit.
+	droppedCount, err = c.SetVolumeLimit(-1)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrVolumeLimitIsNegative)
+	aTest.MustBeEqual(droppedCount, 0)
+
+	// Test #2. Limit is shrunk.
+	droppedCount, err = c.SetVolumeLimit(1) // ABC -> A.
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(droppedCount, 2)
+	ok = _test_ensure_order_1_record(c, "A", "1")
+	aTest.MustBeEqual(ok, true)
+	aTest.MustBeEqual(c.volumeLimit, 1)
+	aTest.MustBeEqual(c.stats.Evictions[EvictionReasonVolume], uint64(2))
+
+	// Test #3. Limit is disabled.
+	droppedCount, err = c.SetVolumeLimit(0)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(droppedCount, 0)
+	err = c.AddRecord("Q", "xxx")
+	aTest.MustBeNoError(err)
+
+	// Test #4. Volume is not measured.
+	c.measurement = nil
+	droppedCount, err = c.SetVolumeLimit(1)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrMeasurementIsNotSet)
+	aTest.MustBeEqual(droppedCount, 0)
+	aTest.MustBeEqual(c.volumeLimit, 0)
+}
+
+func Test_SetTtl(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	var droppedCount int
+	var ok bool
+	var err error
+
+	c, err = NewFromConfig(Config[string, string]{RecordTtl: 60, Clock: clock})
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("C", "3") // LAT = 1000.
+	aTest.MustBeNoError(err)
+	clock.Add(time.Second * 10)
+	err = c.AddRecord("B", "2") // LAT = 1010.
+	aTest.MustBeNoError(err)
+	clock.Add(time.Second * 10)
+	err = c.AddRecord("A", "1") // LAT = 1020.
+	aTest.MustBeNoError(err)
+	clock.Add(time.Second * 5) // Now = 1025.
+
+	// Test #1. Zero TTL.
+	droppedCount, err = c.SetTtl(0)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrTtlIsZero)
+	aTest.MustBeEqual(droppedCount, 0)
+
+	// Test #2. No record is outdated.
+	droppedCount, err = c.SetTtl(30) // ABC -> ABC.
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(droppedCount, 0)
+	ok = _test_ensure_order_3_records(c, [3]string{"A", "B", "C"}, [3]string{"1", "2", "3"})
+	aTest.MustBeEqual(ok, true)
+
+	// Test #3. TTL is shrunk.
+	droppedCount, err = c.SetTtl(20) // ABC -> AB.
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(droppedCount, 1)
+	ok = _test_ensure_order_2_records(c, [2]string{"A", "B"}, [2]string{"1", "2"})
+	aTest.MustBeEqual(ok, true)
+	aTest.MustBeEqual(c.GetTtl(), uint(20))
+
+	// Test #4. All records are outdated.
+	droppedCount, err = c.SetTtl(1) // AB -> {}.
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(droppedCount, 2)
+	ok = _test_ensure_order_0_records(c)
+	aTest.MustBeEqual(ok, true)
+	aTest.MustBeEqual(c.stats.Evictions[EvictionReasonExpired], uint64(3))
+}
+
+func Test_Store_WriteThrough(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ts = _test_new_store()
+	var storeErrors []string
+	var data string
+	var err error
+
+	c, err = New(
+		WithRecordTtl[string, string](60),
+		WithStore[string, string](ts, WriteModeThrough, WriteBehindSettings{}),
+		WithOnStoreError[string, string](func(uid string, err error) { storeErrors = append(storeErrors, uid) }),
+	)
+	aTest.MustBeNoError(err)
+
+	// Test #1. Record is written synchronously.
+	err = c.AddRecord("A", "1")
+	aTest.MustBeNoError(err)
+	data, _ = ts.get("A")
+	aTest.MustBeEqual(data, "1")
+
+	// Test #2. Failed store does not change the cache.
+	ts.setFailures(1)
+	err = c.AddRecordCtx(context.Background(), "B", "2")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+
+	// Test #3. Removal.
+	c.RemoveRecord("A")
+	_, ok := ts.get("A")
+	aTest.MustBeEqual(ok, false)
+	ts.setFailures(1)
+	c.RemoveRecord("Q")
+	aTest.MustBeEqual(storeErrors, []string{"Q"})
+	ts.setFailures(1)
+	err = c.RemoveExistingRecord("Q")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "store failure")
+
+	// Test #4. Store is the default loader.
+	ts.records["L"] = "loaded"
+	data, err = c.GetOrLoadCtx(context.Background(), "L", nil)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "loaded")
+	aTest.MustBeEqual(c.RecordExists("L"), true)
+
+	// Test #5. Flush and close do nothing.
+	aTest.MustBeNoError(c.Flush(context.Background()))
+	aTest.MustBeNoError(c.Close())
+
+	// Test #6. Cache is not locked while the store is being written.
+	ts.gate = make(chan struct{})
+	added := make(chan error)
+	go func() { added <- c.AddRecord("S", "slow") }()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	data, err = c.GetRecordCtx(ctx, "L")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "loaded")
+	aTest.MustBeEqual(c.RecordExists("S"), false)
+	close(ts.gate)
+	aTest.MustBeNoError(<-added)
+	aTest.MustBeEqual(c.RecordExists("S"), true)
+}
+
+func Test_Store_WriteBehind(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var ts = _test_new_store()
+	var data string
+	var err error
+
+	// Test #1. Bad settings.
+	_, err = NewFromConfig(Config[string, string]{RecordTtl: 60, WriteMode: WriteModeBehind})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrStoreIsNotSet)
+	_, err = NewFromConfig(Config[string, string]{RecordTtl: 60, Store: ts, WriteMode: WriteModeBehind})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrFlushIntervalIsNotPositive)
+	_, err = NewFromConfig(Config[string, string]{RecordTtl: 60, WriteMode: 100})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrWriteModeIsUnknown)
+
+	c, err = NewFromConfig(Config[string, string]{
+		SizeLimit:   1,
+		RecordTtl:   60,
+		Store:       ts,
+		WriteMode:   WriteModeBehind,
+		WriteBehind: WriteBehindSettings{FlushInterval: time.Hour},
+	})
+	aTest.MustBeNoError(err)
+
+	// Test #2. Record is written asynchronously.
+	err = c.AddRecord("A", "1")
+	aTest.MustBeNoError(err)
+	_, ok := ts.get("A")
+	aTest.MustBeEqual(ok, false)
+	err = c.Flush(context.Background())
+	aTest.MustBeNoError(err)
+	data, _ = ts.get("A")
+	aTest.MustBeEqual(data, "1")
+
+	// Test #3. Queued data is loaded instead of the store's data.
+	err = c.AddRecord("A", "2")
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("B", "3") // A is evicted while being dirty.
+	aTest.MustBeNoError(err)
+	data, err = c.GetOrLoadCtx(context.Background(), "A", nil)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "2")
+
+	// Test #4. Eviction of a dirty record triggers a flush.
+	time.Sleep(time.Millisecond * 100)
+	data, _ = ts.get("B")
+	aTest.MustBeEqual(data, "3")
+
+	// Test #5. Queued deletion.
+	c.RemoveRecord("B")
+	_, err = c.GetOrLoadCtx(context.Background(), "B", nil)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), `record is not found, uid=B`)
+
+	// Test #6. Close flushes the queue.
+	err = c.Close()
+	aTest.MustBeNoError(err)
+	_, ok = ts.get("B")
+	aTest.MustBeEqual(ok, false)
+}
+
+func Test_refreshAhead(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	var release = make(chan struct{})
+	var loadsCount atomic.Int32
+	var loaderFails atomic.Bool
+	var data string
+	var err error
+
+	loader := func(ctx context.Context, uid string) (data string, err error) {
+		n := loadsCount.Add(1)
+		<-release
+		if loaderFails.Load() {
+			return "", errors.New("loader failure")
+		}
+		return fmt.Sprintf("%s%d", uid, n), nil
+	}
+	waitForLoads := func() {
+		for i := 0; i < 100; i++ {
+			c.lock.RLock()
+			n := len(c.loads)
+			c.lock.RUnlock()
+			if n == 0 {
+				return
+			}
+			time.Sleep(time.Millisecond * 10)
+		}
+		t.Fatal("loads are not finished")
+	}
+
+	c, err = New(
+		WithRecordTtl[string, string](10),
+		WithClock[string, string](clock),
+		WithLoader[string, string](loader),
+		WithRefreshAhead[string, string](0.5),
+	)
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("A", "A0")
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("B", "B0")
+	aTest.MustBeNoError(err)
+
+	// Test #1. Young record is not reloaded.
+	clock.Add(time.Second * 5)
+	data, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "A0")
+	aTest.MustBeEqual(loadsCount.Load(), int32(0))
+
+	// Test #2. Old record is returned and reloaded once.
+	clock.Add(time.Second)
+	data, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "A0")
+	data, err = c.GetOrLoadCtx(context.Background(), "A", nil)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "A0")
+	close(release)
+	waitForLoads()
+	aTest.MustBeEqual(loadsCount.Load(), int32(1))
+	aTest.MustBeEqual(c.PeekTopUids(2), []string{"A", "B"})
+	data, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "A1")
+
+	// Test #3. Failed reload keeps the record.
+	loaderFails.Store(true)
+	data, err = c.GetRecord("B")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "B0")
+	waitForLoads()
+	aTest.MustBeEqual(loadsCount.Load(), int32(2))
+	data, err = c.GetRecord("B")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "B0")
+	waitForLoads() // B is still old, so it is reloaded again.
+	aTest.MustBeEqual(loadsCount.Load(), int32(3))
+
+	// Test #4. Reloaded record is moved to the top.
+	loaderFails.Store(false)
+	c.refresh(c.recordsByUid["A"], loader, newLoadCall[string]())
+	aTest.MustBeEqual(c.PeekTopUids(2), []string{"A", "B"})
+	aTest.MustBeEqual(c.top.data, "A4")
+
+	// Test #5. Record removed during a reload is not added back.
+	var gate = make(chan struct{})
+	gatedLoader := func(ctx context.Context, uid string) (data string, err error) {
+		<-gate
+		return uid + "R", nil
+	}
+	c.lock.Lock()
+	c.startRefresh(c.recordsByUid["B"], gatedLoader)
+	c.lock.Unlock()
+	c.RemoveRecord("B")
+	close(gate)
+	waitForLoads()
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+
+	// Test #6. Reload of a cleared record is stored when it is awaited.
+	gate = make(chan struct{})
+	c.lock.Lock()
+	c.startRefresh(c.recordsByUid["A"], gatedLoader)
+	call := c.loads["A"]
+	c.lock.Unlock()
+	aTest.MustBeNoError(c.Clear())
+	loaded := make(chan string)
+	go func() {
+		d, _ := c.GetOrLoadCtx(context.Background(), "A", gatedLoader)
+		loaded <- d
+	}()
+	for isAwaited := false; !isAwaited; time.Sleep(time.Millisecond) {
+		c.lock.RLock()
+		isAwaited = call.isAwaited
+		c.lock.RUnlock()
+	}
+	close(gate)
+	aTest.MustBeEqual(<-loaded, "AR")
+	aTest.MustBeEqual(c.RecordExists("A"), true)
+}
+
+func Test_GetRecordAllowStale(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	var release = make(chan struct{})
+	var data string
+	var freshness Freshness
+	var err error
+
+	c, err = New(
+		WithRecordTtl[string, string](10),
+		WithClock[string, string](clock),
+		WithGracePeriod[string, string](5),
+	)
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("A", "1")
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("B", "2")
+	aTest.MustBeNoError(err)
+
+	// Test #1. Fresh record.
+	data, freshness, err = c.GetRecordAllowStale("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "1")
+	aTest.MustBeEqual(freshness, FreshnessFresh)
+
+	// Test #2. Absent record.
+	_, _, err = c.GetRecordAllowStale("Q")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "record is not found, uid=Q")
+
+	// Test #3. Outdated record is kept during the grace period.
+	clock.Add(time.Second * 11)
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+	_, err = c.GetRecord("B")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "record is outdated, uid=B")
+	aTest.MustBeEqual(c.size, 2)
+
+	// Test #4. Stale record is returned and is not moved.
+	data, freshness, err = c.GetRecordAllowStale("B")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "2")
+	aTest.MustBeEqual(freshness, FreshnessStale)
+	aTest.MustBeEqual(c.PeekTopUids(2), []string{"A", "B"})
+	aTest.MustBeEqual(c.GetStats().StaleHits, uint64(1))
+
+	// Test #5. Grace period is over.
+	clock.Add(time.Second * 5)
+	_, _, err = c.GetRecordAllowStale("B")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "record is outdated, uid=B")
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+	aTest.MustBeEqual(c.size, 1)
+
+	// Test #6. Stale record is revalidated in background.
+	c.loader = func(ctx context.Context, uid string) (data string, err error) {
+		<-release
+		return "3", nil
+	}
+	err = c.AddRecord("C", "C")
+	aTest.MustBeNoError(err)
+	clock.Add(time.Second * 12)
+	data, freshness, err = c.GetRecordAllowStale("C")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "C")
+	aTest.MustBeEqual(freshness, FreshnessStale)
+	close(release)
+	data, err = c.GetOrLoadCtx(context.Background(), "C", nil)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "3")
+	data, freshness, err = c.GetRecordAllowStale("C")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "3")
+	aTest.MustBeEqual(freshness, FreshnessFresh)
+}
+
+func Test_StaleIfError(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	var data string
+	var err error
+
+	loader := func(ctx context.Context, uid string) (data string, err error) {
+		return "", errors.New("loader failure")
+	}
+
+	c, err = New(
+		WithRecordTtl[string, string](10),
+		WithClock[string, string](clock),
+		WithGracePeriod[string, string](5),
+		WithStaleIfError[string, string](),
+	)
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("A", "1")
+	aTest.MustBeNoError(err)
+
+	// Test #1. Stale data is returned when the loader fails.
+	clock.Add(time.Second * 11)
+	data, err = c.GetOrLoadCtx(context.Background(), "A", loader)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "1")
+	aTest.MustBeEqual(c.GetStats().StaleHits, uint64(1))
+
+	// Test #2. Grace period is over.
+	clock.Add(time.Second * 5)
+	_, err = c.GetOrLoadCtx(context.Background(), "A", loader)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "loader failure")
+
+	// Test #3. Stale-if-error is disabled.
+	c.staleIfError = false
+	err = c.AddRecord("B", "2")
+	aTest.MustBeNoError(err)
+	clock.Add(time.Second * 11)
+	_, err = c.GetOrLoadCtx(context.Background(), "B", loader)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "loader failure")
+}
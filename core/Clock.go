@@ -0,0 +1,9 @@
+package core
+
+import "time"
+
+// Clock is a source of current time. It allows to control time of the cache,
+// e.g. in tests. A cache without a clock uses system time.
+type Clock interface {
+	Now() time.Time
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+)
+
+// Config contains settings of the cache.
+type Config[U comparable, D any] struct {
+	// SizeLimit is a maximum number of records, zero disables the limit.
+	SizeLimit int
+
+	// VolumeLimit is a maximum total volume of records, zero disables the
+	// limit. A volume limit requires a measurement.
+	VolumeLimit int
+
+	// Measurement is a strategy of measuring volume of records' data. Nil
+	// disables calculation of volume.
+	Measurement Measurement[D]
+
+	// RecordTtl is the records' TTL in seconds, it must not be zero.
+	RecordTtl uint
+
+	// Clock is an optional source of current time.
+	Clock Clock
+
+	// OnEviction is an optional callback which is called when a record is
+	// evicted to fit the limits or removed as outdated. It is called while
+	// the cache is locked, so it must not use the cache.
+	OnEviction func(uid U, data D, reason EvictionReason)
+
+	// Logger is an optional logger, see the SetLogger method.
+	Logger *slog.Logger
+
+	// LogSampling contains settings of sampling of log events.
+	LogSampling LogSampling
+
+	// Instrumentation is an optional instrumentation, see the
+	// SetInstrumentation method.
+	Instrumentation Instrumentation[U]
+
+	// Store is an optional backing store. When it is set, added records are
+	// written to the store, removed records are deleted from the store, and
+	// the store is used as a default loader of records.
+	Store Store[U, D]
+
+	// WriteMode is a mode of writing records to the store.
+	WriteMode WriteMode
+
+	// WriteBehind contains settings of the write-behind mode.
+	WriteBehind WriteBehindSettings
+
+	// OnStoreError is an optional callback which receives errors of the
+	// store which can not be returned to a caller, i.e. errors of the
+	// RemoveRecord method and errors of writes dropped by the write-behind
+	// flusher.
+	OnStoreError func(uid U, err error)
+
+	// Loader is an optional default loader of records. It is used by the
+	// GetOrLoadCtx method when no loader is given, and by the refresh-ahead.
+	// If it is not set, the store is used as a default loader.
+	Loader Loader[U, D]
+
+	// RefreshAheadFactor is a fraction of the TTL after which a record read
+	// from the cache is reloaded in background. It must be in the [0, 1)
+	// range, zero disables the refresh-ahead.
+	RefreshAheadFactor float64
+
+	// GracePeriod is a period in seconds after the TTL during which an
+	// outdated record is kept in the cache and may be served as stale, see
+	// the GetRecordAllowStale method.
+	GracePeriod uint
+
+	// StaleIfError enables serving of stale data by the GetOrLoadCtx method
+	// when the loader fails.
+	StaleIfError bool
+}
+
+func (cfg Config[U, D]) validate() (err error) {
+	if cfg.SizeLimit < 0 {
+		return errors.New(ErrSizeLimitIsNegative)
+	}
+
+	if cfg.VolumeLimit < 0 {
+		return errors.New(ErrVolumeLimitIsNegative)
+	}
+
+	if (cfg.VolumeLimit > 0) && (cfg.Measurement == nil) {
+		return errors.New(ErrMeasurementIsNotSet)
+	}
+
+	if cfg.RecordTtl == 0 {
+		return errors.New(ErrTtlIsZero)
+	}
+
+	if math.IsNaN(cfg.RefreshAheadFactor) || (cfg.RefreshAheadFactor < 0) || (cfg.RefreshAheadFactor >= 1) {
+		return errors.New(ErrRefreshAheadFactorIsInvalid)
+	}
+
+	if (cfg.RefreshAheadFactor > 0) && (cfg.Loader == nil) && (cfg.Store == nil) {
+		return errors.New(ErrLoaderIsNotSet)
+	}
+
+	switch cfg.WriteMode {
+	case WriteModeThrough:
+	case WriteModeBehind:
+		if cfg.Store == nil {
+			return errors.New(ErrStoreIsNotSet)
+		}
+
+		err = cfg.WriteBehind.validate()
+		if err != nil {
+			return err
+		}
+	default:
+		return errors.New(ErrWriteModeIsUnknown)
+	}
+
+	return nil
+}
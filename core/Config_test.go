@@ -0,0 +1,54 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_validate(t *testing.T) {
+	aTest := tester.New(t)
+	var cfg Config[string, string]
+	var err error
+
+	// Test #1. Zero TTL.
+	err = cfg.validate()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrTtlIsZero)
+
+	// Test #2. Negative limits.
+	cfg.RecordTtl = 60
+	cfg.SizeLimit = -1
+	err = cfg.validate()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrSizeLimitIsNegative)
+	cfg.SizeLimit = 0
+
+	cfg.VolumeLimit = -1
+	err = cfg.validate()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrVolumeLimitIsNegative)
+	cfg.VolumeLimit = 0
+
+	// Test #3. Refresh-ahead.
+	cfg.RefreshAheadFactor = 1
+	err = cfg.validate()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrRefreshAheadFactorIsInvalid)
+
+	cfg.RefreshAheadFactor = math.NaN()
+	err = cfg.validate()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrRefreshAheadFactorIsInvalid)
+
+	cfg.RefreshAheadFactor = 0.5
+	err = cfg.validate()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrLoaderIsNotSet)
+	cfg.RefreshAheadFactor = 0
+
+	// Test #4. OK.
+	err = cfg.validate()
+	aTest.MustBeNoError(err)
+}
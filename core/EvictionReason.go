@@ -0,0 +1,17 @@
+package core
+
+// EvictionReason is a reason of a record's removal made by the cache itself.
+type EvictionReason string
+
+const (
+	// EvictionReasonSize is used when a record is evicted to fit the size
+	// limit.
+	EvictionReasonSize EvictionReason = "size"
+
+	// EvictionReasonVolume is used when a record is evicted to fit the
+	// volume limit.
+	EvictionReasonVolume EvictionReason = "volume"
+
+	// EvictionReasonExpired is used when an outdated record is removed.
+	EvictionReasonExpired EvictionReason = "expired"
+)
@@ -0,0 +1,13 @@
+package core
+
+// Freshness tells whether data returned by the cache is fresh or stale.
+type Freshness string
+
+const (
+	// FreshnessFresh is used for data of an alive record.
+	FreshnessFresh Freshness = "fresh"
+
+	// FreshnessStale is used for data of an outdated record which is in its
+	// grace period.
+	FreshnessStale Freshness = "stale"
+)
@@ -0,0 +1,15 @@
+package core
+
+import "context"
+
+// Instrumentation observes operations of the cache, e.g. for tracing.
+//
+// StartOperation is called before an operation and may return a derived
+// context which is then passed to EndOperation. EndOperation is called after
+// the operation with its result. Operations which are not related to a single
+// record, e.g. Clear, receive a zero UID. Both hooks are called while the
+// cache is locked.
+type Instrumentation[U comparable] interface {
+	StartOperation(ctx context.Context, op Operation, uid U) (ctxOut context.Context)
+	EndOperation(ctx context.Context, op Operation, uid U, result OperationResult)
+}
@@ -0,0 +1,36 @@
+package core
+
+import "context"
+
+// loadCall is a single in-flight load of a record which is shared by all the
+// requests of the same UID.
+type loadCall[D any] struct {
+	done chan struct{}
+	data D
+	err  error
+
+	// isAwaited is set when a request waits for the load. It is protected by
+	// the cache's lock.
+	isAwaited bool
+}
+
+func newLoadCall[D any]() (call *loadCall[D]) {
+	return &loadCall[D]{
+		done: make(chan struct{}),
+	}
+}
+
+func (lc *loadCall[D]) finish(data D, err error) {
+	lc.data = data
+	lc.err = err
+	close(lc.done)
+}
+
+func (lc *loadCall[D]) wait(ctx context.Context) (data D, err error) {
+	select {
+	case <-lc.done:
+		return lc.data, lc.err
+	case <-ctx.Done():
+		return data, ctx.Err()
+	}
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_loadCall(t *testing.T) {
+	aTest := tester.New(t)
+	var call *loadCall[string]
+	var data string
+	var err error
+
+	// Test #1. Call is finished.
+	call = newLoadCall[string]()
+	call.finish("data", nil)
+	data, err = call.wait(context.Background())
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "data")
+
+	// Test #2. Context is done before the call is finished.
+	call = newLoadCall[string]()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	data, err = call.wait(ctx)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(errors.Is(err, context.DeadlineExceeded), true)
+	aTest.MustBeEqual(data, "")
+}
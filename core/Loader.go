@@ -0,0 +1,7 @@
+package core
+
+import "context"
+
+// Loader is a function which loads data of a record which is absent in the
+// cache.
+type Loader[U comparable, D any] func(ctx context.Context, uid U) (data D, err error)
@@ -0,0 +1,49 @@
+package core
+
+import "time"
+
+const logSamplerDefaultInterval = time.Second
+
+// logSampler decides which log events are written. It is not safe for
+// concurrent use, the cache must be locked for writing.
+type logSampler struct {
+	sampling    LogSampling
+	periodStart time.Time
+	counters    map[string]int
+}
+
+func newLogSampler(sampling LogSampling) (ls *logSampler) {
+	if sampling.Interval <= 0 {
+		sampling.Interval = logSamplerDefaultInterval
+	}
+
+	return &logSampler{
+		sampling: sampling,
+		counters: make(map[string]int),
+	}
+}
+
+// allow registers an event of the kind and tells whether it must be logged.
+func (ls *logSampler) allow(kind string) bool {
+	if ls.sampling.isDisabled() {
+		return true
+	}
+
+	now := time.Now()
+	if now.Sub(ls.periodStart) >= ls.sampling.Interval {
+		ls.periodStart = now
+		clear(ls.counters)
+	}
+
+	ls.counters[kind]++
+	n := ls.counters[kind]
+	if n <= ls.sampling.Initial {
+		return true
+	}
+
+	if ls.sampling.Thereafter <= 0 {
+		return false
+	}
+
+	return (n-ls.sampling.Initial)%ls.sampling.Thereafter == 0
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_newLogSampler(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test.
+	aTest.MustBeEqual(newLogSampler(LogSampling{}).sampling.Interval, time.Second)
+	aTest.MustBeEqual(newLogSampler(LogSampling{Interval: time.Minute}).sampling.Interval, time.Minute)
+}
+
+func Test_allow(t *testing.T) {
+	aTest := tester.New(t)
+	var ls *logSampler
+	var allowed []bool
+
+	// Test #1. Sampling is disabled.
+	ls = newLogSampler(LogSampling{})
+	for i := 1; i <= 5; i++ {
+		aTest.MustBeEqual(ls.allow("a"), true)
+	}
+
+	// Test #2. Initial events and every 3rd event after them.
+	ls = newLogSampler(LogSampling{Initial: 2, Thereafter: 3, Interval: time.Hour})
+	allowed = nil
+	for i := 1; i <= 8; i++ {
+		allowed = append(allowed, ls.allow("a"))
+	}
+	aTest.MustBeEqual(allowed, []bool{true, true, false, false, true, false, false, true})
+
+	// Kinds are counted separately.
+	aTest.MustBeEqual(ls.allow("b"), true)
+
+	// Test #3. Only initial events.
+	ls = newLogSampler(LogSampling{Initial: 1, Interval: time.Millisecond * 50})
+	aTest.MustBeEqual(ls.allow("a"), true)
+	aTest.MustBeEqual(ls.allow("a"), false)
+
+	// Counters are reset after the interval.
+	time.Sleep(time.Millisecond * 60)
+	aTest.MustBeEqual(ls.allow("a"), true)
+}
@@ -0,0 +1,19 @@
+package core
+
+import "time"
+
+// LogSampling contains settings of sampling of log events. During each
+// interval, the first 'Initial' events of each kind are logged, and after
+// that only every 'Thereafter'-th event of the kind is logged. A zero
+// 'Thereafter' drops all the events after the initial ones. A non-positive
+// interval is replaced with one second. A zero value of the settings disables
+// sampling, i.e. all the events are logged.
+type LogSampling struct {
+	Initial    int
+	Thereafter int
+	Interval   time.Duration
+}
+
+func (ls LogSampling) isDisabled() bool {
+	return (ls.Initial <= 0) && (ls.Thereafter <= 0)
+}
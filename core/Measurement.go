@@ -0,0 +1,45 @@
+package core
+
+import "errors"
+
+// Measurement is a strategy of measuring volume of records' data. A cache
+// without a measurement does not calculate volume.
+type Measurement[D any] interface {
+	// Measure returns the volume of the data. Data which must not be stored
+	// is rejected with an error.
+	Measure(data D) (volume int, err error)
+}
+
+// Bytes is a constraint of data which is measured by its length.
+type Bytes interface {
+	~string | ~[]byte
+}
+
+// ByteLength measures volume of data as its length in bytes. Empty data is
+// rejected.
+type ByteLength[D Bytes] struct{}
+
+// Measure returns the length of the data.
+func (ByteLength[D]) Measure(data D) (volume int, err error) {
+	if len(data) == 0 {
+		return 0, errors.New(ErrDataIsEmpty)
+	}
+
+	return len(data), nil
+}
+
+// Weigher measures volume of data using a custom function.
+type Weigher[D any] func(data D) (volume int)
+
+// Measure returns the weight of the data.
+func (w Weigher[D]) Measure(data D) (volume int, err error) {
+	return w(data), nil
+}
+
+// None does not measure data, volume of any data is zero.
+type None[D any] struct{}
+
+// Measure returns zero.
+func (None[D]) Measure(data D) (volume int, err error) {
+	return 0, nil
+}
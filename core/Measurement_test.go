@@ -0,0 +1,79 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_ByteLength(t *testing.T) {
+	aTest := tester.New(t)
+	var volume int
+	var err error
+
+	// Test #1. Empty data.
+	_, err = ByteLength[string]{}.Measure("")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrDataIsEmpty)
+
+	// Test #2. OK.
+	volume, err = ByteLength[string]{}.Measure("ok")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(volume, 2)
+	volume, err = ByteLength[[]byte]{}.Measure([]byte("abc"))
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(volume, 3)
+}
+
+func Test_Weigher(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test.
+	w := Weigher[[]int](func(data []int) int { return len(data) * 8 })
+	volume, err := w.Measure([]int{1, 2})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(volume, 16)
+}
+
+func Test_None(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test.
+	volume, err := None[string]{}.Measure("abc")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(volume, 0)
+}
+
+func Test_measure(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, []int]
+	var err error
+
+	// Test #1. Cache without a measurement.
+	c, err = New(WithRecordTtl[string, []int](60))
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("A", nil)
+	aTest.MustBeNoError(err)
+	usedVolume, _ := c.GetVolume()
+	aTest.MustBeEqual(usedVolume, 0)
+
+	// Test #2. Custom weigher.
+	c, err = New(
+		WithRecordTtl[string, []int](60),
+		WithVolumeLimit[string, []int](10),
+		WithMeasurement[string, []int](Weigher[[]int](func(data []int) int { return len(data) })),
+	)
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("A", make([]int, 4))
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("B", make([]int, 5))
+	aTest.MustBeNoError(err)
+	usedVolume, _ = c.GetVolume()
+	aTest.MustBeEqual(usedVolume, 9)
+	err = c.AddRecord("C", make([]int, 11))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrRecordIsTooBig)
+	err = c.AddRecord("C", make([]int, 2))
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(c.PeekTopUids(3), []string{"C", "B"})
+}
@@ -0,0 +1,11 @@
+package core
+
+// Operation is a name of an observed operation of the cache.
+type Operation string
+
+const (
+	OperationAddRecord    Operation = "AddRecord"
+	OperationGetRecord    Operation = "GetRecord"
+	OperationRemoveRecord Operation = "RemoveRecord"
+	OperationClear        Operation = "Clear"
+)
@@ -0,0 +1,13 @@
+package core
+
+// OperationResult is a result of an observed operation of the cache.
+type OperationResult struct {
+	Outcome Outcome
+
+	// VolumeDelta is a change of the cache's volume made by the operation,
+	// including the evictions caused by it.
+	VolumeDelta int
+
+	// Err is an error returned by the operation, if any.
+	Err error
+}
@@ -0,0 +1,109 @@
+package core
+
+import "log/slog"
+
+// Option is a functional option of the cache's constructor.
+type Option[U comparable, D any] func(cfg *Config[U, D])
+
+// WithSizeLimit sets the maximum number of records.
+func WithSizeLimit[U comparable, D any](sizeLimit int) Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.SizeLimit = sizeLimit
+	}
+}
+
+// WithVolumeLimit sets the maximum total volume of records.
+func WithVolumeLimit[U comparable, D any](volumeLimit int) Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.VolumeLimit = volumeLimit
+	}
+}
+
+// WithMeasurement sets the strategy of measuring volume of records' data.
+func WithMeasurement[U comparable, D any](measurement Measurement[D]) Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.Measurement = measurement
+	}
+}
+
+// WithRecordTtl sets the records' TTL in seconds.
+func WithRecordTtl[U comparable, D any](recordTtl uint) Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.RecordTtl = recordTtl
+	}
+}
+
+// WithClock sets the source of current time.
+func WithClock[U comparable, D any](clock Clock) Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.Clock = clock
+	}
+}
+
+// WithOnEviction sets the eviction callback.
+func WithOnEviction[U comparable, D any](onEviction func(uid U, data D, reason EvictionReason)) Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.OnEviction = onEviction
+	}
+}
+
+// WithLogger sets the logger and settings of sampling of log events.
+func WithLogger[U comparable, D any](logger *slog.Logger, sampling LogSampling) Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.Logger = logger
+		cfg.LogSampling = sampling
+	}
+}
+
+// WithInstrumentation sets the instrumentation.
+func WithInstrumentation[U comparable, D any](instrumentation Instrumentation[U]) Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.Instrumentation = instrumentation
+	}
+}
+
+// WithStore sets the backing store and the mode of writing to it.
+func WithStore[U comparable, D any](store Store[U, D], writeMode WriteMode, writeBehind WriteBehindSettings) Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.Store = store
+		cfg.WriteMode = writeMode
+		cfg.WriteBehind = writeBehind
+	}
+}
+
+// WithOnStoreError sets the store error callback.
+func WithOnStoreError[U comparable, D any](onStoreError func(uid U, err error)) Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.OnStoreError = onStoreError
+	}
+}
+
+// WithLoader sets the default loader of records.
+func WithLoader[U comparable, D any](loader Loader[U, D]) Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.Loader = loader
+	}
+}
+
+// WithRefreshAhead sets the fraction of the TTL after which a record read
+// from the cache is reloaded in background.
+func WithRefreshAhead[U comparable, D any](refreshAheadFactor float64) Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.RefreshAheadFactor = refreshAheadFactor
+	}
+}
+
+// WithGracePeriod sets the period in seconds after the TTL during which an
+// outdated record may be served as stale.
+func WithGracePeriod[U comparable, D any](gracePeriod uint) Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.GracePeriod = gracePeriod
+	}
+}
+
+// WithStaleIfError enables serving of stale data when the loader fails.
+func WithStaleIfError[U comparable, D any]() Option[U, D] {
+	return func(cfg *Config[U, D]) {
+		cfg.StaleIfError = true
+	}
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_Option(t *testing.T) {
+	aTest := tester.New(t)
+	var cfg Config[string, string]
+	var clock = new(_test_clock)
+	var logger = slog.Default()
+	var ti = new(_test_instrumentation)
+	var evictionsCount int
+	var loadsCount int
+
+	// Test.
+	opts := []Option[string, string]{
+		WithSizeLimit[string, string](1),
+		WithVolumeLimit[string, string](2),
+		WithRecordTtl[string, string](3),
+		WithClock[string, string](clock),
+		WithOnEviction[string, string](func(uid string, data string, reason EvictionReason) { evictionsCount++ }),
+		WithLogger[string, string](logger, LogSampling{Initial: 4}),
+		WithInstrumentation[string, string](ti),
+		WithLoader[string, string](func(ctx context.Context, uid string) (string, error) { loadsCount++; return "", nil }),
+		WithRefreshAhead[string, string](0.5),
+		WithGracePeriod[string, string](6),
+		WithStaleIfError[string, string](),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	aTest.MustBeEqual(cfg.SizeLimit, 1)
+	aTest.MustBeEqual(cfg.VolumeLimit, 2)
+	aTest.MustBeEqual(cfg.RecordTtl, uint(3))
+	aTest.MustBeEqual(cfg.Clock, Clock(clock))
+	cfg.OnEviction("", "", EvictionReasonSize)
+	aTest.MustBeEqual(evictionsCount, 1)
+	aTest.MustBeEqual(cfg.Logger, logger)
+	aTest.MustBeEqual(cfg.LogSampling, LogSampling{Initial: 4})
+	aTest.MustBeEqual(cfg.Instrumentation, Instrumentation[string](ti))
+	_, _ = cfg.Loader(context.Background(), "")
+	aTest.MustBeEqual(loadsCount, 1)
+	aTest.MustBeEqual(cfg.RefreshAheadFactor, 0.5)
+	aTest.MustBeEqual(cfg.GracePeriod, uint(6))
+	aTest.MustBeEqual(cfg.StaleIfError, true)
+}
@@ -0,0 +1,32 @@
+package core
+
+// Outcome is an outcome of an observed operation of the cache.
+type Outcome string
+
+const (
+	// OutcomeAdded means that a new record has been added.
+	OutcomeAdded Outcome = "added"
+
+	// OutcomeUpdated means that an existing record has been updated.
+	OutcomeUpdated Outcome = "updated"
+
+	// OutcomeHit means that an alive record has been found.
+	OutcomeHit Outcome = "hit"
+
+	// OutcomeMiss means that a record has not been found.
+	OutcomeMiss Outcome = "miss"
+
+	// OutcomeExpired means that an outdated record has been found and has
+	// not been returned.
+	OutcomeExpired Outcome = "expired"
+
+	// OutcomeStale means that an outdated record has been found and has been
+	// returned as stale.
+	OutcomeStale Outcome = "stale"
+
+	// OutcomeRemoved means that records have been removed.
+	OutcomeRemoved Outcome = "removed"
+
+	// OutcomeFailed means that the operation has returned an error.
+	OutcomeFailed Outcome = "failed"
+)
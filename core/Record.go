@@ -0,0 +1,186 @@
+package core
+
+// Record is record. Nothing more, nothing less.
+type Record[U comparable, D any] struct {
+	uid            U
+	data           D
+	volume         int
+	lastAccessTime uint
+	updateTime     uint
+	cache          *Cache[U, D]
+	upperRecord    *Record[U, D]
+	lowerRecord    *Record[U, D]
+}
+
+// NewRecord creates a new cache record.
+func NewRecord[U comparable, D any](cache *Cache[U, D], uid U, data D) (rec *Record[U, D], err error) {
+	var measurement Measurement[D]
+	if cache != nil {
+		measurement = cache.measurement
+	}
+
+	return newRecord(cache, measurement, uid, data)
+}
+
+// NewDetachedRecord creates a new record which does not belong to a cache.
+// Its volume is calculated by the measurement, a nil measurement leaves the
+// volume zero.
+func NewDetachedRecord[U comparable, D any](measurement Measurement[D], uid U, data D) (rec *Record[U, D], err error) {
+	return newRecord[U, D](nil, measurement, uid, data)
+}
+
+func newRecord[U comparable, D any](cache *Cache[U, D], measurement Measurement[D], uid U, data D) (rec *Record[U, D], err error) {
+	err = checkUid(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	var volume int
+	if measurement != nil {
+		volume, err = measurement.Measure(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rec = &Record[U, D]{
+		uid:            uid,
+		data:           data,
+		volume:         volume,
+		lastAccessTime: 0, // See below.
+		cache:          cache,
+		upperRecord:    nil,
+		lowerRecord:    nil,
+	}
+
+	rec.touch()
+	rec.updateTime = rec.lastAccessTime
+
+	return rec, nil
+}
+
+func checkUid[U comparable](uid U) (err error) {
+	//TODO: Check for empty value when Go language gets a working type switch
+	// for generics.
+	//switch v := uid.(type) {
+	//case string:
+	//	if len(v) == 0 {
+	//		return errors.New(ErrUidIsEmpty)
+	//	}
+	//case uint:
+	//	if v == 0 {
+	//		return errors.New(ErrUidIsEmpty)
+	//	}
+	//case int:
+	//	if v == 0 {
+	//		return errors.New(ErrUidIsEmpty)
+	//	}
+	//}
+
+	return nil
+}
+
+func (r *Record[U, D]) moveToTop() {
+	if r == r.cache.top {
+		return
+	}
+
+	if r != r.cache.bottom {
+		r.upperRecord.lowerRecord = r.lowerRecord
+		r.lowerRecord.upperRecord = r.upperRecord
+		r.lowerRecord = r.cache.top
+		r.cache.top.upperRecord = r
+		r.upperRecord = nil
+		r.cache.top = r
+	} else {
+		r.cache.bottom = r.upperRecord
+		r.cache.bottom.lowerRecord = nil
+		r.upperRecord = nil
+		r.lowerRecord = r.cache.top
+		r.cache.top.upperRecord = r
+		r.cache.top = r
+	}
+
+	// Size is not changed.
+	// Volume is not changed.
+	// Map is not changed.
+}
+
+func (r *Record[U, D]) touch() {
+	r.lastAccessTime = r.cache.now()
+}
+
+func (r *Record[U, D]) isAlive() bool {
+	return r.cache.now() < r.lastAccessTime+r.cache.recordTtl
+}
+
+// isServable tells whether the record is alive or is in its grace period,
+// i.e. whether its data may still be served.
+func (r *Record[U, D]) isServable() bool {
+	return r.cache.now() < r.lastAccessTime+r.cache.recordTtl+r.cache.gracePeriod
+}
+
+// needsRefresh tells whether the record's data has lived for more than the
+// refresh-ahead fraction of the TTL. The age of data is counted from the last
+// write of the record, as reading a record prolongs its life.
+func (r *Record[U, D]) needsRefresh() bool {
+	if r.cache.refreshAheadFactor == 0 {
+		return false
+	}
+
+	now := r.cache.now()
+	if now < r.updateTime {
+		// The clock has gone backwards.
+		return false
+	}
+
+	age := now - r.updateTime
+	return float64(age) > r.cache.refreshAheadFactor*float64(r.cache.recordTtl)
+}
+
+func (r *Record[U, D]) update(data D) (err error) {
+	var volume int
+	volume, err = r.cache.measure(data)
+	if err != nil {
+		return err
+	}
+
+	oldVolume := r.volume
+	r.data = data
+	r.volume = volume
+
+	r.touch()
+	r.updateTime = r.lastAccessTime
+
+	r.cache.volume += r.volume - oldVolume
+	// Size is not changed.
+	// Map is not changed.
+
+	return nil
+}
+
+func (r *Record[U, D]) unlink() {
+	if r.cache.size == 1 {
+		r.cache.top = nil
+		r.cache.bottom = nil
+	} else if r.cache.top == r {
+		r.cache.top = r.lowerRecord
+		r.cache.top.upperRecord = nil
+		r.upperRecord = nil
+		r.lowerRecord = nil
+	} else if r.cache.bottom == r {
+		r.cache.bottom = r.upperRecord
+		r.cache.bottom.lowerRecord = nil
+		r.upperRecord = nil
+		r.lowerRecord = nil
+	} else { // size is >= 3 and r is a middle record.
+		r.upperRecord.lowerRecord = r.lowerRecord
+		r.lowerRecord.upperRecord = r.upperRecord
+		r.upperRecord = nil
+		r.lowerRecord = nil
+	}
+
+	r.cache.size--
+	r.cache.volume -= r.volume
+	delete(r.cache.recordsByUid, r.uid)
+}
@@ -1,4 +1,4 @@
-package nvl
+package core
 
 import (
 	"testing"
@@ -10,28 +10,56 @@ import (
 func Test_NewRecord(t *testing.T) {
 	aTest := tester.New(t)
 	var err error
-
-	type MyClassA struct {
-		Name string
-		Age  int
-	}
-	var r *Record[string, MyClassA]
+	var r *Record[string, string]
+	var c = _test_new_cache[string, string](0, 0, 60)
 
 	// Test #1. checkUid fails.
 	// TODO: Wait for Go language update for generics.
-	//r, err = NewRecord[string, string](nil, "", "data")
+	//r, err = NewRecord[string, string](c, "", "data")
 	//aTest.MustBeAnError(err)
 	//aTest.MustBeEqual(err.Error(), ErrUidIsEmpty)
 
-	// Test #3. OK.
+	// Test #2. Measurement fails.
+	r, err = NewRecord[string, string](c, "uid", "")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrDataIsEmpty)
 
-	r, err = NewRecord[string, MyClassA](nil, "uid", MyClassA{Name: "John", Age: 123})
+	// Test #3. OK.
+	r, err = NewRecord[string, string](c, "uid", "data")
 	aTest.MustBeNoError(err)
 	aTest.MustBeEqual(r.uid, "uid")
-	aTest.MustBeEqual(r.data, MyClassA{Name: "John", Age: 123})
-	aTest.MustBeEqual(r.cache, (*Cache[string, MyClassA])(nil))
-	aTest.MustBeEqual(r.upperRecord, (*Record[string, MyClassA])(nil))
-	aTest.MustBeEqual(r.lowerRecord, (*Record[string, MyClassA])(nil))
+	aTest.MustBeEqual(r.data, "data")
+	aTest.MustBeEqual(r.volume, 4)
+	aTest.MustBeEqual(r.cache, c)
+	aTest.MustBeEqual(r.upperRecord, (*Record[string, string])(nil))
+	aTest.MustBeEqual(r.lowerRecord, (*Record[string, string])(nil))
+
+	// Test #4. Detached record is not measured.
+	r, err = NewRecord[string, string](nil, "uid", "")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(r.volume, 0)
+}
+
+func Test_NewDetachedRecord(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+	var r *Record[string, string]
+
+	// Test #1. Measurement fails.
+	_, err = NewDetachedRecord[string, string](ByteLength[string]{}, "uid", "")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrDataIsEmpty)
+
+	// Test #2. OK.
+	r, err = NewDetachedRecord[string, string](ByteLength[string]{}, "uid", "data")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(r.volume, 4)
+	aTest.MustBeEqual(r.cache, (*Cache[string, string])(nil))
+
+	// Test #3. No measurement.
+	r, err = NewDetachedRecord[string, string](nil, "uid", "")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(r.volume, 0)
 }
 
 func Test_checkUid(t *testing.T) {
@@ -84,7 +112,7 @@ func Test_isAlive(t *testing.T) {
 	aTest := tester.New(t)
 	var c *Cache[string, string]
 	var err error
-	c = NewCache[string, string](0, 1)
+	c = _test_new_cache[string, string](0, 0, 1)
 	err = c.AddRecord("A", "1")
 	aTest.MustBeNoError(err)
 
@@ -96,17 +124,84 @@ func Test_isAlive(t *testing.T) {
 	aTest.MustBeEqual(c.top.isAlive(), false)
 }
 
+func Test_isServable(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	var err error
+	c = _test_new_cache[string, string](0, 0, 10)
+	c.clock = clock
+	c.gracePeriod = 5
+	err = c.AddRecord("A", "1")
+	aTest.MustBeNoError(err)
+
+	// Test #1. Alive record.
+	aTest.MustBeEqual(c.top.isServable(), true)
+
+	// Test #2. Record in its grace period.
+	clock.Add(time.Second * 14)
+	aTest.MustBeEqual(c.top.isAlive(), false)
+	aTest.MustBeEqual(c.top.isServable(), true)
+
+	// Test #3. Grace period is over.
+	clock.Add(time.Second)
+	aTest.MustBeEqual(c.top.isServable(), false)
+}
+
+func Test_needsRefresh(t *testing.T) {
+	aTest := tester.New(t)
+	var c *Cache[string, string]
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	var err error
+	c = _test_new_cache[string, string](0, 0, 10)
+	c.clock = clock
+	err = c.AddRecord("A", "1")
+	aTest.MustBeNoError(err)
+
+	// Test #1. Refresh-ahead is disabled.
+	clock.Add(time.Second * 9)
+	aTest.MustBeEqual(c.top.needsRefresh(), false)
+
+	// Test #2. Reading does not make data younger.
+	c.refreshAheadFactor = 0.5
+	c.top.touch()
+	aTest.MustBeEqual(c.top.needsRefresh(), true)
+
+	// Test #3. Writing does.
+	c.top.update("2")
+	aTest.MustBeEqual(c.top.needsRefresh(), false)
+	clock.Add(time.Second * 5)
+	aTest.MustBeEqual(c.top.needsRefresh(), false)
+	clock.Add(time.Second)
+	aTest.MustBeEqual(c.top.needsRefresh(), true)
+
+	// Test #4. Clock goes backwards.
+	c.top.update("3")
+	clock.Add(-time.Second * 5)
+	aTest.MustBeEqual(c.top.needsRefresh(), false)
+}
+
 func Test_update(t *testing.T) {
 	aTest := tester.New(t)
 	var c *Cache[string, string]
 	var err error
-	c = NewCache[string, string](0, 60)
+	c = _test_new_cache[string, string](0, 0, 60)
 	err = c.AddRecord("A", "1")
 	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(c.volume, 1)
 
 	// Test #1.
-	c.top.update("333")
+	err = c.top.update("333")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(c.top.data, "333")
+	aTest.MustBeEqual(c.volume, 3)
+
+	// Test #2. Measurement fails.
+	err = c.top.update("")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrDataIsEmpty)
 	aTest.MustBeEqual(c.top.data, "333")
+	aTest.MustBeEqual(c.volume, 3)
 }
 
 func Test_unlink(t *testing.T) {
@@ -0,0 +1,23 @@
+package core
+
+// Stats contains statistics of the cache's usage.
+type Stats struct {
+	// Hits is a number of read requests which have found an alive record.
+	Hits uint64
+
+	// Misses is a number of read requests which have not found an alive
+	// record.
+	Misses uint64
+
+	// ExpiredOnRead is a number of read requests which have found an
+	// outdated record.
+	ExpiredOnRead uint64
+
+	// StaleHits is a number of read requests which have been served with
+	// data of an outdated record in its grace period.
+	StaleHits uint64
+
+	// Evictions is a number of records removed by the cache itself, grouped
+	// by the reason of removal.
+	Evictions map[EvictionReason]uint64
+}
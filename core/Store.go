@@ -0,0 +1,11 @@
+package core
+
+import "context"
+
+// Store is a backing store, e.g. a key-value database, which the cache is
+// placed in front of.
+type Store[U comparable, D any] interface {
+	Load(ctx context.Context, uid U) (data D, err error)
+	Store(ctx context.Context, uid U, data D) (err error)
+	Delete(ctx context.Context, uid U) (err error)
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// tryLocker is a lock which may be acquired without waiting.
+type tryLocker interface {
+	sync.Locker
+	TryLock() bool
+}
+
+// lockWithContext acquires the lock. If the context is done before the lock
+// is acquired, an error is returned and the lock is released as soon as it is
+// acquired by the abandoned attempt. A context which is never done waits for
+// the lock as usual.
+func lockWithContext(ctx context.Context, l tryLocker) (err error) {
+	if ctx.Done() == nil {
+		l.Lock()
+		return nil
+	}
+
+	err = ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	if l.TryLock() {
+		return nil
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		l.Lock()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		return nil
+	case <-ctx.Done():
+		go func() {
+			<-acquired
+			l.Unlock()
+		}()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,196 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// writeBehind is a queue of records which are written to the store
+// asynchronously. It has its own lock, so that the cache is not locked while
+// the store is being written.
+type writeBehind[U comparable, D any] struct {
+	store     Store[U, D]
+	settings  WriteBehindSettings
+	onError   func(uid U, err error)
+	pending   map[U]*pendingWrite[D]
+	inFlight  map[U]*pendingWrite[D]
+	lock      *sync.Mutex
+	flushLock *sync.Mutex
+	wake      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	stopOnce  *sync.Once
+}
+
+// pendingWrite is a queued write or deletion of a record.
+type pendingWrite[D any] struct {
+	data       D
+	isDeletion bool
+	failures   int
+}
+
+func newWriteBehind[U comparable, D any](store Store[U, D], settings WriteBehindSettings, onError func(uid U, err error)) (wb *writeBehind[U, D]) {
+	wb = &writeBehind[U, D]{
+		store:     store,
+		settings:  settings,
+		onError:   onError,
+		pending:   make(map[U]*pendingWrite[D]),
+		inFlight:  make(map[U]*pendingWrite[D]),
+		lock:      new(sync.Mutex),
+		flushLock: new(sync.Mutex),
+		wake:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		stopOnce:  new(sync.Once),
+	}
+
+	go wb.run()
+
+	return wb
+}
+
+// put queues a write of a record. A newer write of the same UID replaces the
+// older one.
+func (wb *writeBehind[U, D]) put(uid U, data D) {
+	wb.enqueue(uid, &pendingWrite[D]{data: data})
+}
+
+// delete queues a deletion of a record.
+func (wb *writeBehind[U, D]) delete(uid U) {
+	wb.enqueue(uid, &pendingWrite[D]{isDeletion: true})
+}
+
+func (wb *writeBehind[U, D]) enqueue(uid U, pw *pendingWrite[D]) {
+	wb.lock.Lock()
+	wb.pending[uid] = pw
+	n := len(wb.pending)
+	wb.lock.Unlock()
+
+	if (wb.settings.BatchSize > 0) && (n >= wb.settings.BatchSize) {
+		wb.wakeUp()
+	}
+}
+
+// isDirty checks whether a record has a queued write.
+func (wb *writeBehind[U, D]) isDirty(uid U) bool {
+	wb.lock.Lock()
+	defer wb.lock.Unlock()
+
+	_, isQueued := wb.pending[uid]
+	return isQueued
+}
+
+// peek returns a copy of a queued write of a record. A write which is being
+// flushed is also returned, as the store may not have it yet.
+func (wb *writeBehind[U, D]) peek(uid U) (pw pendingWrite[D], isQueued bool) {
+	wb.lock.Lock()
+	defer wb.lock.Unlock()
+
+	var queued *pendingWrite[D]
+	queued, isQueued = wb.pending[uid]
+	if !isQueued {
+		queued, isQueued = wb.inFlight[uid]
+	}
+	if !isQueued {
+		return pw, false
+	}
+
+	return *queued, true
+}
+
+// wakeUp requests a flush without waiting for the end of the interval.
+func (wb *writeBehind[U, D]) wakeUp() {
+	select {
+	case wb.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (wb *writeBehind[U, D]) run() {
+	defer close(wb.done)
+
+	ticker := time.NewTicker(wb.settings.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-wb.stop:
+			return
+		case <-ticker.C:
+		case <-wb.wake:
+		}
+
+		_ = wb.flush(context.Background())
+	}
+}
+
+// flush writes all the queued records to the store. Records being written
+// stay visible to the peek method until their writes are finished. Failed
+// writes are queued again unless they have been replaced by newer ones or
+// have exhausted their retries. Flushes are serialized, so that writes of the
+// same UID are not reordered.
+func (wb *writeBehind[U, D]) flush(ctx context.Context) (err error) {
+	wb.flushLock.Lock()
+	defer wb.flushLock.Unlock()
+
+	wb.lock.Lock()
+	batch := wb.pending
+	wb.pending = make(map[U]*pendingWrite[D])
+	wb.inFlight = batch
+	wb.lock.Unlock()
+
+	var errs []error
+	for uid, pw := range batch {
+		var e error
+		if pw.isDeletion {
+			e = wb.store.Delete(ctx, uid)
+		} else {
+			e = wb.store.Store(ctx, uid, pw.data)
+		}
+		if e != nil {
+			errs = append(errs, e)
+		}
+
+		wb.finish(uid, pw, e)
+	}
+
+	return errors.Join(errs...)
+}
+
+// finish removes a flushed write from the in-flight writes. A failed write is
+// queued again in the same critical section, so that it does not disappear
+// from the peek method.
+func (wb *writeBehind[U, D]) finish(uid U, pw *pendingWrite[D], err error) {
+	wb.lock.Lock()
+	delete(wb.inFlight, uid)
+	if err == nil {
+		wb.lock.Unlock()
+		return
+	}
+
+	pw.failures++
+	isExhausted := pw.failures > wb.settings.MaxRetries
+	if !isExhausted {
+		_, isReplaced := wb.pending[uid]
+		if !isReplaced {
+			wb.pending[uid] = pw
+		}
+	}
+	wb.lock.Unlock()
+
+	if isExhausted && (wb.onError != nil) {
+		wb.onError(uid, err)
+	}
+}
+
+// close stops the flusher and flushes the queue for the last time.
+func (wb *writeBehind[U, D]) close() (err error) {
+	wb.stopOnce.Do(func() {
+		close(wb.stop)
+	})
+	<-wb.done
+
+	return wb.flush(context.Background())
+}
@@ -0,0 +1,37 @@
+package core
+
+import (
+	"errors"
+	"time"
+)
+
+// WriteBehindSettings contains settings of the write-behind mode.
+type WriteBehindSettings struct {
+	// FlushInterval is a period between flushes of queued records.
+	FlushInterval time.Duration
+
+	// BatchSize is a number of queued records which triggers a flush before
+	// the end of the interval. Zero disables early flushes.
+	BatchSize int
+
+	// MaxRetries is a number of retries of a failed write. A write which
+	// has failed more times is dropped and its error is passed to the store
+	// error callback.
+	MaxRetries int
+}
+
+func (s WriteBehindSettings) validate() (err error) {
+	if s.FlushInterval <= 0 {
+		return errors.New(ErrFlushIntervalIsNotPositive)
+	}
+
+	if s.BatchSize < 0 {
+		return errors.New(ErrBatchSizeIsNegative)
+	}
+
+	if s.MaxRetries < 0 {
+		return errors.New(ErrMaxRetriesIsNegative)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_WriteBehindSettings_validate(t *testing.T) {
+	aTest := tester.New(t)
+	var s WriteBehindSettings
+
+	// Test #1. Bad settings.
+	aTest.MustBeEqual(s.validate().Error(), ErrFlushIntervalIsNotPositive)
+	s.FlushInterval = time.Second
+	s.BatchSize = -1
+	aTest.MustBeEqual(s.validate().Error(), ErrBatchSizeIsNegative)
+	s.BatchSize = 0
+	s.MaxRetries = -1
+	aTest.MustBeEqual(s.validate().Error(), ErrMaxRetriesIsNegative)
+
+	// Test #2. OK.
+	s.MaxRetries = 0
+	aTest.MustBeNoError(s.validate())
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_writeBehind(t *testing.T) {
+	aTest := tester.New(t)
+	var ts = _test_new_store()
+	var dropped []string
+	var wb *writeBehind[string, string]
+	var err error
+
+	wb = newWriteBehind[string, string](ts, WriteBehindSettings{FlushInterval: time.Hour, MaxRetries: 1},
+		func(uid string, err error) { dropped = append(dropped, uid) })
+	defer func() { _ = wb.close() }()
+
+	// Test #1. Newer writes replace older ones.
+	wb.put("A", "1")
+	wb.put("A", "2")
+	wb.put("B", "3")
+	wb.delete("B")
+	aTest.MustBeEqual(wb.isDirty("A"), true)
+	aTest.MustBeEqual(wb.isDirty("Q"), false)
+	pw, isQueued := wb.peek("B")
+	aTest.MustBeEqual(isQueued, true)
+	aTest.MustBeEqual(pw.isDeletion, true)
+
+	err = wb.flush(context.Background())
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(ts.writes, 2)
+	data, _ := ts.get("A")
+	aTest.MustBeEqual(data, "2")
+	aTest.MustBeEqual(wb.isDirty("A"), false)
+
+	// Test #2. Failed write is retried.
+	ts.setFailures(1)
+	wb.put("C", "4")
+	err = wb.flush(context.Background())
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(wb.isDirty("C"), true)
+	err = wb.flush(context.Background())
+	aTest.MustBeNoError(err)
+	data, _ = ts.get("C")
+	aTest.MustBeEqual(data, "4")
+
+	// Test #3. Failed write is dropped after retries.
+	ts.setFailures(2)
+	wb.put("D", "5")
+	_ = wb.flush(context.Background())
+	_ = wb.flush(context.Background())
+	aTest.MustBeEqual(wb.isDirty("D"), false)
+	aTest.MustBeEqual(dropped, []string{"D"})
+
+	// Test #4. Failed write is not retried when it is replaced.
+	ts.setFailures(1)
+	wb.put("E", "6")
+	wb.lock.Lock()
+	batch := wb.pending
+	wb.pending = map[string]*pendingWrite[string]{"E": {data: "7"}}
+	wb.lock.Unlock()
+	wb.finish("E", batch["E"], errors.New("store failure"))
+	pw, _ = wb.peek("E")
+	aTest.MustBeEqual(pw.data, "7")
+	ts.setFailures(0)
+	err = wb.flush(context.Background())
+	aTest.MustBeNoError(err)
+
+	// Test #5. Write being flushed is visible until it is finished.
+	ts.gate = make(chan struct{})
+	wb.put("F", "8")
+	flushed := make(chan error)
+	go func() { flushed <- wb.flush(context.Background()) }()
+	for !_test_is_in_flight(wb, "F") {
+		time.Sleep(time.Millisecond)
+	}
+	aTest.MustBeEqual(wb.isDirty("F"), false)
+	pw, isQueued = wb.peek("F")
+	aTest.MustBeEqual(isQueued, true)
+	aTest.MustBeEqual(pw.data, "8")
+	close(ts.gate)
+	aTest.MustBeNoError(<-flushed)
+	_, isQueued = wb.peek("F")
+	aTest.MustBeEqual(isQueued, false)
+}
+
+func Test_writeBehind_run(t *testing.T) {
+	aTest := tester.New(t)
+	var ts = _test_new_store()
+	var wb *writeBehind[string, string]
+
+	// Test #1. Batch size triggers a flush.
+	wb = newWriteBehind[string, string](ts, WriteBehindSettings{FlushInterval: time.Hour, BatchSize: 2}, nil)
+	wb.put("A", "1")
+	wb.put("B", "2")
+	time.Sleep(time.Millisecond * 100)
+	_, ok := ts.get("B")
+	aTest.MustBeEqual(ok, true)
+
+	// Test #2. Close flushes the queue and may be repeated.
+	wb.put("C", "3")
+	aTest.MustBeNoError(wb.close())
+	aTest.MustBeNoError(wb.close())
+	_, ok = ts.get("C")
+	aTest.MustBeEqual(ok, true)
+
+	// Test #3. Interval triggers a flush.
+	wb = newWriteBehind[string, string](ts, WriteBehindSettings{FlushInterval: time.Millisecond * 10}, nil)
+	defer func() { _ = wb.close() }()
+	wb.put("D", "4")
+	time.Sleep(time.Millisecond * 100)
+	_, ok = ts.get("D")
+	aTest.MustBeEqual(ok, true)
+}
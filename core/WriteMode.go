@@ -0,0 +1,14 @@
+package core
+
+// WriteMode is a mode of writing records to the store.
+type WriteMode byte
+
+const (
+	// WriteModeThrough writes records to the store synchronously, while the
+	// cache is locked.
+	WriteModeThrough WriteMode = iota
+
+	// WriteModeBehind queues records and writes them to the store
+	// asynchronously in batches.
+	WriteModeBehind
+)
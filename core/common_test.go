@@ -0,0 +1,345 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func _test_ensure_order_3_records[U comparable](
+	cache *Cache[U, string],
+	uids [3]U, // [0] is top, [2] is bottom.
+	datas [3]string, // [0] is top, [2] is bottom.
+) (ok bool) {
+	var r1, r2, r3 *Record[U, string]
+
+	r1 = cache.top
+	r2 = r1.lowerRecord
+	r3 = cache.bottom
+
+	// Check links & order.
+	if (r1.upperRecord != nil) || (r1.lowerRecord != r2) ||
+		(r2.upperRecord != r1) || (r2.lowerRecord != r3) ||
+		(r3.upperRecord != r2) || (r3.lowerRecord != nil) {
+		return false
+	}
+
+	// Check UIDs.
+	if (r1.uid != uids[0]) ||
+		(r2.uid != uids[1]) ||
+		(r3.uid != uids[2]) {
+		return false
+	}
+
+	// Check Data.
+	if (r1.data != datas[0]) ||
+		(r2.data != datas[1]) ||
+		(r3.data != datas[2]) {
+		return false
+	}
+
+	// Check Map.
+	if (cache.recordsByUid[uids[0]].data != datas[0]) ||
+		(cache.recordsByUid[uids[1]].data != datas[1]) ||
+		(cache.recordsByUid[uids[2]].data != datas[2]) ||
+		(len(cache.recordsByUid) != 3) {
+		return false
+	}
+
+	return true
+}
+
+func _test_ensure_order_2_records[U comparable](
+	cache *Cache[U, string],
+	uids [2]U, // [0] is top, [1] is bottom.
+	datas [2]string, // [0] is top, [1] is bottom.
+) (ok bool) {
+	var r1, r2 *Record[U, string]
+
+	r1 = cache.top
+	r2 = cache.bottom
+
+	// Check links & order.
+	if (r1.upperRecord != nil) || (r1.lowerRecord != r2) ||
+		(r2.upperRecord != r1) || (r2.lowerRecord != nil) {
+		return false
+	}
+
+	// Check UIDs.
+	if (r1.uid != uids[0]) ||
+		(r2.uid != uids[1]) {
+		return false
+	}
+
+	// Check Data.
+	if (r1.data != datas[0]) ||
+		(r2.data != datas[1]) {
+		return false
+	}
+
+	// Check Map.
+	if (cache.recordsByUid[uids[0]].data != datas[0]) ||
+		(cache.recordsByUid[uids[1]].data != datas[1]) ||
+		(len(cache.recordsByUid) != 2) {
+		return false
+	}
+
+	return true
+}
+
+func _test_ensure_order_1_record[U comparable](
+	cache *Cache[U, string],
+	uid U,
+	data string,
+) (ok bool) {
+	var r1 *Record[U, string]
+
+	r1 = cache.top
+
+	if cache.top != cache.bottom {
+		return false
+	}
+
+	// Check links & order.
+	if (r1.upperRecord != nil) || (r1.lowerRecord != nil) {
+		return false
+	}
+
+	// Check UIDs.
+	if r1.uid != uid {
+		return false
+	}
+
+	// Check Data.
+	if r1.data != data {
+		return false
+	}
+
+	// Check Map.
+	if (cache.recordsByUid[uid].data != data) ||
+		(len(cache.recordsByUid) != 1) {
+		return false
+	}
+
+	return true
+}
+
+func _test_ensure_order_0_records[U comparable](
+	cache *Cache[U, string],
+) (ok bool) {
+
+	if (cache.top != nil) ||
+		(cache.bottom != nil) {
+		return false
+	}
+
+	// Check Map.
+	if len(cache.recordsByUid) != 0 {
+		return false
+	}
+
+	return true
+}
+
+func _test_prepare_ABC_cache(aTest *tester.Test) (c *Cache[string, string]) {
+	var err error
+	c = _test_new_cache[string, string](0, 0, 60)
+	err = c.AddRecord("C", "3")
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("B", "2")
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("A", "1")
+	aTest.MustBeNoError(err)
+	return c
+}
+
+func _test_prepare_ABC_cache_with_low_ttl(aTest *tester.Test) (c *Cache[string, string]) {
+	var err error
+	c = _test_new_cache[string, string](0, 0, 3)
+	err = c.AddRecord("C", "3")
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("B", "2")
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("A", "1")
+	aTest.MustBeNoError(err)
+	return c
+}
+
+func _test_prepare_AB_cache(aTest *tester.Test) (c *Cache[string, string]) {
+	var err error
+	c = _test_new_cache[string, string](0, 0, 60)
+	err = c.AddRecord("B", "2")
+	aTest.MustBeNoError(err)
+	err = c.AddRecord("A", "1")
+	aTest.MustBeNoError(err)
+	return c
+}
+
+func _test_prepare_A_cache(aTest *tester.Test) (c *Cache[string, string]) {
+	var err error
+	c = _test_new_cache[string, string](0, 0, 60)
+	err = c.AddRecord("A", "1")
+	aTest.MustBeNoError(err)
+	return c
+}
+
+func _test_prepare_0_cache() (c *Cache[string, string]) {
+	c = _test_new_cache[string, string](0, 0, 60)
+	return c
+}
+
+func _test_prepare_record_Q(aTest *tester.Test, c *Cache[string, string]) (r *Record[string, string]) {
+	var err error
+	r, err = NewRecord(c, "Q", "W")
+	aTest.MustBeNoError(err)
+	return r
+}
+
+// _test_instrumentation records observed operations as text lines.
+type _test_instrumentation struct {
+	events []string
+}
+
+type _test_ctx_key struct{}
+
+func (ti *_test_instrumentation) StartOperation(ctx context.Context, op Operation, uid string) context.Context {
+	ti.events = append(ti.events, fmt.Sprintf("start %s %s", op, uid))
+	return context.WithValue(ctx, _test_ctx_key{}, op)
+}
+
+func (ti *_test_instrumentation) EndOperation(ctx context.Context, op Operation, uid string, r OperationResult) {
+	ti.events = append(ti.events, fmt.Sprintf("end %s %s %s %d %v %v", op, uid, r.Outcome, r.VolumeDelta, r.Err != nil, ctx.Value(_test_ctx_key{}) == op))
+}
+
+// _test_clock is a clock which is moved manually.
+type _test_clock struct {
+	lock sync.Mutex
+	t    time.Time
+}
+
+func (tc *_test_clock) Now() time.Time {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+
+	return tc.t
+}
+
+func (tc *_test_clock) Add(d time.Duration) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+
+	tc.t = tc.t.Add(d)
+}
+
+// _test_store is an in-memory store. When the gate is set, writes wait for
+// it to be closed.
+type _test_store struct {
+	lock     sync.Mutex
+	records  map[string]string
+	failures int
+	writes   int
+	gate     chan struct{}
+}
+
+func _test_new_store() (ts *_test_store) {
+	return &_test_store{records: make(map[string]string)}
+}
+
+func (ts *_test_store) fail() (err error) {
+	if ts.failures > 0 {
+		ts.failures--
+		return errors.New("store failure")
+	}
+	return nil
+}
+
+func (ts *_test_store) Load(ctx context.Context, uid string) (data string, err error) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	var ok bool
+	data, ok = ts.records[uid]
+	if !ok {
+		return data, errors.New("not found in store")
+	}
+	return data, nil
+}
+
+func (ts *_test_store) Store(ctx context.Context, uid string, data string) (err error) {
+	if ts.gate != nil {
+		<-ts.gate
+	}
+
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	err = ts.fail()
+	if err != nil {
+		return err
+	}
+	ts.records[uid] = data
+	ts.writes++
+	return nil
+}
+
+func (ts *_test_store) Delete(ctx context.Context, uid string) (err error) {
+	if ts.gate != nil {
+		<-ts.gate
+	}
+
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	err = ts.fail()
+	if err != nil {
+		return err
+	}
+	delete(ts.records, uid)
+	ts.writes++
+	return nil
+}
+
+func (ts *_test_store) get(uid string) (data string, ok bool) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	data, ok = ts.records[uid]
+	return data, ok
+}
+
+func (ts *_test_store) setFailures(n int) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	ts.failures = n
+}
+
+// _test_new_cache creates a cache which measures data by its length, as the
+// cache of the 'vl' package does. It panics if the settings are not valid.
+func _test_new_cache[U comparable, D Bytes](sizeLimit int, volumeLimit int, recordTtl uint) (cache *Cache[U, D]) {
+	var err error
+	cache, err = NewFromConfig(Config[U, D]{
+		SizeLimit:   sizeLimit,
+		VolumeLimit: volumeLimit,
+		RecordTtl:   recordTtl,
+		Measurement: ByteLength[D]{},
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return cache
+}
+
+// _test_is_in_flight checks whether a write of the record is being flushed.
+func _test_is_in_flight(wb *writeBehind[string, string], uid string) bool {
+	wb.lock.Lock()
+	defer wb.lock.Unlock()
+
+	_, ok := wb.inFlight[uid]
+	return ok
+}
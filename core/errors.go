@@ -0,0 +1,21 @@
+package core
+
+const (
+	ErrBottomRecordDoesNotExist    = "bottom record does not exist"
+	ErrUidIsEmpty                  = "UID is empty" //TODO
+	ErrDataIsEmpty                 = "data is empty"
+	ErrRecordIsNotFound            = `record is not found, uid=%v`
+	ErrRecordIsOutdated            = `record is outdated, uid=%v`
+	ErrRecordIsTooBig              = "record is too big"
+	ErrTtlIsZero                   = "zero TTL will totally disable the cache"
+	ErrLoaderIsNotSet              = "loader is not set"
+	ErrSizeLimitIsNegative         = "size limit is negative"
+	ErrVolumeLimitIsNegative       = "volume limit is negative"
+	ErrStoreIsNotSet               = "store is not set"
+	ErrWriteModeIsUnknown          = "write mode is unknown"
+	ErrFlushIntervalIsNotPositive  = "flush interval is not positive"
+	ErrBatchSizeIsNegative         = "batch size is negative"
+	ErrMaxRetriesIsNegative        = "max retries is negative"
+	ErrRefreshAheadFactorIsInvalid = "refresh-ahead factor is invalid"
+	ErrMeasurementIsNotSet         = "measurement is not set"
+)
@@ -0,0 +1,8 @@
+package core
+
+const (
+	logMsgRecordIsEvicted      = "record is evicted"
+	logMsgRecordIsExpired      = "record is expired"
+	logMsgRecordIsNotRefreshed = "record is not refreshed"
+	logMsgRecordIsRejected     = "record is rejected"
+)
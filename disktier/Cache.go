@@ -0,0 +1,197 @@
+package disktier
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+// Cache is a two-tier cache. Records evicted from the bottom of the memory
+// tier to fit its size or volume limit are demoted to the disk tier, and
+// records which are missing in the memory tier are promoted back from the
+// disk tier. Outdated records of the memory tier are not demoted. A record
+// is stored in at most one tier at a time.
+type Cache[U vl.UidType, D vl.DataType] struct {
+	memory  *vl.Cache[U, D]
+	disk    *Disk[U, D]
+	onError func(err error)
+
+	// lock makes composite operations over both tiers atomic.
+	lock *sync.Mutex
+}
+
+// Open creates a two-tier cache. The memory tier is created using its
+// configuration, its eviction callback is still called for all evictions.
+// The disk tier is opened using its settings, records which have been stored
+// on the disk before are available. If the TTL of the disk tier is not set,
+// the TTL of the memory tier is used.
+func Open[U vl.UidType, D vl.DataType](memory vl.Config[U, D], disk Settings) (c *Cache[U, D], err error) {
+	if disk.RecordTtl == 0 {
+		disk.RecordTtl = memory.RecordTtl
+	}
+
+	c = &Cache[U, D]{
+		onError: disk.OnError,
+		lock:    new(sync.Mutex),
+	}
+
+	c.disk, err = OpenDisk[U, D](disk)
+	if err != nil {
+		return nil, err
+	}
+
+	onEviction := memory.OnEviction
+	memory.OnEviction = func(uid U, data D, reason vl.EvictionReason) {
+		if reason != vl.EvictionReasonExpired {
+			c.demote(uid, data)
+		}
+
+		if onEviction != nil {
+			onEviction(uid, data, reason)
+		}
+	}
+
+	c.memory, err = vl.NewFromConfig(memory)
+	if err != nil {
+		_ = c.disk.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// demote writes a record evicted from the memory tier to the disk tier. It is
+// called while the memory tier is locked.
+func (c *Cache[U, D]) demote(uid U, data D) {
+	err := c.disk.Put(uid, data)
+	if err != nil {
+		c.reportError(fmt.Errorf(ErrDemotionIsFailed, uid, err))
+	}
+}
+
+func (c *Cache[U, D]) reportError(err error) {
+	if c.onError != nil {
+		c.onError(err)
+	}
+}
+
+// Memory returns the memory tier.
+func (c *Cache[U, D]) Memory() (memory *vl.Cache[U, D]) {
+	return c.memory
+}
+
+// Disk returns the disk tier.
+func (c *Cache[U, D]) Disk() (disk *Disk[U, D]) {
+	return c.disk
+}
+
+// RecordExists checks whether the specified record exists in any tier. It
+// does not promote the record.
+func (c *Cache[U, D]) RecordExists(uid U) (recordExists bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.memory.RecordExists(uid) || c.disk.RecordExists(uid)
+}
+
+// AddRecord adds a record to the memory tier. A copy of the record stored
+// in the disk tier is removed as outdated.
+func (c *Cache[U, D]) AddRecord(uid U, data D) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, err = c.disk.Delete(uid)
+	if err != nil {
+		return err
+	}
+
+	return c.memory.AddRecord(uid, data)
+}
+
+// GetRecord reads a record from the memory tier. If the record is missing
+// there, it is taken from the disk tier and is added to the memory tier.
+// Promotion of a record may demote other records to the disk tier.
+func (c *Cache[U, D]) GetRecord(uid U) (data D, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	data, err = c.memory.GetRecord(uid)
+	if err == nil {
+		return data, nil
+	}
+
+	var diskErr error
+	data, diskErr = c.disk.Take(uid)
+	if diskErr != nil {
+		return data, err
+	}
+
+	err = c.memory.AddRecord(uid, data)
+	if err != nil {
+		// The record does not fit the memory tier any more, e.g. when its
+		// volume limit has been lowered, so it is returned to the disk.
+		c.demote(uid, data)
+	}
+
+	return data, nil
+}
+
+// RemoveRecord removes a record from both tiers. An error of the disk tier
+// is passed to the error callback.
+func (c *Cache[U, D]) RemoveRecord(uid U) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.memory.RemoveRecord(uid)
+
+	_, err := c.disk.Delete(uid)
+	if err != nil {
+		c.reportError(fmt.Errorf(ErrRemovalIsFailed, uid, err))
+	}
+}
+
+// RemoveExistingRecord removes a record which exists in any tier.
+func (c *Cache[U, D]) RemoveExistingRecord(uid U) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	memoryErr := c.memory.RemoveExistingRecord(uid)
+
+	var recExists bool
+	recExists, err = c.disk.Delete(uid)
+	if err != nil {
+		return err
+	}
+
+	if (memoryErr != nil) && !recExists {
+		return memoryErr
+	}
+
+	return nil
+}
+
+// Clear removes all records from both tiers. Records of the memory tier are
+// not demoted.
+func (c *Cache[U, D]) Clear() (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	err = c.memory.Clear()
+	if err != nil {
+		return err
+	}
+
+	return c.disk.Clear()
+}
+
+// Close closes the memory tier and the disk tier. Records of the memory tier
+// are not demoted, so they are lost. The cache must not be used after
+// closing.
+func (c *Cache[U, D]) Close() (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return errors.Join(c.memory.Close(), c.disk.Close())
+}
@@ -0,0 +1,139 @@
+package disktier
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_Open(t *testing.T) {
+	aTest := tester.New(t)
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	var s = _test_settings(t, clock)
+	var c *Cache[string, string]
+	var err error
+
+	// Test #1. Bad settings.
+	_, err = Open[string, string](vl.Config[string, string]{}, s)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), vl.ErrTtlIsZero)
+	_, err = Open[string, string](vl.Config[string, string]{RecordTtl: 60}, Settings{})
+	aTest.MustBeAnError(err)
+
+	// Test #2. TTL of the memory tier is used by default.
+	s.RecordTtl = 0
+	c, err = Open[string, string](vl.Config[string, string]{RecordTtl: 30}, s)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(c.Disk().settings.RecordTtl, uint(30))
+	aTest.MustBeEqual(c.Memory().GetTtl(), uint(30))
+	aTest.MustBeNoError(c.Close())
+}
+
+func Test_Cache(t *testing.T) {
+	aTest := tester.New(t)
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	var s = _test_settings(t, clock)
+	var c *Cache[string, string]
+	var evictions []vl.EvictionReason
+	var data string
+	var err error
+
+	memory := vl.Config[string, string]{
+		SizeLimit: 2,
+		RecordTtl: 60,
+		Clock:     clock,
+		OnEviction: func(uid string, data string, reason vl.EvictionReason) {
+			evictions = append(evictions, reason)
+		},
+	}
+	c, err = Open(memory, s)
+	aTest.MustBeNoError(err)
+
+	// Test #1. Evicted record is demoted.
+	aTest.MustBeNoError(c.AddRecord("A", "1"))
+	aTest.MustBeNoError(c.AddRecord("B", "2"))
+	aTest.MustBeNoError(c.AddRecord("C", "3"))
+	aTest.MustBeEqual(evictions, []vl.EvictionReason{vl.EvictionReasonSize})
+	aTest.MustBeEqual(c.Memory().RecordExists("A"), false)
+	aTest.MustBeEqual(c.Disk().RecordExists("A"), true)
+	aTest.MustBeEqual(c.RecordExists("A"), true)
+
+	// Test #2. Missing record is promoted.
+	data, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "1")
+	aTest.MustBeEqual(c.Memory().PeekTopUids(2), []string{"A", "C"})
+	aTest.MustBeEqual(c.Disk().RecordExists("A"), false)
+	aTest.MustBeEqual(c.Disk().RecordExists("B"), true)
+
+	// Test #3. Absent record.
+	_, err = c.GetRecord("Q")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "record is not found, uid=Q")
+
+	// Test #4. Added record replaces its copy on the disk.
+	aTest.MustBeNoError(c.AddRecord("B", "22"))
+	aTest.MustBeEqual(c.Disk().RecordExists("B"), false)
+	data, err = c.GetRecord("B")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "22")
+
+	// Test #5. Removal.
+	aTest.MustBeEqual(c.Disk().RecordExists("C"), true)
+	c.RemoveRecord("C")
+	aTest.MustBeEqual(c.RecordExists("C"), false)
+	aTest.MustBeNoError(c.AddRecord("D", "4"))
+	aTest.MustBeEqual(c.Disk().RecordExists("A"), true)
+	aTest.MustBeNoError(c.RemoveExistingRecord("A"))
+	aTest.MustBeNoError(c.RemoveExistingRecord("D"))
+	err = c.RemoveExistingRecord("A")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "record is not found, uid=A")
+
+	// Test #6. Outdated records are not demoted.
+	clock.Add(time.Second * 60)
+	aTest.MustBeNoError(c.AddRecord("E", "5"))
+	_, err = c.GetRecord("B")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(c.Disk().RecordExists("B"), false)
+
+	// Test #7. Disk tier survives reopening.
+	aTest.MustBeNoError(c.AddRecord("F", "6"))
+	aTest.MustBeNoError(c.AddRecord("G", "7"))
+	aTest.MustBeNoError(c.Close())
+	c, err = Open(memory, s)
+	aTest.MustBeNoError(err)
+	data, err = c.GetRecord("E")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "5")
+
+	// Test #8. Clear.
+	aTest.MustBeNoError(c.Clear())
+	aTest.MustBeEqual(c.RecordExists("E"), false)
+	aTest.MustBeEqual(c.Disk().GetSize(), 0)
+	aTest.MustBeNoError(c.Close())
+}
+
+func Test_Cache_onError(t *testing.T) {
+	aTest := tester.New(t)
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	var s = _test_settings(t, clock)
+	var c *Cache[string, string]
+	var errs []error
+	var err error
+
+	s.OnError = func(err error) { errs = append(errs, err) }
+	c, err = Open(vl.Config[string, string]{SizeLimit: 1, RecordTtl: 60}, s)
+	aTest.MustBeNoError(err)
+	defer func() { _ = c.Close() }()
+
+	// Test.
+	aTest.MustBeNoError(c.AddRecord("A", string(make([]byte, 200))))
+	aTest.MustBeNoError(c.AddRecord("B", "2"))
+	aTest.MustBeEqual(len(errs), 1)
+	aTest.MustBeEqual(errs[0].Error(), "demotion is failed, uid=A: record is too big")
+	aTest.MustBeEqual(errors.Unwrap(errs[0]).Error(), ErrRecordIsTooBig)
+}
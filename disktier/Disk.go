@@ -0,0 +1,375 @@
+package disktier
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/internal/uidcodec"
+)
+
+// Disk is a disk tier of records. Records are appended to segment files, and
+// an in-memory index maps UIDs to locations of the records. When the total
+// size of segment files exceeds the volume limit, the oldest segment is
+// removed with all its records. The index is rebuilt from segment files when
+// the tier is opened.
+type Disk[U vl.UidType, D vl.DataType] struct {
+	settings Settings
+	segments []*segment
+	index    map[U]location
+	volume   int
+	lock     *sync.Mutex
+}
+
+// location is a location of a record in a segment.
+type location struct {
+	segment *segment
+	offset  int64
+	time    int64
+}
+
+// OpenDisk opens a disk tier stored in the directory of the settings.
+func OpenDisk[U vl.UidType, D vl.DataType](settings Settings) (d *Disk[U, D], err error) {
+	err = settings.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(settings.Dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	d = &Disk[U, D]{
+		settings: settings,
+		index:    make(map[U]location),
+		lock:     new(sync.Mutex),
+	}
+
+	err = d.recover()
+	if err != nil {
+		_ = d.closeSegments()
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// recover opens existing segment files in order and fills the index.
+func (d *Disk[U, D]) recover() (err error) {
+	var dirEntries []os.DirEntry
+	dirEntries, err = os.ReadDir(d.settings.Dir)
+	if err != nil {
+		return err
+	}
+
+	var ids []uint64
+	var id uint64
+	for _, de := range dirEntries {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), SegmentFileExt) {
+			continue
+		}
+
+		id, err = parseSegmentFileName(de.Name())
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	var s *segment
+	for _, id = range ids {
+		s, err = openSegment(d.settings.Dir, id)
+		if err != nil {
+			return err
+		}
+		d.segments = append(d.segments, s)
+
+		var decodeErr error
+		err = s.scan(func(e entry, offset int64) {
+			uid, uidErr := uidcodec.DecodeText[U](e.uid)
+			if uidErr != nil {
+				decodeErr = uidErr
+				return
+			}
+
+			if e.flags == entryFlagDelete {
+				delete(d.index, uid)
+			} else {
+				d.index[uid] = location{segment: s, offset: offset, time: e.time}
+			}
+		})
+		if err != nil {
+			return err
+		}
+		if decodeErr != nil {
+			return decodeErr
+		}
+		d.volume += int(s.size)
+	}
+
+	if len(d.segments) == 0 {
+		_, err = d.addSegment()
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.applyVolumeLimit()
+}
+
+func (d *Disk[U, D]) now() int64 {
+	if d.settings.Clock == nil {
+		return time.Now().Unix()
+	}
+
+	return d.settings.Clock.Now().Unix()
+}
+
+func (d *Disk[U, D]) isAlive(loc location) bool {
+	return d.now() < loc.time+int64(d.settings.RecordTtl)
+}
+
+func (d *Disk[U, D]) addSegment() (s *segment, err error) {
+	var id uint64 = 1
+	if len(d.segments) > 0 {
+		id = d.segments[len(d.segments)-1].id + 1
+	}
+
+	s, err = openSegment(d.settings.Dir, id)
+	if err != nil {
+		return nil, err
+	}
+	d.segments = append(d.segments, s)
+
+	return s, nil
+}
+
+// write appends the entry to the last segment. A new segment is started when
+// the entry does not fit the last segment.
+func (d *Disk[U, D]) write(e entry) (loc location, err error) {
+	s := d.segments[len(d.segments)-1]
+	if (s.size > 0) && (s.size+int64(e.size()) > int64(d.settings.SegmentSize)) {
+		s, err = d.addSegment()
+		if err != nil {
+			return loc, err
+		}
+	}
+
+	var offset int64
+	offset, err = s.append(e)
+	if err != nil {
+		return loc, err
+	}
+	d.volume += e.size()
+
+	return location{segment: s, offset: offset, time: e.time}, nil
+}
+
+// applyVolumeLimit removes the oldest segments with all their records until
+// the volume limit holds. The last segment is never removed.
+func (d *Disk[U, D]) applyVolumeLimit() (err error) {
+	for (d.volume > d.settings.VolumeLimit) && (len(d.segments) > 1) {
+		oldest := d.segments[0]
+		for uid, loc := range d.index {
+			if loc.segment == oldest {
+				delete(d.index, uid)
+			}
+		}
+
+		d.segments = d.segments[1:]
+		d.volume -= int(oldest.size)
+
+		err = oldest.remove()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Put writes a record to the disk. A previous record with the same UID is
+// replaced.
+func (d *Disk[U, D]) Put(uid U, data D) (err error) {
+	e := entry{
+		flags: entryFlagPut,
+		time:  d.now(),
+		uid:   uidcodec.EncodeText(uid),
+		data:  []byte(data),
+	}
+
+	if e.size() > d.settings.VolumeLimit {
+		return errors.New(ErrRecordIsTooBig)
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	var loc location
+	loc, err = d.write(e)
+	if err != nil {
+		return err
+	}
+	d.index[uid] = loc
+
+	return d.applyVolumeLimit()
+}
+
+// Get reads a record from the disk. An outdated record is removed and is not
+// returned.
+func (d *Disk[U, D]) Get(uid U) (data D, err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.get(uid)
+}
+
+func (d *Disk[U, D]) get(uid U) (data D, err error) {
+	loc, recExists := d.index[uid]
+	if !recExists {
+		return data, fmt.Errorf(ErrRecordIsNotFound, uid)
+	}
+
+	if !d.isAlive(loc) {
+		_, err = d.delete(uid)
+		if err != nil {
+			return data, err
+		}
+
+		return data, fmt.Errorf(ErrRecordIsOutdated, uid)
+	}
+
+	var e entry
+	e, err = loc.segment.read(loc.offset)
+	if err != nil {
+		return data, err
+	}
+
+	return D(e.data), nil
+}
+
+// Take reads a record from the disk and removes it.
+func (d *Disk[U, D]) Take(uid U) (data D, err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	data, err = d.get(uid)
+	if err != nil {
+		return data, err
+	}
+
+	_, err = d.delete(uid)
+	if err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+// RecordExists checks whether an alive record exists on the disk.
+func (d *Disk[U, D]) RecordExists(uid U) (recordExists bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	var loc location
+	loc, recordExists = d.index[uid]
+
+	return recordExists && d.isAlive(loc)
+}
+
+// Delete removes a record from the disk. Removal is written to the disk, so
+// that the record is not restored when the tier is opened again.
+func (d *Disk[U, D]) Delete(uid U) (recExists bool, err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.delete(uid)
+}
+
+func (d *Disk[U, D]) delete(uid U) (recExists bool, err error) {
+	_, recExists = d.index[uid]
+	if !recExists {
+		return false, nil
+	}
+	delete(d.index, uid)
+
+	_, err = d.write(entry{
+		flags: entryFlagDelete,
+		time:  d.now(),
+		uid:   uidcodec.EncodeText(uid),
+	})
+	if err != nil {
+		return true, err
+	}
+
+	return true, d.applyVolumeLimit()
+}
+
+// Clear removes all records and all segment files.
+func (d *Disk[U, D]) Clear() (err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	lastId := d.segments[len(d.segments)-1].id
+	for _, s := range d.segments {
+		err = s.remove()
+		if err != nil {
+			return err
+		}
+	}
+
+	d.segments = nil
+	d.index = make(map[U]location)
+	d.volume = 0
+
+	// Identifiers of segments are never reused.
+	var s *segment
+	s, err = openSegment(d.settings.Dir, lastId+1)
+	if err != nil {
+		return err
+	}
+	d.segments = append(d.segments, s)
+
+	return nil
+}
+
+// GetVolume returns the total size of segment files and its limit.
+func (d *Disk[U, D]) GetVolume() (usedVolume int, volumeLimit int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.volume, d.settings.VolumeLimit
+}
+
+// GetSize returns the number of records on the disk including outdated
+// records which have not been removed yet.
+func (d *Disk[U, D]) GetSize() (size int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return len(d.index)
+}
+
+// Close closes segment files. The disk tier must not be used after closing.
+func (d *Disk[U, D]) Close() (err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.closeSegments()
+}
+
+func (d *Disk[U, D]) closeSegments() (err error) {
+	var errs []error
+	for _, s := range d.segments {
+		errs = append(errs, s.close())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,158 @@
+package disktier
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_OpenDisk(t *testing.T) {
+	aTest := tester.New(t)
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	var s = _test_settings(t, clock)
+	var d *Disk[string, string]
+	var data string
+	var err error
+
+	// Test #1. Bad settings.
+	_, err = OpenDisk[string, string](Settings{})
+	aTest.MustBeAnError(err)
+
+	// Test #2. Empty directory.
+	d, err = OpenDisk[string, string](s)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(d.GetSize(), 0)
+	aTest.MustBeNoError(d.Put("A", "1"))
+	aTest.MustBeNoError(d.Put("B", "2"))
+	aTest.MustBeNoError(d.Put("A", "3"))
+	_, err = d.Delete("B")
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(d.Close())
+
+	// Test #3. Records are recovered.
+	d, err = OpenDisk[string, string](s)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(d.GetSize(), 1)
+	data, err = d.Get("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "3")
+	aTest.MustBeEqual(d.RecordExists("B"), false)
+	aTest.MustBeNoError(d.Close())
+
+	// Test #4. Bad file name.
+	err = os.WriteFile(s.Dir+"/x"+SegmentFileExt, nil, 0o644)
+	aTest.MustBeNoError(err)
+	_, err = OpenDisk[string, string](s)
+	aTest.MustBeAnError(err)
+}
+
+func Test_Disk(t *testing.T) {
+	aTest := tester.New(t)
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	var d *Disk[int, []byte]
+	var data []byte
+	var recExists bool
+	var err error
+
+	d, err = OpenDisk[int, []byte](_test_settings(t, clock))
+	aTest.MustBeNoError(err)
+	defer func() { _ = d.Close() }()
+
+	// Test #1. Put and Get.
+	err = d.Put(1, []byte("0123456789"))
+	aTest.MustBeNoError(err)
+	data, err = d.Get(1)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("0123456789"))
+	_, err = d.Get(2)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "record is not found, uid=2")
+	aTest.MustBeEqual(d.RecordExists(1), true)
+	volume, volumeLimit := d.GetVolume()
+	aTest.MustBeEqual(volume, 32)
+	aTest.MustBeEqual(volumeLimit, 200)
+
+	// Test #2. Take.
+	data, err = d.Take(1)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("0123456789"))
+	aTest.MustBeEqual(d.RecordExists(1), false)
+	_, err = d.Take(1)
+	aTest.MustBeAnError(err)
+
+	// Test #3. Delete.
+	err = d.Put(2, []byte("2"))
+	aTest.MustBeNoError(err)
+	recExists, err = d.Delete(2)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(recExists, true)
+	recExists, err = d.Delete(2)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(recExists, false)
+
+	// Test #4. Too big record.
+	err = d.Put(3, make([]byte, 200))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrRecordIsTooBig)
+
+	// Test #5. Outdated record.
+	err = d.Put(4, []byte("4"))
+	aTest.MustBeNoError(err)
+	clock.Add(time.Second * 60)
+	aTest.MustBeEqual(d.RecordExists(4), false)
+	_, err = d.Get(4)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "record is outdated, uid=4")
+	aTest.MustBeEqual(d.GetSize(), 0)
+
+	// Test #6. Clear.
+	err = d.Put(5, []byte("5"))
+	aTest.MustBeNoError(err)
+	err = d.Clear()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(d.GetSize(), 0)
+	volume, _ = d.GetVolume()
+	aTest.MustBeEqual(volume, 0)
+	entries, err := os.ReadDir(d.settings.Dir)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(len(entries), 1)
+	aTest.MustBeEqual(entries[0].Name(), segmentFileName(d.segments[0].id))
+}
+
+func Test_applyVolumeLimit(t *testing.T) {
+	aTest := tester.New(t)
+	var clock = &_test_clock{t: time.Unix(1000, 0)}
+	var d *Disk[int, string]
+	var err error
+
+	d, err = OpenDisk[int, string](_test_settings(t, clock))
+	aTest.MustBeNoError(err)
+	defer func() { _ = d.Close() }()
+
+	// Test #1. Segments are rotated.
+	for uid := 1; uid <= 6; uid++ {
+		err = d.Put(uid, "0123456789")
+		aTest.MustBeNoError(err)
+	}
+	aTest.MustBeEqual(len(d.segments), 2)
+	volume, _ := d.GetVolume()
+	aTest.MustBeEqual(volume, 192)
+
+	// Test #2. The oldest segment is removed.
+	err = d.Put(7, "0123456789")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(len(d.segments), 2)
+	volume, _ = d.GetVolume()
+	aTest.MustBeEqual(volume, 128)
+	aTest.MustBeEqual(d.GetSize(), 4)
+	for uid := 1; uid <= 3; uid++ {
+		aTest.MustBeEqual(d.RecordExists(uid), false)
+	}
+	for uid := 4; uid <= 7; uid++ {
+		aTest.MustBeEqual(d.RecordExists(uid), true)
+	}
+	_, err = os.Stat(d.settings.Dir + "/" + segmentFileName(1))
+	aTest.MustBeEqual(os.IsNotExist(err), true)
+}
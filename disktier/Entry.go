@@ -0,0 +1,83 @@
+package disktier
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+const entryHeaderLen = 21
+
+const (
+	entryFlagPut    byte = 0
+	entryFlagDelete byte = 1
+)
+
+// entry is an entry of a segment file. The layout of an entry is following:
+// a CRC-32 checksum of the rest of the entry (4 bytes), flags (1 byte), time
+// of writing in Unix seconds (8 bytes), length of the UID (4 bytes), length
+// of the data (4 bytes), the UID and the data. Integers are big-endian.
+type entry struct {
+	flags byte
+	time  int64
+	uid   []byte
+	data  []byte
+}
+
+func (e entry) size() int {
+	return entryHeaderLen + len(e.uid) + len(e.data)
+}
+
+func (e entry) encode() (buf []byte) {
+	buf = make([]byte, e.size())
+	buf[4] = e.flags
+	binary.BigEndian.PutUint64(buf[5:13], uint64(e.time))
+	binary.BigEndian.PutUint32(buf[13:17], uint32(len(e.uid)))
+	binary.BigEndian.PutUint32(buf[17:21], uint32(len(e.data)))
+	copy(buf[entryHeaderLen:], e.uid)
+	copy(buf[entryHeaderLen+len(e.uid):], e.data)
+	binary.BigEndian.PutUint32(buf[0:4], crc32.ChecksumIEEE(buf[4:]))
+
+	return buf
+}
+
+// readEntry reads an entry located at the offset. The limit is the size of
+// the readable data, an entry crossing the limit is treated as corrupted.
+func readEntry(r io.ReaderAt, offset int64, limit int64) (e entry, size int64, err error) {
+	if offset+entryHeaderLen > limit {
+		return e, 0, errors.New(ErrEntryIsCorrupted)
+	}
+
+	header := make([]byte, entryHeaderLen)
+	_, err = r.ReadAt(header, offset)
+	if err != nil {
+		return e, 0, err
+	}
+
+	uidLen := int64(binary.BigEndian.Uint32(header[13:17]))
+	dataLen := int64(binary.BigEndian.Uint32(header[17:21]))
+	size = entryHeaderLen + uidLen + dataLen
+	if offset+size > limit {
+		return e, 0, errors.New(ErrEntryIsCorrupted)
+	}
+
+	buf := make([]byte, size)
+	_, err = r.ReadAt(buf, offset)
+	if err != nil {
+		return e, 0, err
+	}
+
+	if binary.BigEndian.Uint32(buf[0:4]) != crc32.ChecksumIEEE(buf[4:]) {
+		return e, 0, errors.New(ErrEntryIsCorrupted)
+	}
+
+	e = entry{
+		flags: buf[4],
+		time:  int64(binary.BigEndian.Uint64(buf[5:13])),
+		uid:   buf[entryHeaderLen : entryHeaderLen+uidLen],
+		data:  buf[entryHeaderLen+uidLen:],
+	}
+
+	return e, size, nil
+}
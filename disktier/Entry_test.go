@@ -0,0 +1,39 @@
+package disktier
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_entry(t *testing.T) {
+	aTest := tester.New(t)
+	var e = entry{flags: entryFlagDelete, time: 123, uid: []byte("uid"), data: []byte("data")}
+	var err error
+
+	// Test #1. Encoding and decoding.
+	buf := e.encode()
+	aTest.MustBeEqual(len(buf), e.size())
+	aTest.MustBeEqual(e.size(), entryHeaderLen+7)
+	r := bytes.NewReader(append([]byte("xx"), buf...))
+	var e2 entry
+	var size int64
+	e2, size, err = readEntry(r, 2, int64(r.Len()))
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(size, int64(e.size()))
+	aTest.MustBeEqual(e2, e)
+
+	// Test #2. Truncated entry.
+	_, _, err = readEntry(r, 2, int64(r.Len()-1))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrEntryIsCorrupted)
+	_, _, err = readEntry(r, 2, 10)
+	aTest.MustBeAnError(err)
+
+	// Test #3. Damaged entry.
+	buf[len(buf)-1] ^= 0xFF
+	_, _, err = readEntry(bytes.NewReader(buf), 0, int64(len(buf)))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrEntryIsCorrupted)
+}
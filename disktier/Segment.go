@@ -0,0 +1,115 @@
+package disktier
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SegmentFileExt is an extension of segment files.
+const SegmentFileExt = ".seg"
+
+// segment is an append-only file of entries.
+type segment struct {
+	id   uint64
+	file *os.File
+	size int64
+}
+
+func segmentFileName(id uint64) string {
+	return fmt.Sprintf("%020d%s", id, SegmentFileExt)
+}
+
+func parseSegmentFileName(name string) (id uint64, err error) {
+	id, err = strconv.ParseUint(strings.TrimSuffix(name, SegmentFileExt), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf(ErrSegmentFileNameIsBad, name)
+	}
+
+	return id, nil
+}
+
+// openSegment opens a segment file, creating it if it does not exist.
+func openSegment(dir string, id uint64) (s *segment, err error) {
+	var file *os.File
+	file, err = os.OpenFile(filepath.Join(dir, segmentFileName(id)), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	var fi os.FileInfo
+	fi, err = file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	s = &segment{
+		id:   id,
+		file: file,
+		size: fi.Size(),
+	}
+
+	return s, nil
+}
+
+// append writes the entry to the end of the segment and returns the entry's
+// offset.
+func (s *segment) append(e entry) (offset int64, err error) {
+	offset = s.size
+	_, err = s.file.WriteAt(e.encode(), offset)
+	if err != nil {
+		return 0, err
+	}
+
+	s.size += int64(e.size())
+
+	return offset, nil
+}
+
+func (s *segment) read(offset int64) (e entry, err error) {
+	e, _, err = readEntry(s.file, offset, s.size)
+	return e, err
+}
+
+// scan reads all the entries of the segment in order. A corrupted tail, e.g.
+// an entry which has been written partially, is cut off.
+func (s *segment) scan(fn func(e entry, offset int64)) (err error) {
+	var offset int64
+	var e entry
+	var size int64
+	for offset < s.size {
+		e, size, err = readEntry(s.file, offset, s.size)
+		if err != nil {
+			break
+		}
+
+		fn(e, offset)
+		offset += size
+	}
+
+	if offset < s.size {
+		err = s.file.Truncate(offset)
+		if err != nil {
+			return err
+		}
+		s.size = offset
+	}
+
+	return nil
+}
+
+func (s *segment) close() (err error) {
+	return s.file.Close()
+}
+
+func (s *segment) remove() (err error) {
+	err = s.file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(s.file.Name())
+}
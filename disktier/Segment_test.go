@@ -0,0 +1,71 @@
+package disktier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_parseSegmentFileName(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test #1. OK.
+	id, err := parseSegmentFileName(segmentFileName(42))
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(id, uint64(42))
+	aTest.MustBeEqual(segmentFileName(42), "00000000000000000042.seg")
+
+	// Test #2. Bad name.
+	_, err = parseSegmentFileName("x.seg")
+	aTest.MustBeAnError(err)
+}
+
+func Test_segment(t *testing.T) {
+	aTest := tester.New(t)
+	var dir = t.TempDir()
+	var s *segment
+	var err error
+
+	s, err = openSegment(dir, 1)
+	aTest.MustBeNoError(err)
+
+	// Test #1. Appending and reading.
+	e1 := entry{time: 1, uid: []byte("A"), data: []byte("1")}
+	e2 := entry{time: 2, uid: []byte("B"), data: []byte("22")}
+	offset, err := s.append(e1)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(offset, int64(0))
+	offset, err = s.append(e2)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(offset, int64(e1.size()))
+	e, err := s.read(offset)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(e, e2)
+	aTest.MustBeNoError(s.close())
+
+	// Test #2. Partially written tail is cut off.
+	path := filepath.Join(dir, segmentFileName(1))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	aTest.MustBeNoError(err)
+	_, err = f.Write(e1.encode()[:5])
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(f.Close())
+
+	s, err = openSegment(dir, 1)
+	aTest.MustBeNoError(err)
+	var entries []entry
+	err = s.scan(func(e entry, offset int64) { entries = append(entries, e) })
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(entries, []entry{e1, e2})
+	aTest.MustBeEqual(s.size, int64(e1.size()+e2.size()))
+	fi, err := os.Stat(path)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(fi.Size(), s.size)
+
+	// Test #3. Removal.
+	aTest.MustBeNoError(s.remove())
+	_, err = os.Stat(path)
+	aTest.MustBeEqual(os.IsNotExist(err), true)
+}
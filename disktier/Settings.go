@@ -0,0 +1,55 @@
+package disktier
+
+import (
+	"errors"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+// Settings contains settings of the disk tier.
+type Settings struct {
+	// Dir is a directory of segment files. It is created if it does not
+	// exist.
+	Dir string
+
+	// VolumeLimit is a maximum total size of segment files in bytes. When it
+	// is exceeded, the oldest segment is removed with all its records.
+	VolumeLimit int
+
+	// SegmentSize is a size of a segment file in bytes after which a new
+	// segment is started.
+	SegmentSize int
+
+	// RecordTtl is the records' TTL in seconds counted from the moment of
+	// writing a record to the disk. The two-tier cache uses the TTL of its
+	// memory tier when this TTL is zero.
+	RecordTtl uint
+
+	// Clock is an optional source of current time.
+	Clock vl.Clock
+
+	// OnError is an optional callback which receives errors of the disk tier
+	// which can not be returned to a caller, i.e. errors of demotion of
+	// records and errors of the RemoveRecord method of the two-tier cache.
+	OnError func(err error)
+}
+
+func (s Settings) validate() (err error) {
+	if len(s.Dir) == 0 {
+		return errors.New(ErrDirIsNotSet)
+	}
+
+	if s.VolumeLimit <= 0 {
+		return errors.New(ErrVolumeLimitIsNotPositive)
+	}
+
+	if (s.SegmentSize <= 0) || (s.SegmentSize > s.VolumeLimit) {
+		return errors.New(ErrSegmentSizeIsBad)
+	}
+
+	if s.RecordTtl == 0 {
+		return errors.New(ErrRecordTtlIsZero)
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package disktier
+
+import (
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_validate(t *testing.T) {
+	aTest := tester.New(t)
+	var s Settings
+
+	// Test #1. Bad settings.
+	aTest.MustBeEqual(s.validate().Error(), ErrDirIsNotSet)
+	s.Dir = "x"
+	aTest.MustBeEqual(s.validate().Error(), ErrVolumeLimitIsNotPositive)
+	s.VolumeLimit = 10
+	aTest.MustBeEqual(s.validate().Error(), ErrSegmentSizeIsBad)
+	s.SegmentSize = 11
+	aTest.MustBeEqual(s.validate().Error(), ErrSegmentSizeIsBad)
+	s.SegmentSize = 10
+	aTest.MustBeEqual(s.validate().Error(), ErrRecordTtlIsZero)
+
+	// Test #2. OK.
+	s.RecordTtl = 1
+	aTest.MustBeNoError(s.validate())
+}
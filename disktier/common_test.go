@@ -0,0 +1,37 @@
+package disktier
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// _test_clock is a clock which is moved manually.
+type _test_clock struct {
+	lock sync.Mutex
+	t    time.Time
+}
+
+func (tc *_test_clock) Now() time.Time {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+
+	return tc.t
+}
+
+func (tc *_test_clock) Add(d time.Duration) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+
+	tc.t = tc.t.Add(d)
+}
+
+func _test_settings(t *testing.T, clock *_test_clock) (s Settings) {
+	return Settings{
+		Dir:         t.TempDir(),
+		VolumeLimit: 200,
+		SegmentSize: 100,
+		RecordTtl:   60,
+		Clock:       clock,
+	}
+}
@@ -0,0 +1,15 @@
+package disktier
+
+const (
+	ErrDirIsNotSet              = "directory is not set"
+	ErrVolumeLimitIsNotPositive = "volume limit is not positive"
+	ErrSegmentSizeIsBad         = "segment size must satisfy 0 < segment size <= volume limit"
+	ErrRecordTtlIsZero          = "record TTL is zero"
+	ErrRecordIsTooBig           = "record is too big"
+	ErrRecordIsNotFound         = "record is not found, uid=%v"
+	ErrRecordIsOutdated         = "record is outdated, uid=%v"
+	ErrEntryIsCorrupted         = "entry is corrupted"
+	ErrDemotionIsFailed         = "demotion is failed, uid=%v: %w"
+	ErrRemovalIsFailed          = "removal is failed, uid=%v: %w"
+	ErrSegmentFileNameIsBad     = "segment file name is bad: %v"
+)
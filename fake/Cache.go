@@ -0,0 +1,154 @@
+// Package fake contains a fake cache for unit tests of the cache's consumers.
+package fake
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+)
+
+// Cache is a fake cache. It keeps records in a map without any limits and
+// without TTL, counts calls of its methods and returns errors injected into
+// its methods.
+type Cache[U comparable, D any] struct {
+	records map[U]D
+	calls   map[Method]int
+	errors  map[Method]error
+	lock    *sync.Mutex
+}
+
+// New creates a new fake cache.
+func New[U comparable, D any]() (c *Cache[U, D]) {
+	return &Cache[U, D]{
+		records: make(map[U]D),
+		calls:   make(map[Method]int),
+		errors:  make(map[Method]error),
+		lock:    new(sync.Mutex),
+	}
+}
+
+// SetError injects an error which is returned by the method instead of
+// doing its work. Nil removes the injected error. Errors of methods which do
+// not return an error are ignored.
+func (c *Cache[U, D]) SetError(method Method, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err == nil {
+		delete(c.errors, method)
+		return
+	}
+
+	c.errors[method] = err
+}
+
+// Calls returns the number of calls of the method.
+func (c *Cache[U, D]) Calls(method Method) (n int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.calls[method]
+}
+
+// Records returns a copy of the stored records.
+func (c *Cache[U, D]) Records() (records map[U]D) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return maps.Clone(c.records)
+}
+
+// call registers a call of the method and returns its injected error. The
+// cache must be locked.
+func (c *Cache[U, D]) call(method Method) (err error) {
+	c.calls[method]++
+	return c.errors[method]
+}
+
+// RecordExists checks whether the specified record exists or not.
+func (c *Cache[U, D]) RecordExists(uid U) (recordExists bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_ = c.call(MethodRecordExists)
+	_, recordExists = c.records[uid]
+
+	return recordExists
+}
+
+// AddRecord adds a new record or updates an existing one.
+func (c *Cache[U, D]) AddRecord(uid U, data D) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	err = c.call(MethodAddRecord)
+	if err != nil {
+		return err
+	}
+
+	c.records[uid] = data
+
+	return nil
+}
+
+// GetRecord reads a record.
+func (c *Cache[U, D]) GetRecord(uid U) (data D, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	err = c.call(MethodGetRecord)
+	if err != nil {
+		return data, err
+	}
+
+	var recExists bool
+	data, recExists = c.records[uid]
+	if !recExists {
+		return data, fmt.Errorf(ErrRecordIsNotFound, uid)
+	}
+
+	return data, nil
+}
+
+// RemoveRecord removes a record if it exists.
+func (c *Cache[U, D]) RemoveRecord(uid U) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_ = c.call(MethodRemoveRecord)
+	delete(c.records, uid)
+}
+
+// RemoveExistingRecord removes an existing record.
+func (c *Cache[U, D]) RemoveExistingRecord(uid U) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	err = c.call(MethodRemoveExistingRecord)
+	if err != nil {
+		return err
+	}
+
+	_, recExists := c.records[uid]
+	if !recExists {
+		return fmt.Errorf(ErrRecordIsNotFound, uid)
+	}
+	delete(c.records, uid)
+
+	return nil
+}
+
+// Clear removes all records.
+func (c *Cache[U, D]) Clear() (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	err = c.call(MethodClear)
+	if err != nil {
+		return err
+	}
+
+	clear(c.records)
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package fake
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_Cache(t *testing.T) {
+	aTest := tester.New(t)
+	var c = New[string, int]()
+	var data int
+	var err error
+
+	// Test #1. Records.
+	aTest.MustBeNoError(c.AddRecord("A", 1))
+	aTest.MustBeNoError(c.AddRecord("B", 2))
+	aTest.MustBeEqual(c.RecordExists("A"), true)
+	data, err = c.GetRecord("B")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, 2)
+	_, err = c.GetRecord("Q")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "record is not found, uid=Q")
+	aTest.MustBeEqual(c.Records(), map[string]int{"A": 1, "B": 2})
+
+	// Test #2. Removal.
+	c.RemoveRecord("A")
+	aTest.MustBeEqual(c.RecordExists("A"), false)
+	err = c.RemoveExistingRecord("A")
+	aTest.MustBeAnError(err)
+	aTest.MustBeNoError(c.RemoveExistingRecord("B"))
+	aTest.MustBeNoError(c.AddRecord("C", 3))
+	aTest.MustBeNoError(c.Clear())
+	aTest.MustBeEqual(c.Records(), map[string]int{})
+
+	// Test #3. Calls.
+	aTest.MustBeEqual(c.Calls(MethodAddRecord), 3)
+	aTest.MustBeEqual(c.Calls(MethodGetRecord), 2)
+	aTest.MustBeEqual(c.Calls(MethodRecordExists), 2)
+	aTest.MustBeEqual(c.Calls(MethodRemoveRecord), 1)
+	aTest.MustBeEqual(c.Calls(MethodRemoveExistingRecord), 2)
+	aTest.MustBeEqual(c.Calls(MethodClear), 1)
+}
+
+func Test_SetError(t *testing.T) {
+	aTest := tester.New(t)
+	var c = New[string, int]()
+	var failure = errors.New("failure")
+	var err error
+
+	// Test #1. Injected errors.
+	for _, m := range []Method{MethodAddRecord, MethodGetRecord, MethodRemoveExistingRecord, MethodClear} {
+		c.SetError(m, failure)
+	}
+	err = c.AddRecord("A", 1)
+	aTest.MustBeEqual(err, failure)
+	_, err = c.GetRecord("A")
+	aTest.MustBeEqual(err, failure)
+	err = c.RemoveExistingRecord("A")
+	aTest.MustBeEqual(err, failure)
+	err = c.Clear()
+	aTest.MustBeEqual(err, failure)
+	aTest.MustBeEqual(len(c.Records()), 0)
+
+	// Test #2. Errors are removed.
+	c.SetError(MethodAddRecord, nil)
+	aTest.MustBeNoError(c.AddRecord("A", 1))
+	aTest.MustBeEqual(c.Calls(MethodAddRecord), 2)
+}
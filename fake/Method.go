@@ -0,0 +1,13 @@
+package fake
+
+// Method is a name of a method of the cache.
+type Method string
+
+const (
+	MethodRecordExists         Method = "RecordExists"
+	MethodAddRecord            Method = "AddRecord"
+	MethodGetRecord            Method = "GetRecord"
+	MethodRemoveRecord         Method = "RemoveRecord"
+	MethodRemoveExistingRecord Method = "RemoveExistingRecord"
+	MethodClear                Method = "Clear"
+)
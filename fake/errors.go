@@ -0,0 +1,5 @@
+package fake
+
+const (
+	ErrRecordIsNotFound = "record is not found, uid=%v"
+)
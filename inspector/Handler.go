@@ -0,0 +1,231 @@
+package inspector
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	// publishedHandlers maps names of published variables to handlers which
+	// have registered them last. Variables are resolved to handlers when
+	// they are read.
+	publishedHandlers     = make(map[string]*Handler)
+	publishedHandlersLock = new(sync.Mutex)
+)
+
+const (
+	// DefaultUidsCount is a default number of UIDs shown at the top and at
+	// the bottom of each cache.
+	DefaultUidsCount = 10
+
+	// ExpvarPrefix is a prefix of names of variables published in the
+	// 'expvar' package.
+	ExpvarPrefix = "cache."
+)
+
+// Handler is an HTTP handler which renders states of registered caches.
+//
+// The state is rendered as JSON when the 'format' query parameter is 'json'
+// or when the client accepts 'application/json', otherwise it is rendered as
+// a simple HTML page. The 'name' query parameter limits the output to a single
+// cache.
+//
+// Each registered cache is also published in the 'expvar' package under the
+// name prefixed with 'cache.'. As variables can not be removed from the
+// 'expvar' package, an unregistered cache is published as null, and the name
+// may then be registered by another handler. A name which is registered by
+// another handler or is published in the 'expvar' package by other code can
+// not be registered.
+type Handler struct {
+	uidsCount int
+	sources   map[string]Source
+	lock      *sync.RWMutex
+}
+
+// NewHandler creates a new handler which shows at most uidsCount UIDs at the
+// top and at the bottom of each cache. A non-positive count is replaced with
+// the default value.
+func NewHandler(uidsCount int) (h *Handler) {
+	if uidsCount <= 0 {
+		uidsCount = DefaultUidsCount
+	}
+
+	return &Handler{
+		uidsCount: uidsCount,
+		sources:   make(map[string]Source),
+		lock:      new(sync.RWMutex),
+	}
+}
+
+// Register registers a source of a cache having the specified name.
+func (h *Handler) Register(name string, src Source) (err error) {
+	if len(name) == 0 {
+		return errors.New(ErrNameIsEmpty)
+	}
+	if src == nil {
+		return errors.New(ErrSourceIsNil)
+	}
+
+	publishedHandlersLock.Lock()
+	defer publishedHandlersLock.Unlock()
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	_, isUsed := h.sources[name]
+	if isUsed {
+		return fmt.Errorf(ErrNameIsUsed, name)
+	}
+
+	owner, isPublished := publishedHandlers[name]
+	if !isPublished && (expvar.Get(ExpvarPrefix+name) != nil) {
+		return fmt.Errorf(ErrNameIsPublished, name)
+	}
+	if isPublished && (owner != h) && owner.isRegistered(name) {
+		return fmt.Errorf(ErrNameIsUsed, name)
+	}
+
+	h.sources[name] = src
+	publishedHandlers[name] = h
+
+	if !isPublished {
+		expvar.Publish(ExpvarPrefix+name, expvar.Func(func() any {
+			publishedHandlersLock.Lock()
+			owner := publishedHandlers[name]
+			publishedHandlersLock.Unlock()
+
+			s, ok := owner.inspect(name)
+			if !ok {
+				return nil
+			}
+			return s
+		}))
+	}
+
+	return nil
+}
+
+func (h *Handler) isRegistered(name string) bool {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	_, ok := h.sources[name]
+	return ok
+}
+
+// Unregister removes a source of a cache having the specified name.
+func (h *Handler) Unregister(name string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	delete(h.sources, name)
+}
+
+// States returns states of all the registered caches sorted by name.
+func (h *Handler) States() (states []State) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	names := make([]string, 0, len(h.sources))
+	for name := range h.sources {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	states = make([]State, 0, len(names))
+	for _, name := range names {
+		s := h.sources[name].Inspect(h.uidsCount)
+		s.Name = name
+		states = append(states, s)
+	}
+
+	return states
+}
+
+func (h *Handler) inspect(name string) (s State, ok bool) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	var src Source
+	src, ok = h.sources[name]
+	if !ok {
+		return s, false
+	}
+
+	s = src.Inspect(h.uidsCount)
+	s.Name = name
+
+	return s, true
+}
+
+// ServeHTTP renders states of the registered caches.
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	var states []State
+	name := req.URL.Query().Get("name")
+	if len(name) > 0 {
+		s, ok := h.inspect(name)
+		if !ok {
+			http.Error(rw, fmt.Sprintf(ErrCacheNotFound, name), http.StatusNotFound)
+			return
+		}
+		states = []State{s}
+	} else {
+		states = h.States()
+	}
+
+	if wantsJson(req) {
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(states)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pageTemplate.Execute(rw, states)
+}
+
+func wantsJson(req *http.Request) bool {
+	format := req.URL.Query().Get("format")
+	if len(format) > 0 {
+		return format == "json"
+	}
+
+	return strings.Contains(req.Header.Get("Accept"), "application/json")
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Caches</title></head>
+<body>
+{{- range .}}
+<h2>{{.Name}}</h2>
+<table>
+<tr><td>Size</td><td>{{.Size}} / {{.SizeLimit}}</td></tr>
+{{- if .HasVolume}}
+<tr><td>Volume</td><td>{{.Volume}} / {{.VolumeLimit}}</td></tr>
+{{- end}}
+<tr><td>TTL</td><td>{{.Ttl}} s</td></tr>
+<tr><td>Hits</td><td>{{.Stats.Hits}}</td></tr>
+<tr><td>Misses</td><td>{{.Stats.Misses}}</td></tr>
+<tr><td>Expired on read</td><td>{{.Stats.ExpiredOnRead}}</td></tr>
+<tr><td>Stale hits</td><td>{{.Stats.StaleHits}}</td></tr>
+{{- range $reason, $count := .Stats.Evictions}}
+<tr><td>Evictions ({{$reason}})</td><td>{{$count}}</td></tr>
+{{- end}}
+</table>
+<h3>Top</h3>
+<ol>{{range .TopUids}}<li>{{.}}</li>{{end}}</ol>
+<h3>Bottom</h3>
+<ol>{{range .BottomUids}}<li>{{.}}</li>{{end}}</ol>
+{{- else}}
+<p>No caches are registered.</p>
+{{- end}}
+</body>
+</html>
+`))
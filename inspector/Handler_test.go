@@ -0,0 +1,131 @@
+package inspector
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func _test_prepare_handler(aTest *tester.Test, name string) (h *Handler) {
+	cache := vl.NewCache[string, string](0, 100, 60)
+	for _, uid := range []string{"C", "B", "A"} {
+		err := cache.AddRecord(uid, uid)
+		aTest.MustBeNoError(err)
+	}
+
+	h = NewHandler(2)
+	err := h.Register(name, FromVL(cache))
+	aTest.MustBeNoError(err)
+	return h
+}
+
+func Test_NewHandler(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test.
+	aTest.MustBeEqual(NewHandler(0).uidsCount, DefaultUidsCount)
+	aTest.MustBeEqual(NewHandler(5).uidsCount, 5)
+}
+
+func Test_Register(t *testing.T) {
+	aTest := tester.New(t)
+	var h = _test_prepare_handler(aTest, "register")
+	var err error
+
+	// Test #1. Bad arguments.
+	err = h.Register("", SourceFunc(func(n int) State { return State{} }))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrNameIsEmpty)
+	err = h.Register("x", nil)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrSourceIsNil)
+
+	// Test #2. Name is used.
+	err = h.Register("register", SourceFunc(func(n int) State { return State{} }))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrNameIsUsed, "register"))
+
+	// Test #3. Variable is published in 'expvar'.
+	v := expvar.Get(ExpvarPrefix + "register")
+	aTest.MustBeDifferent(v, nil)
+	aTest.MustBeEqual(strings.Contains(v.String(), `"topUids":["A","B"]`), true)
+
+	// Test #4. Name is registered by another handler.
+	h2 := NewHandler(2)
+	src2 := SourceFunc(func(n int) State { return State{TopUids: []string{"Z"}} })
+	err = h2.Register("register", src2)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrNameIsUsed, "register"))
+
+	// Test #5. Unregistered cache is published as null.
+	h.Unregister("register")
+	aTest.MustBeEqual(v.String(), "null")
+	aTest.MustBeEqual(len(h.States()), 0)
+
+	// Test #6. Unregistered name is taken by another handler.
+	err = h2.Register("register", src2)
+	aTest.MustBeNoError(err)
+	defer h2.Unregister("register")
+	aTest.MustBeEqual(strings.Contains(v.String(), `"topUids":["Z"]`), true)
+
+	// Test #7. Name is published by other code.
+	if expvar.Get(ExpvarPrefix+"foreign") == nil {
+		expvar.NewInt(ExpvarPrefix + "foreign")
+	}
+	err = h.Register("foreign", src2)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrNameIsPublished, "foreign"))
+}
+
+func Test_ServeHTTP(t *testing.T) {
+	aTest := tester.New(t)
+	var h = _test_prepare_handler(aTest, "serve")
+	defer h.Unregister("serve")
+	var rec *httptest.ResponseRecorder
+	var states []State
+	var err error
+
+	// Test #1. JSON.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?format=json", nil))
+	aTest.MustBeEqual(rec.Code, http.StatusOK)
+	aTest.MustBeEqual(rec.Header().Get("Content-Type"), "application/json")
+	err = json.Unmarshal(rec.Body.Bytes(), &states)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(len(states), 1)
+	aTest.MustBeEqual(states[0].Name, "serve")
+	aTest.MustBeEqual(states[0].Volume, 3)
+	aTest.MustBeEqual(states[0].BottomUids, []string{"C", "B"})
+
+	// Test #2. Zero volume is not omitted.
+	buf, err := json.Marshal(State{HasVolume: true})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(strings.Contains(string(buf), `"volume":0`), true)
+
+	// Test #3. JSON is accepted.
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/?name=serve", nil)
+	req.Header.Set("Accept", "application/json")
+	h.ServeHTTP(rec, req)
+	aTest.MustBeEqual(rec.Header().Get("Content-Type"), "application/json")
+
+	// Test #4. HTML.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	aTest.MustBeEqual(rec.Code, http.StatusOK)
+	aTest.MustBeEqual(rec.Header().Get("Content-Type"), "text/html; charset=utf-8")
+	aTest.MustBeEqual(strings.Contains(rec.Body.String(), "<h2>serve</h2>"), true)
+	aTest.MustBeEqual(strings.Contains(rec.Body.String(), "<td>3 / 100</td>"), true)
+
+	// Test #5. Unknown cache.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?name=junk", nil))
+	aTest.MustBeEqual(rec.Code, http.StatusNotFound)
+}
@@ -0,0 +1,84 @@
+package inspector
+
+import (
+	"fmt"
+
+	nvl "github.com/vault-thirteen/Cache/NVL"
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+// Source provides a state of a single cache. The source must not change the
+// order of records in the cache.
+type Source interface {
+	// Inspect returns the state of the cache with at most n UIDs at the top
+	// and at most n UIDs at the bottom of the cache.
+	Inspect(n int) (s State)
+}
+
+// SourceFunc is an adapter which allows to use an ordinary function as a
+// source.
+type SourceFunc func(n int) (s State)
+
+// Inspect calls the function.
+func (f SourceFunc) Inspect(n int) (s State) {
+	return f(n)
+}
+
+// FromVL creates a source of a cache with volume calculation.
+func FromVL[U vl.UidType, D vl.DataType](cache *vl.Cache[U, D]) (src Source) {
+	return SourceFunc(func(n int) (s State) {
+		s.Size, s.SizeLimit = cache.GetSize()
+		s.Volume, s.VolumeLimit = cache.GetVolume()
+		s.HasVolume = true
+		s.Ttl = cache.GetTtl()
+		s.TopUids = formatUids(cache.PeekTopUids(n))
+		s.BottomUids = formatUids(cache.PeekBottomUids(n))
+
+		stats := cache.GetStats()
+		s.Stats = Stats{
+			Hits:          stats.Hits,
+			Misses:        stats.Misses,
+			ExpiredOnRead: stats.ExpiredOnRead,
+			StaleHits:     stats.StaleHits,
+			Evictions:     make(map[string]uint64, len(stats.Evictions)),
+		}
+		for reason, count := range stats.Evictions {
+			s.Stats.Evictions[string(reason)] = count
+		}
+
+		return s
+	})
+}
+
+// FromNVL creates a source of a cache without volume calculation.
+func FromNVL[U nvl.UidType, D nvl.DataType](cache *nvl.Cache[U, D]) (src Source) {
+	return SourceFunc(func(n int) (s State) {
+		s.Size, s.SizeLimit = cache.GetSize()
+		s.Ttl = cache.GetTtl()
+		s.TopUids = formatUids(cache.PeekTopUids(n))
+		s.BottomUids = formatUids(cache.PeekBottomUids(n))
+
+		stats := cache.GetStats()
+		s.Stats = Stats{
+			Hits:          stats.Hits,
+			Misses:        stats.Misses,
+			ExpiredOnRead: stats.ExpiredOnRead,
+			StaleHits:     stats.StaleHits,
+			Evictions:     make(map[string]uint64, len(stats.Evictions)),
+		}
+		for reason, count := range stats.Evictions {
+			s.Stats.Evictions[string(reason)] = count
+		}
+
+		return s
+	})
+}
+
+func formatUids[U any](uids []U) (result []string) {
+	result = make([]string, 0, len(uids))
+	for _, uid := range uids {
+		result = append(result, fmt.Sprint(uid))
+	}
+
+	return result
+}
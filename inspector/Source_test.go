@@ -0,0 +1,57 @@
+package inspector
+
+import (
+	"testing"
+
+	nvl "github.com/vault-thirteen/Cache/NVL"
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_FromVL(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	cache := vl.NewCache[string, string](0, 100, 60)
+	for _, uid := range []string{"C", "B", "A"} {
+		err = cache.AddRecord(uid, uid+uid)
+		aTest.MustBeNoError(err)
+	}
+
+	// Test.
+	s := FromVL(cache).Inspect(2)
+	aTest.MustBeEqual(s.Size, 3)
+	aTest.MustBeEqual(s.SizeLimit, 0)
+	aTest.MustBeEqual(s.Volume, 6)
+	aTest.MustBeEqual(s.VolumeLimit, 100)
+	aTest.MustBeEqual(s.HasVolume, true)
+	aTest.MustBeEqual(s.Ttl, uint(60))
+	aTest.MustBeEqual(s.TopUids, []string{"A", "B"})
+	aTest.MustBeEqual(s.BottomUids, []string{"C", "B"})
+
+	// Inspection must not alter the order.
+	s = FromVL(cache).Inspect(3)
+	aTest.MustBeEqual(s.TopUids, []string{"A", "B", "C"})
+	aTest.MustBeEqual(s.Stats.Hits, uint64(0))
+}
+
+func Test_FromNVL(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	cache := nvl.NewCache[int, string](0, 60)
+	for _, uid := range []int{3, 2, 1} {
+		err = cache.AddRecord(uid, "x")
+		aTest.MustBeNoError(err)
+	}
+	_, err = cache.GetRecord(3)
+	aTest.MustBeNoError(err)
+
+	// Test.
+	s := FromNVL(cache).Inspect(2)
+	aTest.MustBeEqual(s.Size, 3)
+	aTest.MustBeEqual(s.HasVolume, false)
+	aTest.MustBeEqual(s.TopUids, []string{"3", "1"})
+	aTest.MustBeEqual(s.BottomUids, []string{"2", "1"})
+	aTest.MustBeEqual(s.Stats.Hits, uint64(1))
+}
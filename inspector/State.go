@@ -0,0 +1,35 @@
+package inspector
+
+// State is a state of a cache at some moment.
+type State struct {
+	Name        string `json:"name"`
+	Size        int    `json:"size"`
+	SizeLimit   int    `json:"sizeLimit"`
+	Volume      int    `json:"volume"`
+	VolumeLimit int    `json:"volumeLimit"`
+
+	// HasVolume is set when the cache measures volume of its records.
+	HasVolume bool `json:"hasVolume"`
+
+	// Ttl is the records' TTL in seconds.
+	Ttl uint `json:"ttl"`
+
+	// TopUids are UIDs of the most recently used records, the most recent
+	// goes first.
+	TopUids []string `json:"topUids"`
+
+	// BottomUids are UIDs of the records to be evicted next, the first to be
+	// evicted goes first.
+	BottomUids []string `json:"bottomUids"`
+
+	Stats Stats `json:"stats"`
+}
+
+// Stats are statistics of a cache.
+type Stats struct {
+	Hits          uint64            `json:"hits"`
+	Misses        uint64            `json:"misses"`
+	ExpiredOnRead uint64            `json:"expiredOnRead"`
+	StaleHits     uint64            `json:"staleHits"`
+	Evictions     map[string]uint64 `json:"evictions"`
+}
@@ -0,0 +1,9 @@
+package inspector
+
+const (
+	ErrNameIsEmpty     = "name is empty"
+	ErrNameIsUsed      = `name is already used: %v`
+	ErrNameIsPublished = `name is published in expvar by other code: %v`
+	ErrSourceIsNil     = "source is nil"
+	ErrCacheNotFound   = `cache is not found: %v`
+)
@@ -0,0 +1,52 @@
+// Package itemcodec contains the format of items which the network servers
+// store in the cache.
+package itemcodec
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Item is data stored in the cache with its expiration time. The cache has a
+// single TTL for all the records, so the expiration time of an item is stored
+// together with its data and is checked on reading:
+//
+//	expiresAt int64, Unix time of expiration in units chosen by the server,
+//	          zero if the item does not expire by itself
+//	data      [...]byte
+type Item struct {
+	ExpiresAt int64
+	Data      []byte
+}
+
+// HeaderSize is the size of the encoded expiration time.
+const HeaderSize = 8
+
+// Encode encodes the item.
+func (it Item) Encode() (buf []byte) {
+	buf = make([]byte, 0, HeaderSize+len(it.Data))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(it.ExpiresAt))
+	buf = append(buf, it.Data...)
+
+	return buf
+}
+
+// Decode parses an item. The data of the item shares memory with the buffer.
+func Decode(buf []byte) (it Item, err error) {
+	if len(buf) < HeaderSize {
+		return it, errors.New(ErrItemIsCorrupted)
+	}
+
+	it = Item{
+		ExpiresAt: int64(binary.BigEndian.Uint64(buf[0:HeaderSize])),
+		Data:      buf[HeaderSize:],
+	}
+
+	return it, nil
+}
+
+// IsAlive checks whether the item is not expired at the moment, which is
+// given in the units of the expiration time.
+func (it Item) IsAlive(now int64) bool {
+	return (it.ExpiresAt == 0) || (now < it.ExpiresAt)
+}
@@ -0,0 +1,39 @@
+package itemcodec
+
+import (
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_Item_Encode(t *testing.T) {
+	aTest := tester.New(t)
+	var it Item
+	var err error
+
+	// Test #1. OK.
+	buf := Item{ExpiresAt: 3, Data: []byte("abc")}.Encode()
+	aTest.MustBeEqual(len(buf), HeaderSize+3)
+	it, err = Decode(buf)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(it, Item{ExpiresAt: 3, Data: []byte("abc")})
+
+	// Test #2. Empty data.
+	it, err = Decode(Item{}.Encode())
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(len(it.Data), 0)
+
+	// Test #3. Corrupted item.
+	_, err = Decode([]byte("abc"))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrItemIsCorrupted)
+}
+
+func Test_Item_IsAlive(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test.
+	aTest.MustBeEqual(Item{ExpiresAt: 0}.IsAlive(100), true)
+	aTest.MustBeEqual(Item{ExpiresAt: 101}.IsAlive(100), true)
+	aTest.MustBeEqual(Item{ExpiresAt: 100}.IsAlive(100), false)
+}
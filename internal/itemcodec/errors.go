@@ -0,0 +1,5 @@
+package itemcodec
+
+const (
+	ErrItemIsCorrupted = "item is corrupted"
+)
@@ -0,0 +1,79 @@
+// Package recordindex contains parts of the caches which keep records in
+// slices of structures without pointers and refer to them by indices, i.e.
+// of the arena and slab caches.
+package recordindex
+
+import (
+	"bytes"
+	"hash/maphash"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+// NoRecord is an index meaning the absence of a record.
+const NoRecord int32 = -1
+
+// EvictionReasonCollision is used when a record is replaced by a record
+// having another UID with the same hash.
+const EvictionReasonCollision vl.EvictionReason = "collision"
+
+// Index maps UIDs to indices of records. UIDs are indexed by their 64-bit
+// hashes, so the index contains no pointers. Two UIDs having the same hash
+// can not be stored at the same time, adding one of them evicts the other.
+// Hashes are seeded randomly for each index, so such collisions are
+// extremely rare and can not be provoked.
+type Index struct {
+	seed    maphash.Seed
+	indices map[uint64]int32
+}
+
+// NewIndex creates an empty index.
+func NewIndex() (idx *Index) {
+	return &Index{
+		seed:    maphash.MakeSeed(),
+		indices: make(map[uint64]int32),
+	}
+}
+
+// Hash returns the hash of the UID.
+func (idx *Index) Hash(uid []byte) uint64 {
+	return maphash.Bytes(idx.seed, uid)
+}
+
+// Get returns the index of the record whose UID has the hash. The record may
+// have another UID with the same hash.
+func (idx *Index) Get(h uint64) (i int32, ok bool) {
+	i, ok = idx.indices[h]
+	return i, ok
+}
+
+// Find returns the index of the record having the UID. The uidOf function
+// returns the UID of a record by its index.
+func (idx *Index) Find(uid []byte, uidOf func(i int32) []byte) (i int32, ok bool) {
+	i, ok = idx.indices[idx.Hash(uid)]
+	if !ok || !bytes.Equal(uidOf(i), uid) {
+		return NoRecord, false
+	}
+
+	return i, true
+}
+
+// Set maps the hash to the index of a record.
+func (idx *Index) Set(h uint64, i int32) {
+	idx.indices[h] = i
+}
+
+// Delete removes the hash from the index.
+func (idx *Index) Delete(h uint64) {
+	delete(idx.indices, h)
+}
+
+// Len returns the number of hashes.
+func (idx *Index) Len() int {
+	return len(idx.indices)
+}
+
+// Clear removes all the hashes. The seed is kept.
+func (idx *Index) Clear() {
+	idx.indices = make(map[uint64]int32)
+}
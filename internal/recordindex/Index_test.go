@@ -0,0 +1,44 @@
+package recordindex
+
+import (
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_Index(t *testing.T) {
+	aTest := tester.New(t)
+	uids := map[int32][]byte{0: []byte("A"), 1: []byte("B")}
+	uidOf := func(i int32) []byte { return uids[i] }
+
+	idx := NewIndex()
+	idx.Set(idx.Hash(uids[0]), 0)
+	idx.Set(idx.Hash(uids[1]), 1)
+	aTest.MustBeEqual(idx.Len(), 2)
+
+	// Test #1. Existing UIDs.
+	i, ok := idx.Find([]byte("B"), uidOf)
+	aTest.MustBeEqual(ok, true)
+	aTest.MustBeEqual(i, int32(1))
+
+	// Test #2. Absent UID.
+	i, ok = idx.Find([]byte("C"), uidOf)
+	aTest.MustBeEqual(ok, false)
+	aTest.MustBeEqual(i, NoRecord)
+
+	// Test #3. A record having another UID with the same hash is not found.
+	// The collision is simulated by moving the record to the hash of
+	// another UID.
+	idx.Delete(idx.Hash(uids[0]))
+	idx.Set(idx.Hash([]byte("C")), 0)
+	_, ok = idx.Get(idx.Hash([]byte("C")))
+	aTest.MustBeEqual(ok, true)
+	_, ok = idx.Find([]byte("C"), uidOf)
+	aTest.MustBeEqual(ok, false)
+
+	// Test #4. Clear.
+	idx.Clear()
+	aTest.MustBeEqual(idx.Len(), 0)
+	_, ok = idx.Find([]byte("B"), uidOf)
+	aTest.MustBeEqual(ok, false)
+}
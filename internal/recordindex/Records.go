@@ -0,0 +1,37 @@
+package recordindex
+
+// Records is a slice of records which are referred to by indices. Elements of
+// removed records are reused.
+type Records[R any] struct {
+	// Items contains records, unused elements are zero.
+	Items []R
+
+	// free contains indices of unused elements of the items.
+	free []int32
+}
+
+// New returns an index of an unused record.
+func (rs *Records[R]) New() (i int32) {
+	if len(rs.free) > 0 {
+		i = rs.free[len(rs.free)-1]
+		rs.free = rs.free[:len(rs.free)-1]
+		return i
+	}
+
+	var zero R
+	rs.Items = append(rs.Items, zero)
+	return int32(len(rs.Items) - 1)
+}
+
+// Free zeroes the record, its element becomes unused.
+func (rs *Records[R]) Free(i int32) {
+	var zero R
+	rs.Items[i] = zero
+	rs.free = append(rs.free, i)
+}
+
+// Clear removes all the records.
+func (rs *Records[R]) Clear() {
+	rs.Items = nil
+	rs.free = nil
+}
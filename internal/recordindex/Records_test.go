@@ -0,0 +1,29 @@
+package recordindex
+
+import (
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_Records(t *testing.T) {
+	aTest := tester.New(t)
+	var rs Records[int]
+
+	// Test #1. New records are appended.
+	aTest.MustBeEqual(rs.New(), int32(0))
+	aTest.MustBeEqual(rs.New(), int32(1))
+	rs.Items[1] = 7
+
+	// Test #2. Freed records are zeroed and reused.
+	rs.Free(1)
+	aTest.MustBeEqual(rs.Items[1], 0)
+	aTest.MustBeEqual(rs.New(), int32(1))
+	aTest.MustBeEqual(len(rs.Items), 2)
+
+	// Test #3. Clear.
+	rs.Free(0)
+	rs.Clear()
+	aTest.MustBeEqual(len(rs.Items), 0)
+	aTest.MustBeEqual(rs.New(), int32(0))
+}
@@ -0,0 +1,54 @@
+package recordindex
+
+import (
+	"bytes"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/internal/uidcodec"
+)
+
+// Stats are statistics of a cache. Evictions are counted by the statistics,
+// which pass evicted records to the eviction callback of the cache. UIDs of
+// records are encoded by the binary encoding of the 'uidcodec' package.
+type Stats[U vl.UidType] struct {
+	vl.Stats
+
+	onEviction func(uid U, data []byte, reason vl.EvictionReason)
+}
+
+// NewStats creates statistics passing evicted records to the callback, which
+// may be nil.
+func NewStats[U vl.UidType](onEviction func(uid U, data []byte, reason vl.EvictionReason)) (s *Stats[U]) {
+	return &Stats[U]{
+		Stats:      vl.Stats{Evictions: make(map[vl.EvictionReason]uint64)},
+		onEviction: onEviction,
+	}
+}
+
+// Evict counts an eviction of the record having the UID and the data, and
+// removes the record by the remove function. The callback is called after
+// the removal, so it receives copies of the UID and the data.
+func (s *Stats[U]) Evict(reason vl.EvictionReason, uid []byte, data []byte, remove func()) {
+	s.Evictions[reason]++
+
+	if s.onEviction == nil {
+		remove()
+		return
+	}
+
+	uidCopy := uidcodec.DecodeBinary[U](uid)
+	dataCopy := bytes.Clone(data)
+	remove()
+	s.onEviction(uidCopy, dataCopy, reason)
+}
+
+// Copy returns a copy of the statistics.
+func (s *Stats[U]) Copy() (stats vl.Stats) {
+	stats = s.Stats
+	stats.Evictions = make(map[vl.EvictionReason]uint64, len(s.Evictions))
+	for reason, count := range s.Evictions {
+		stats.Evictions[reason] = count
+	}
+
+	return stats
+}
@@ -0,0 +1,48 @@
+package recordindex
+
+import (
+	"testing"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_Stats_Evict(t *testing.T) {
+	aTest := tester.New(t)
+	buf := []byte("Aa")
+	var removed bool
+	var evicted []string
+
+	s := NewStats(func(uid string, data []byte, reason vl.EvictionReason) {
+		aTest.MustBeEqual(removed, true)
+		evicted = append(evicted, uid+":"+string(data)+":"+string(reason))
+	})
+
+	// Test #1. The callback receives copies after the removal.
+	s.Evict(vl.EvictionReasonSize, buf[:1], buf[1:], func() {
+		removed = true
+		copy(buf, "Bb")
+	})
+	aTest.MustBeEqual(evicted, []string{"A:a:" + string(vl.EvictionReasonSize)})
+
+	// Test #2. Evictions are counted without a callback.
+	s = NewStats[string](nil)
+	removed = false
+	s.Evict(EvictionReasonCollision, buf[:1], buf[1:], func() { removed = true })
+	aTest.MustBeEqual(removed, true)
+	aTest.MustBeEqual(s.Evictions[EvictionReasonCollision], uint64(1))
+}
+
+func Test_Stats_Copy(t *testing.T) {
+	aTest := tester.New(t)
+
+	s := NewStats[string](nil)
+	s.Hits = 2
+	s.Evictions[vl.EvictionReasonSize] = 1
+
+	// Test.
+	stats := s.Copy()
+	s.Evictions[vl.EvictionReasonSize]++
+	aTest.MustBeEqual(stats.Hits, uint64(2))
+	aTest.MustBeEqual(stats.Evictions[vl.EvictionReasonSize], uint64(1))
+}
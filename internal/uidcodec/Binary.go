@@ -0,0 +1,35 @@
+package uidcodec
+
+import (
+	"encoding/binary"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+// EncodeBinary encodes the UID in the binary encoding.
+func EncodeBinary[U vl.UidType](uid U) (buf []byte) {
+	switch v := any(uid).(type) {
+	case string:
+		return []byte(v)
+	case int:
+		return binary.BigEndian.AppendUint64(nil, uint64(v))
+	case uint:
+		return binary.BigEndian.AppendUint64(nil, uint64(v))
+	}
+
+	return nil
+}
+
+// DecodeBinary decodes a UID encoded by the EncodeBinary function.
+func DecodeBinary[U vl.UidType](buf []byte) (uid U) {
+	switch p := any(&uid).(type) {
+	case *string:
+		*p = string(buf)
+	case *int:
+		*p = int(binary.BigEndian.Uint64(buf))
+	case *uint:
+		*p = uint(binary.BigEndian.Uint64(buf))
+	}
+
+	return uid
+}
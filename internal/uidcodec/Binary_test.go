@@ -0,0 +1,21 @@
+package uidcodec
+
+import (
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_EncodeBinary(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test #1. Encoding.
+	aTest.MustBeEqual(EncodeBinary("abc"), []byte("abc"))
+	aTest.MustBeEqual(EncodeBinary(-1), []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+	aTest.MustBeEqual(EncodeBinary(uint(258)), []byte{0, 0, 0, 0, 0, 0, 1, 2})
+
+	// Test #2. Round trip.
+	aTest.MustBeEqual(DecodeBinary[string](EncodeBinary("abc")), "abc")
+	aTest.MustBeEqual(DecodeBinary[int](EncodeBinary(-5)), -5)
+	aTest.MustBeEqual(DecodeBinary[uint](EncodeBinary(uint(7))), uint(7))
+}
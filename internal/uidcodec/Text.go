@@ -0,0 +1,63 @@
+// Package uidcodec converts UIDs of records to bytes and back. Only string and
+// integer UIDs are supported, i.e. UIDs of the vl.UidType.
+//
+// The text encoding is used where UIDs are stored in files or are sent to
+// other nodes, integers are written as decimal numbers. The binary encoding
+// is used where UIDs are kept in memory, integers are written as 8 big-endian
+// bytes.
+package uidcodec
+
+import (
+	"fmt"
+	"strconv"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+// EncodeText encodes the UID as text.
+func EncodeText[U vl.UidType](uid U) (buf []byte) {
+	// UIDs of the vl.UidType are always supported.
+	buf, _ = EncodeAnyText(uid)
+	return buf
+}
+
+// EncodeAnyText is a variant of the EncodeText function for UIDs whose type
+// is not constrained. A UID of an unsupported type is not encoded.
+func EncodeAnyText(uid any) (buf []byte, err error) {
+	switch v := uid.(type) {
+	case string:
+		return []byte(v), nil
+	case int:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case uint:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	}
+
+	return nil, fmt.Errorf(ErrUidTypeIsNotSupported, uid)
+}
+
+// DecodeText decodes a UID encoded as text.
+func DecodeText[U comparable](buf []byte) (uid U, err error) {
+	switch p := any(&uid).(type) {
+	case *string:
+		*p = string(buf)
+	case *int:
+		var v int64
+		v, err = strconv.ParseInt(string(buf), 10, 0)
+		if err != nil {
+			return uid, fmt.Errorf(ErrUidIsBad, string(buf))
+		}
+		*p = int(v)
+	case *uint:
+		var v uint64
+		v, err = strconv.ParseUint(string(buf), 10, 0)
+		if err != nil {
+			return uid, fmt.Errorf(ErrUidIsBad, string(buf))
+		}
+		*p = uint(v)
+	default:
+		return uid, fmt.Errorf(ErrUidTypeIsNotSupported, uid)
+	}
+
+	return uid, nil
+}
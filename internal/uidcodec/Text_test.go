@@ -0,0 +1,63 @@
+package uidcodec
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_EncodeText(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test.
+	aTest.MustBeEqual(EncodeText("abc"), []byte("abc"))
+	aTest.MustBeEqual(EncodeText(-12), []byte("-12"))
+	aTest.MustBeEqual(EncodeText(uint(12)), []byte("12"))
+}
+
+func Test_EncodeAnyText(t *testing.T) {
+	aTest := tester.New(t)
+	var buf []byte
+	var err error
+
+	// Test #1. Supported types.
+	buf, err = EncodeAnyText("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(string(buf), "A")
+	buf, err = EncodeAnyText(-12)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(string(buf), "-12")
+
+	// Test #2. Unsupported type.
+	_, err = EncodeAnyText(1.5)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrUidTypeIsNotSupported, 1.5))
+}
+
+func Test_DecodeText(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	// Test #1. Supported types.
+	s, err := DecodeText[string]([]byte("A"))
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(s, "A")
+	i, err := DecodeText[int]([]byte("-12"))
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(i, -12)
+	u, err := DecodeText[uint]([]byte("12"))
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(u, uint(12))
+
+	// Test #2. Bad UIDs.
+	_, err = DecodeText[int]([]byte("x"))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrUidIsBad, "x"))
+	_, err = DecodeText[uint]([]byte("-1"))
+	aTest.MustBeAnError(err)
+
+	// Test #3. Unsupported type.
+	_, err = DecodeText[float64]([]byte("1"))
+	aTest.MustBeAnError(err)
+}
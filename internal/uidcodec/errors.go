@@ -0,0 +1,6 @@
+package uidcodec
+
+const (
+	ErrUidTypeIsNotSupported = "uid type is not supported: %T"
+	ErrUidIsBad              = "uid is bad: %v"
+)
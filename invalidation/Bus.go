@@ -0,0 +1,16 @@
+package invalidation
+
+// Bus is a message bus which delivers invalidation events between nodes.
+// Events published by a node may be delivered back to the node itself.
+type Bus interface {
+	// Publish sends the event to all the nodes.
+	Publish(e Event) (err error)
+
+	// Subscribe registers a handler of received events. Handlers are called
+	// sequentially in the order of receiving events from each peer. The
+	// returned function cancels the subscription.
+	Subscribe(handler Handler) (unsubscribe func())
+}
+
+// Handler is a handler of received invalidation events.
+type Handler func(e Event)
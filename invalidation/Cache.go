@@ -0,0 +1,167 @@
+// Package invalidation keeps local caches of several nodes coherent. Changes
+// of records made through a node are published to other nodes as
+// invalidation events, and the nodes remove the changed records from their
+// local caches, so that the records are loaded again instead of being served
+// stale until their TTL expires.
+package invalidation
+
+import (
+	"errors"
+	"fmt"
+
+	cache "github.com/vault-thirteen/Cache"
+	"github.com/vault-thirteen/Cache/internal/uidcodec"
+)
+
+// Cache is a local cache of a node connected to other nodes by a bus. Adding,
+// updating and removal of a record publish an invalidation event of the
+// record, clearing publishes an invalidation event of all the records. Events
+// of other nodes remove records from the local cache, they are not published
+// again.
+type Cache[U comparable, D any] struct {
+	local       cache.Cache[U, D]
+	bus         Bus
+	settings    Settings
+	unsubscribe func()
+}
+
+// New connects the local cache to the bus. Only string and integer UIDs are
+// supported.
+func New[U comparable, D any](local cache.Cache[U, D], bus Bus, settings Settings) (c *Cache[U, D], err error) {
+	if local == nil {
+		return nil, errors.New(ErrCacheIsNil)
+	}
+	if bus == nil {
+		return nil, errors.New(ErrBusIsNil)
+	}
+
+	err = settings.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var uid U
+	_, err = uidcodec.EncodeAnyText(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Cache[U, D]{
+		local:    local,
+		bus:      bus,
+		settings: settings,
+	}
+	c.unsubscribe = bus.Subscribe(c.handle)
+
+	return c, nil
+}
+
+// handle applies an event received from the bus.
+func (c *Cache[U, D]) handle(e Event) {
+	if e.NodeId == c.settings.NodeId {
+		return
+	}
+
+	switch e.Kind {
+	case EventKindRecord:
+		uid, err := uidcodec.DecodeText[U](e.Uid)
+		if err != nil {
+			c.reportError(fmt.Errorf(ErrInvalidationIsFailed, err))
+			return
+		}
+		c.local.RemoveRecord(uid)
+
+	case EventKindAll:
+		err := c.local.Clear()
+		if err != nil {
+			c.reportError(fmt.Errorf(ErrInvalidationIsFailed, err))
+		}
+	}
+}
+
+func (c *Cache[U, D]) publishRecord(uid U) {
+	buf, err := uidcodec.EncodeAnyText(uid)
+	if err != nil {
+		c.reportError(fmt.Errorf(ErrPublicationIsFailed, err))
+		return
+	}
+
+	c.publish(Event{NodeId: c.settings.NodeId, Kind: EventKindRecord, Uid: buf})
+}
+
+func (c *Cache[U, D]) publish(e Event) {
+	err := c.bus.Publish(e)
+	if err != nil {
+		c.reportError(fmt.Errorf(ErrPublicationIsFailed, err))
+	}
+}
+
+func (c *Cache[U, D]) reportError(err error) {
+	if c.settings.OnError != nil {
+		c.settings.OnError(err)
+	}
+}
+
+// Local returns the local cache.
+func (c *Cache[U, D]) Local() (local cache.Cache[U, D]) {
+	return c.local
+}
+
+// RecordExists checks whether the record exists in the local cache.
+func (c *Cache[U, D]) RecordExists(uid U) (recordExists bool) {
+	return c.local.RecordExists(uid)
+}
+
+// AddRecord adds or updates a record in the local cache and invalidates the
+// record on other nodes.
+func (c *Cache[U, D]) AddRecord(uid U, data D) (err error) {
+	err = c.local.AddRecord(uid, data)
+	if err != nil {
+		return err
+	}
+
+	c.publishRecord(uid)
+
+	return nil
+}
+
+// GetRecord reads a record from the local cache.
+func (c *Cache[U, D]) GetRecord(uid U) (data D, err error) {
+	return c.local.GetRecord(uid)
+}
+
+// RemoveRecord removes a record from the local cache and invalidates the
+// record on other nodes.
+func (c *Cache[U, D]) RemoveRecord(uid U) {
+	c.local.RemoveRecord(uid)
+	c.publishRecord(uid)
+}
+
+// RemoveExistingRecord removes an existing record from the local cache and
+// invalidates the record on other nodes. Other nodes are notified even when
+// the record does not exist locally, as they may still have it.
+func (c *Cache[U, D]) RemoveExistingRecord(uid U) (err error) {
+	err = c.local.RemoveExistingRecord(uid)
+	c.publishRecord(uid)
+
+	return err
+}
+
+// Clear removes all records from the local cache and invalidates all the
+// records on other nodes.
+func (c *Cache[U, D]) Clear() (err error) {
+	err = c.local.Clear()
+	if err != nil {
+		return err
+	}
+
+	c.publish(Event{NodeId: c.settings.NodeId, Kind: EventKindAll})
+
+	return nil
+}
+
+// Close disconnects the cache from the bus. Neither the bus nor the local
+// cache is closed.
+func (c *Cache[U, D]) Close() {
+	c.unsubscribe()
+}
@@ -0,0 +1,127 @@
+package invalidation
+
+import (
+	"errors"
+	"testing"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/fake"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_New(t *testing.T) {
+	aTest := tester.New(t)
+	var local = vl.NewCache[string, string](0, 0, 60)
+	var bus = NewLocalBus()
+	var err error
+
+	// Test #1. Bad arguments.
+	_, err = New[string, string](nil, bus, Settings{NodeId: "N1"})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrCacheIsNil)
+	_, err = New[string, string](local, nil, Settings{NodeId: "N1"})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrBusIsNil)
+	_, err = New[string, string](local, bus, Settings{})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrNodeIdIsNotSet)
+	_, err = New[float64, string](fake.New[float64, string](), bus, Settings{NodeId: "N1"})
+	aTest.MustBeAnError(err)
+
+	// Test #2. OK.
+	c, err := New[string, string](local, bus, Settings{NodeId: "N1"})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(c.Local(), local)
+}
+
+func Test_Cache(t *testing.T) {
+	aTest := tester.New(t)
+	var bus = NewLocalBus()
+	var err error
+
+	c1, err := New[string, string](vl.NewCache[string, string](0, 0, 60), bus, Settings{NodeId: "N1"})
+	aTest.MustBeNoError(err)
+	c2, err := New[string, string](vl.NewCache[string, string](0, 0, 60), bus, Settings{NodeId: "N2"})
+	aTest.MustBeNoError(err)
+
+	// Test #1. Updating invalidates the record on other nodes only.
+	aTest.MustBeNoError(c1.Local().AddRecord("A", "1"))
+	aTest.MustBeNoError(c2.Local().AddRecord("A", "1"))
+	aTest.MustBeNoError(c1.AddRecord("A", "2"))
+	data, err := c1.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "2")
+	aTest.MustBeEqual(c2.RecordExists("A"), false)
+
+	// Test #2. Removal.
+	aTest.MustBeNoError(c2.Local().AddRecord("B", "1"))
+	c1.RemoveRecord("B")
+	aTest.MustBeEqual(c2.RecordExists("B"), false)
+	aTest.MustBeNoError(c2.Local().AddRecord("B", "1"))
+	err = c1.RemoveExistingRecord("B")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(c2.RecordExists("B"), false)
+
+	// Test #3. Clearing.
+	aTest.MustBeNoError(c2.Local().AddRecord("C", "1"))
+	aTest.MustBeNoError(c1.Clear())
+	aTest.MustBeEqual(c2.RecordExists("C"), false)
+	aTest.MustBeEqual(c1.RecordExists("A"), false)
+
+	// Test #4. Disconnected cache is not invalidated.
+	c2.Close()
+	aTest.MustBeNoError(c2.AddRecord("D", "1"))
+	aTest.MustBeNoError(c1.AddRecord("D", "2"))
+	aTest.MustBeEqual(c2.RecordExists("D"), true)
+}
+
+func Test_Cache_errors(t *testing.T) {
+	aTest := tester.New(t)
+	var bus = NewLocalBus()
+	var errs []error
+	var onError = func(err error) { errs = append(errs, err) }
+
+	local := fake.New[int, string]()
+	c, err := New[int, string](local, bus, Settings{NodeId: "N1", OnError: onError})
+	aTest.MustBeNoError(err)
+
+	// Test #1. Bad UID of a received event.
+	aTest.MustBeNoError(bus.Publish(Event{NodeId: "N2", Kind: EventKindRecord, Uid: []byte("x")}))
+	aTest.MustBeEqual(len(errs), 1)
+
+	// Test #2. Failure of clearing.
+	local.SetError(fake.MethodClear, errors.New("failure"))
+	aTest.MustBeNoError(bus.Publish(Event{NodeId: "N2", Kind: EventKindAll}))
+	aTest.MustBeEqual(len(errs), 2)
+
+	// Test #3. Failure of publication.
+	tcpBus := _test_listen(t, nil)
+	aTest.MustBeNoError(tcpBus.Close())
+	c, err = New[int, string](fake.New[int, string](), tcpBus, Settings{NodeId: "N1", OnError: onError})
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(c.AddRecord(1, "1"))
+	aTest.MustBeEqual(len(errs), 3)
+}
+
+func Test_Cache_tcp(t *testing.T) {
+	aTest := tester.New(t)
+
+	b1 := _test_listen(t, nil)
+	b2 := _test_listen(t, nil)
+	b1.AddPeer(b2.Addr())
+	b2.AddPeer(b1.Addr())
+
+	c1, err := New[int, string](vl.NewCache[int, string](0, 0, 60), b1, Settings{NodeId: "N1"})
+	aTest.MustBeNoError(err)
+	c2, err := New[int, string](vl.NewCache[int, string](0, 0, 60), b2, Settings{NodeId: "N2"})
+	aTest.MustBeNoError(err)
+
+	// Test.
+	aTest.MustBeNoError(c2.Local().AddRecord(1, "1"))
+	aTest.MustBeNoError(c1.AddRecord(1, "2"))
+	_test_eventually(t, func() bool { return !c2.RecordExists(1) })
+
+	aTest.MustBeNoError(c1.Local().AddRecord(2, "1"))
+	c2.RemoveRecord(2)
+	_test_eventually(t, func() bool { return !c1.RecordExists(2) })
+}
@@ -0,0 +1,82 @@
+package invalidation
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Event is an invalidation event. It tells other nodes that a record, or all
+// the records, have been changed by the node which has published the event.
+type Event struct {
+	// NodeId is an identifier of the node which has published the event.
+	// Nodes ignore their own events.
+	NodeId string
+
+	Kind EventKind
+
+	// Uid is an encoded UID of the invalidated record. It is empty for
+	// events of the EventKindAll kind.
+	Uid []byte
+}
+
+// Events are transferred over the network as frames:
+//
+//	length  uint32, size of the rest of the frame
+//	kind    byte
+//	nodeLen uint16
+//	nodeId  [nodeLen]byte
+//	uid     [length-3-nodeLen]byte
+const (
+	frameLengthSize = 4
+	frameHeaderSize = 1 + 2
+
+	// MaxFrameSize is a maximum size of a frame excluding its length.
+	MaxFrameSize = 64 * 1024
+)
+
+// encode returns the event as a frame.
+func (e Event) encode() (frame []byte, err error) {
+	if len(e.NodeId) > math.MaxUint16 {
+		return nil, errors.New(ErrNodeIdIsTooLong)
+	}
+
+	size := frameHeaderSize + len(e.NodeId) + len(e.Uid)
+	if size > MaxFrameSize {
+		return nil, fmt.Errorf(ErrFrameIsTooBig, size)
+	}
+
+	frame = make([]byte, 0, frameLengthSize+size)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(size))
+	frame = append(frame, byte(e.Kind))
+	frame = binary.BigEndian.AppendUint16(frame, uint16(len(e.NodeId)))
+	frame = append(frame, e.NodeId...)
+	frame = append(frame, e.Uid...)
+
+	return frame, nil
+}
+
+// decodeEvent parses a frame without its length.
+func decodeEvent(buf []byte) (e Event, err error) {
+	if len(buf) < frameHeaderSize {
+		return e, errors.New(ErrFrameIsCorrupted)
+	}
+
+	e.Kind = EventKind(buf[0])
+	if !e.Kind.isValid() {
+		return e, fmt.Errorf(ErrEventKindIsUnknown, buf[0])
+	}
+
+	nodeLen := int(binary.BigEndian.Uint16(buf[1:3]))
+	if len(buf) < frameHeaderSize+nodeLen {
+		return e, errors.New(ErrFrameIsCorrupted)
+	}
+
+	e.NodeId = string(buf[frameHeaderSize : frameHeaderSize+nodeLen])
+	if len(buf) > frameHeaderSize+nodeLen {
+		e.Uid = append([]byte{}, buf[frameHeaderSize+nodeLen:]...)
+	}
+
+	return e, nil
+}
@@ -0,0 +1,16 @@
+package invalidation
+
+// EventKind is a kind of an invalidation event.
+type EventKind byte
+
+const (
+	// EventKindRecord invalidates a single record.
+	EventKindRecord = EventKind(1)
+
+	// EventKindAll invalidates all the records, i.e. clears the cache.
+	EventKindAll = EventKind(2)
+)
+
+func (ek EventKind) isValid() bool {
+	return (ek == EventKindRecord) || (ek == EventKindAll)
+}
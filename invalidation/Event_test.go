@@ -0,0 +1,56 @@
+package invalidation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_Event_encode(t *testing.T) {
+	aTest := tester.New(t)
+	var frame []byte
+	var e Event
+	var err error
+
+	// Test #1. Record.
+	frame, err = Event{NodeId: "N1", Kind: EventKindRecord, Uid: []byte("A")}.encode()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(frame, []byte{0, 0, 0, 6, 1, 0, 2, 'N', '1', 'A'})
+	e, err = decodeEvent(frame[frameLengthSize:])
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(e, Event{NodeId: "N1", Kind: EventKindRecord, Uid: []byte("A")})
+
+	// Test #2. All records.
+	frame, err = Event{NodeId: "N1", Kind: EventKindAll}.encode()
+	aTest.MustBeNoError(err)
+	e, err = decodeEvent(frame[frameLengthSize:])
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(e, Event{NodeId: "N1", Kind: EventKindAll})
+
+	// Test #3. Too big.
+	_, err = Event{NodeId: "N1", Kind: EventKindRecord, Uid: make([]byte, MaxFrameSize)}.encode()
+	aTest.MustBeAnError(err)
+	_, err = Event{NodeId: strings.Repeat("N", 70_000), Kind: EventKindAll}.encode()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrNodeIdIsTooLong)
+}
+
+func Test_decodeEvent(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	// Test #1. Short header.
+	_, err = decodeEvent([]byte{1, 0})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrFrameIsCorrupted)
+
+	// Test #2. Unknown kind.
+	_, err = decodeEvent([]byte{9, 0, 0})
+	aTest.MustBeAnError(err)
+
+	// Test #3. Short node id.
+	_, err = decodeEvent([]byte{1, 0, 5, 'N'})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrFrameIsCorrupted)
+}
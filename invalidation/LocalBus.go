@@ -0,0 +1,26 @@
+package invalidation
+
+// LocalBus is an in-process bus. Events are delivered synchronously to all
+// the subscribers, including the publisher. It connects caches of a single
+// process, e.g. in tests.
+type LocalBus struct {
+	subscribers *subscribers
+}
+
+// NewLocalBus creates an in-process bus.
+func NewLocalBus() (b *LocalBus) {
+	return &LocalBus{
+		subscribers: newSubscribers(),
+	}
+}
+
+// Publish delivers the event to all the subscribers.
+func (b *LocalBus) Publish(e Event) (err error) {
+	b.subscribers.notify(e)
+	return nil
+}
+
+// Subscribe registers a handler of events.
+func (b *LocalBus) Subscribe(handler Handler) (unsubscribe func()) {
+	return b.subscribers.add(handler)
+}
@@ -0,0 +1,28 @@
+package invalidation
+
+import (
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_LocalBus(t *testing.T) {
+	aTest := tester.New(t)
+	var b = NewLocalBus()
+	var received1, received2 []Event
+
+	unsubscribe1 := b.Subscribe(func(e Event) { received1 = append(received1, e) })
+	_ = b.Subscribe(func(e Event) { received2 = append(received2, e) })
+
+	// Test #1. All subscribers receive events.
+	e := Event{NodeId: "N1", Kind: EventKindAll}
+	aTest.MustBeNoError(b.Publish(e))
+	aTest.MustBeEqual(received1, []Event{e})
+	aTest.MustBeEqual(received2, []Event{e})
+
+	// Test #2. Subscription is cancelled.
+	unsubscribe1()
+	aTest.MustBeNoError(b.Publish(e))
+	aTest.MustBeEqual(len(received1), 1)
+	aTest.MustBeEqual(len(received2), 2)
+}
@@ -0,0 +1,25 @@
+package invalidation
+
+import "errors"
+
+// Settings contains settings of an invalidated cache.
+type Settings struct {
+	// NodeId is a unique identifier of the node, e.g. a host name. Events
+	// published by the node are marked with it, and received events with
+	// the same identifier are ignored, so that an event does not return to
+	// its publisher.
+	NodeId string
+
+	// OnError is an optional callback which receives errors which can not be
+	// returned to a caller, i.e. errors of publication of events and errors
+	// of applying received events.
+	OnError func(err error)
+}
+
+func (s Settings) validate() (err error) {
+	if len(s.NodeId) == 0 {
+		return errors.New(ErrNodeIdIsNotSet)
+	}
+
+	return nil
+}
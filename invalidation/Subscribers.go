@@ -0,0 +1,48 @@
+package invalidation
+
+import "sync"
+
+// subscribers is a set of handlers of a bus.
+type subscribers struct {
+	handlers map[uint64]Handler
+	nextId   uint64
+	lock     *sync.Mutex
+}
+
+func newSubscribers() (s *subscribers) {
+	return &subscribers{
+		handlers: make(map[uint64]Handler),
+		lock:     new(sync.Mutex),
+	}
+}
+
+func (s *subscribers) add(handler Handler) (unsubscribe func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	id := s.nextId
+	s.nextId++
+	s.handlers[id] = handler
+
+	return func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		delete(s.handlers, id)
+	}
+}
+
+// notify calls the handlers. Handlers are copied, so that a handler may
+// cancel its subscription.
+func (s *subscribers) notify(e Event) {
+	s.lock.Lock()
+	handlers := make([]Handler, 0, len(s.handlers))
+	for _, h := range s.handlers {
+		handlers = append(handlers, h)
+	}
+	s.lock.Unlock()
+
+	for _, h := range handlers {
+		h(e)
+	}
+}
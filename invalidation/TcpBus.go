@@ -0,0 +1,243 @@
+package invalidation
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// TcpBus is a bus which connects nodes over TCP. Each node listens for events
+// of other nodes and sends its own events to every peer over a persistent
+// connection which is re-established after a failure. Events are queued for
+// each peer and are sent by a goroutine of the peer, so a slow peer does not
+// delay others. Delivery is not guaranteed: events published while a peer is
+// unreachable or while its queue is full are lost for that peer, so the
+// records' TTL remains the upper bound of staleness.
+type TcpBus struct {
+	settings    TcpSettings
+	listener    net.Listener
+	subscribers *subscribers
+
+	// peers maps addresses of peers to their outgoing links.
+	peers map[string]*tcpPeer
+
+	// inbound contains accepted connections of peers.
+	inbound  map[net.Conn]struct{}
+	isClosed bool
+
+	// lock guards peers and inbound connections. Events are queued while it
+	// is held, so that peers receive events in the same order.
+	lock *sync.Mutex
+	wg   *sync.WaitGroup
+}
+
+// ListenTcp creates a TCP bus listening on the address of the settings.
+func ListenTcp(settings TcpSettings) (b *TcpBus, err error) {
+	err = settings.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	b = &TcpBus{
+		settings:    settings.withDefaults(),
+		subscribers: newSubscribers(),
+		peers:       make(map[string]*tcpPeer),
+		inbound:     make(map[net.Conn]struct{}),
+		lock:        new(sync.Mutex),
+		wg:          new(sync.WaitGroup),
+	}
+
+	b.listener, err = net.Listen("tcp", settings.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	b.wg.Add(1)
+	go b.accept()
+
+	for _, address := range settings.Peers {
+		b.AddPeer(address)
+	}
+
+	return b, nil
+}
+
+// Addr returns the address on which the bus accepts events.
+func (b *TcpBus) Addr() (address string) {
+	return b.listener.Addr().String()
+}
+
+// AddPeer adds a node to which events are sent.
+func (b *TcpBus) AddPeer(address string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	_, peerExists := b.peers[address]
+	if peerExists || b.isClosed {
+		return
+	}
+
+	p := newTcpPeer(address, b.settings)
+	b.peers[address] = p
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		p.run(b.reportError)
+	}()
+}
+
+// RemovePeer stops sending events to a node. Events queued for the node are
+// dropped.
+func (b *TcpBus) RemovePeer(address string) {
+	b.lock.Lock()
+	p := b.peers[address]
+	delete(b.peers, address)
+	b.lock.Unlock()
+
+	if p != nil {
+		p.stop()
+	}
+}
+
+// Publish queues the event for all the peers without waiting for it to be
+// sent. Errors of peers whose queues are full are joined, the event is still
+// queued for other peers. Errors of sending are passed to the error callback.
+// The event is not delivered to subscribers of this bus.
+func (b *TcpBus) Publish(e Event) (err error) {
+	var frame []byte
+	frame, err = e.encode()
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.isClosed {
+		return errors.New(ErrBusIsClosed)
+	}
+
+	var errs []error
+	for _, p := range b.peers {
+		err = p.enqueue(frame)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Subscribe registers a handler of events received from peers.
+func (b *TcpBus) Subscribe(handler Handler) (unsubscribe func()) {
+	return b.subscribers.add(handler)
+}
+
+func (b *TcpBus) accept() {
+	defer b.wg.Done()
+
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				b.reportError(err)
+			}
+			return
+		}
+
+		b.lock.Lock()
+		if b.isClosed {
+			b.lock.Unlock()
+			_ = conn.Close()
+			return
+		}
+		b.inbound[conn] = struct{}{}
+		b.wg.Add(1)
+		b.lock.Unlock()
+
+		go b.serve(conn)
+	}
+}
+
+// serve reads events of a peer until the connection is closed. A malformed
+// frame breaks the connection.
+func (b *TcpBus) serve(conn net.Conn) {
+	defer b.wg.Done()
+	defer func() {
+		b.lock.Lock()
+		delete(b.inbound, conn)
+		b.lock.Unlock()
+
+		_ = conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	lengthBuf := make([]byte, frameLengthSize)
+	for {
+		_, err := io.ReadFull(r, lengthBuf)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				b.reportError(err)
+			}
+			return
+		}
+
+		size := binary.BigEndian.Uint32(lengthBuf)
+		if size > MaxFrameSize {
+			b.reportError(fmt.Errorf(ErrFrameIsTooBig, size))
+			return
+		}
+
+		buf := make([]byte, size)
+		_, err = io.ReadFull(r, buf)
+		if err != nil {
+			b.reportError(err)
+			return
+		}
+
+		var e Event
+		e, err = decodeEvent(buf)
+		if err != nil {
+			b.reportError(err)
+			return
+		}
+
+		b.subscribers.notify(e)
+	}
+}
+
+func (b *TcpBus) reportError(err error) {
+	if b.settings.OnError != nil {
+		b.settings.OnError(err)
+	}
+}
+
+// Close stops listening and closes all the connections. Events which are not
+// sent yet are dropped. It waits for handlers of received events to return.
+// The bus must not be used after closing.
+func (b *TcpBus) Close() (err error) {
+	b.lock.Lock()
+	if b.isClosed {
+		b.lock.Unlock()
+		return nil
+	}
+	b.isClosed = true
+
+	err = b.listener.Close()
+	for _, p := range b.peers {
+		p.stop()
+	}
+	for conn := range b.inbound {
+		_ = conn.Close()
+	}
+	b.lock.Unlock()
+
+	b.wg.Wait()
+
+	return err
+}
@@ -0,0 +1,165 @@
+package invalidation
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_ListenTcp(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	// Test #1. No address.
+	_, err = ListenTcp(TcpSettings{})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrAddressIsNotSet)
+
+	// Test #2. Bad address.
+	_, err = ListenTcp(TcpSettings{Address: "127.0.0.1:-1"})
+	aTest.MustBeAnError(err)
+
+	// Test #3. Negative timeout.
+	_, err = ListenTcp(TcpSettings{Address: "127.0.0.1:0", WriteTimeout: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrTimeoutIsNegative)
+
+	// Test #4. Negative queue size.
+	_, err = ListenTcp(TcpSettings{Address: "127.0.0.1:0", QueueSize: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrQueueSizeIsNegative)
+
+	// Test #5. Default settings.
+	b, err := ListenTcp(TcpSettings{Address: "127.0.0.1:0"})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(b.settings.DialTimeout, DefaultDialTimeout)
+	aTest.MustBeEqual(b.settings.WriteTimeout, DefaultWriteTimeout)
+	aTest.MustBeEqual(b.settings.QueueSize, DefaultQueueSize)
+	aTest.MustBeNoError(b.Close())
+}
+
+func Test_TcpBus(t *testing.T) {
+	aTest := tester.New(t)
+	var events = make(chan Event, 10)
+	var errs = make(chan error, 10)
+
+	b1 := _test_listen(t, func(err error) { errs <- err })
+	b2 := _test_listen(t, nil)
+	b2.Subscribe(func(e Event) { events <- e })
+
+	// Test #1. Event is delivered to the peer.
+	b1.AddPeer(b2.Addr())
+	e := Event{NodeId: "N1", Kind: EventKindRecord, Uid: []byte("A")}
+	aTest.MustBeNoError(b1.Publish(e))
+	aTest.MustBeEqual(<-events, e)
+
+	// Test #2. Order of events is kept.
+	for _, uid := range []string{"B", "C", "D"} {
+		aTest.MustBeNoError(b1.Publish(Event{NodeId: "N1", Kind: EventKindRecord, Uid: []byte(uid)}))
+	}
+	for _, uid := range []string{"B", "C", "D"} {
+		aTest.MustBeEqual(string((<-events).Uid), uid)
+	}
+
+	// Test #3. Unreachable peer does not stop delivery to other peers.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	aTest.MustBeNoError(err)
+	deadAddress := l.Addr().String()
+	aTest.MustBeNoError(l.Close())
+	b1.AddPeer(deadAddress)
+	aTest.MustBeNoError(b1.Publish(e))
+	aTest.MustBeEqual(<-events, e)
+	err = <-errs
+	aTest.MustBeEqual(errors.Is(err, syscall.ECONNREFUSED), true)
+	b1.RemovePeer(deadAddress)
+	aTest.MustBeNoError(b1.Publish(e))
+	aTest.MustBeEqual(<-events, e)
+
+	// Test #4. Closed bus.
+	aTest.MustBeNoError(b1.Close())
+	err = b1.Publish(e)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrBusIsClosed)
+	aTest.MustBeNoError(b1.Close())
+}
+
+func Test_tcpPeer_enqueue(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	p := newTcpPeer("127.0.0.1:1", TcpSettings{QueueSize: 1})
+	defer p.stop()
+
+	// Test #1. Frame is queued.
+	aTest.MustBeNoError(p.enqueue([]byte("A")))
+
+	// Test #2. Queue is full.
+	err = p.enqueue([]byte("B"))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrQueueIsFull, "127.0.0.1:1"))
+	aTest.MustBeEqual(string(<-p.queue), "A")
+}
+
+func Test_TcpBus_reconnection(t *testing.T) {
+	aTest := tester.New(t)
+	var events = make(chan Event, 100)
+
+	b1 := _test_listen(t, nil)
+	b2 := _test_listen(t, nil)
+	address := b2.Addr()
+	b1.AddPeer(address)
+	e := Event{NodeId: "N1", Kind: EventKindAll}
+	aTest.MustBeNoError(b1.Publish(e))
+
+	// Test. The peer is restarted on the same address. Events published
+	// through the broken connection may be lost.
+	aTest.MustBeNoError(b2.Close())
+	b3, err := ListenTcp(TcpSettings{Address: address})
+	if err != nil {
+		t.Skip(err)
+	}
+	defer func() { _ = b3.Close() }()
+	b3.Subscribe(func(e Event) { events <- e })
+
+	_test_eventually(t, func() bool {
+		_ = b1.Publish(e)
+		select {
+		case <-events:
+			return true
+		case <-time.After(time.Millisecond * 10):
+			return false
+		}
+	})
+}
+
+func Test_TcpBus_malformedFrame(t *testing.T) {
+	aTest := tester.New(t)
+	var errs []error
+	var lock sync.Mutex
+
+	b := _test_listen(t, func(err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		errs = append(errs, err)
+	})
+
+	// Test.
+	conn, err := net.Dial("tcp", b.Addr())
+	aTest.MustBeNoError(err)
+	defer func() { _ = conn.Close() }()
+	_, err = conn.Write(binary.BigEndian.AppendUint32(nil, MaxFrameSize+1))
+	aTest.MustBeNoError(err)
+
+	_test_eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(errs) == 1
+	})
+}
@@ -0,0 +1,120 @@
+package invalidation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// tcpPeer is an outgoing link to a peer. Frames are queued by the bus and are
+// sent by a goroutine of the peer, so that a slow or unreachable peer does
+// not delay publications and other peers.
+type tcpPeer struct {
+	address  string
+	settings TcpSettings
+	queue    chan []byte
+
+	// ctx is cancelled when the peer is stopped. It interrupts connecting
+	// to the peer.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// conn is a connection to the peer. It is nil until the first frame is
+	// sent and after a failure. The lock guards it against the sender and
+	// the stop method which closes the connection to interrupt a write.
+	conn net.Conn
+	lock *sync.Mutex
+}
+
+func newTcpPeer(address string, settings TcpSettings) (p *tcpPeer) {
+	p = &tcpPeer{
+		address:  address,
+		settings: settings,
+		queue:    make(chan []byte, settings.QueueSize),
+		lock:     new(sync.Mutex),
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	return p
+}
+
+// enqueue queues the frame without waiting. When the queue is full, the frame
+// is dropped.
+func (p *tcpPeer) enqueue(frame []byte) (err error) {
+	select {
+	case p.queue <- frame:
+		return nil
+	default:
+		return fmt.Errorf(ErrQueueIsFull, p.address)
+	}
+}
+
+// run sends queued frames until the peer is stopped. A frame which fails to
+// be sent is dropped and its error is reported.
+func (p *tcpPeer) run(reportError func(err error)) {
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+
+		case frame := <-p.queue:
+			err := p.send(frame)
+			if (err != nil) && (p.ctx.Err() == nil) {
+				reportError(fmt.Errorf(ErrPeerIsUnreachable, p.address, err))
+			}
+		}
+	}
+}
+
+// send writes the frame to the peer, connecting to it when needed. A broken
+// connection is closed and is re-established by the next frame.
+func (p *tcpPeer) send(frame []byte) (err error) {
+	p.lock.Lock()
+	conn := p.conn
+	p.lock.Unlock()
+
+	if conn == nil {
+		dialer := net.Dialer{Timeout: p.settings.DialTimeout}
+		conn, err = dialer.DialContext(p.ctx, "tcp", p.address)
+		if err != nil {
+			return err
+		}
+
+		p.lock.Lock()
+		if p.ctx.Err() != nil {
+			p.lock.Unlock()
+			_ = conn.Close()
+			return p.ctx.Err()
+		}
+		p.conn = conn
+		p.lock.Unlock()
+	}
+
+	err = conn.SetWriteDeadline(time.Now().Add(p.settings.WriteTimeout))
+	if err == nil {
+		_, err = conn.Write(frame)
+	}
+	if err != nil {
+		p.lock.Lock()
+		_ = conn.Close()
+		p.conn = nil
+		p.lock.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// stop stops the sender and closes the connection. Queued frames are dropped.
+func (p *tcpPeer) stop() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.cancel()
+	if p.conn != nil {
+		_ = p.conn.Close()
+		p.conn = nil
+	}
+}
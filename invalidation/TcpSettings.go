@@ -0,0 +1,77 @@
+package invalidation
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultDialTimeout is the default timeout of connecting to a peer.
+	DefaultDialTimeout = time.Second * 5
+
+	// DefaultWriteTimeout is the default timeout of sending an event to a
+	// peer.
+	DefaultWriteTimeout = time.Second * 5
+
+	// DefaultQueueSize is the default maximum number of events waiting to
+	// be sent to a peer.
+	DefaultQueueSize = 1024
+)
+
+// TcpSettings contains settings of the TCP bus.
+type TcpSettings struct {
+	// Address is a TCP address on which the bus accepts events of other
+	// nodes, e.g. "127.0.0.1:7001". Port zero selects a free port.
+	Address string
+
+	// Peers are TCP addresses of other nodes. More peers may be added
+	// later using the AddPeer method.
+	Peers []string
+
+	// DialTimeout is a timeout of connecting to a peer. Zero selects the
+	// default timeout.
+	DialTimeout time.Duration
+
+	// WriteTimeout is a timeout of sending an event to a peer. Zero selects
+	// the default timeout.
+	WriteTimeout time.Duration
+
+	// QueueSize is a maximum number of events waiting to be sent to a peer.
+	// Zero selects the default size.
+	QueueSize int
+
+	// OnError is an optional callback which receives errors of receiving
+	// events, e.g. errors of malformed frames, and errors of sending events
+	// to unreachable peers.
+	OnError func(err error)
+}
+
+func (s TcpSettings) validate() (err error) {
+	if len(s.Address) == 0 {
+		return errors.New(ErrAddressIsNotSet)
+	}
+	if (s.DialTimeout < 0) || (s.WriteTimeout < 0) {
+		return errors.New(ErrTimeoutIsNegative)
+	}
+	if s.QueueSize < 0 {
+		return errors.New(ErrQueueSizeIsNegative)
+	}
+
+	return nil
+}
+
+// withDefaults returns the settings where zero values are replaced by
+// default values.
+func (s TcpSettings) withDefaults() TcpSettings {
+	if s.DialTimeout == 0 {
+		s.DialTimeout = DefaultDialTimeout
+	}
+	if s.WriteTimeout == 0 {
+		s.WriteTimeout = DefaultWriteTimeout
+	}
+	if s.QueueSize == 0 {
+		s.QueueSize = DefaultQueueSize
+	}
+
+	return s
+}
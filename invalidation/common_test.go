@@ -0,0 +1,33 @@
+package invalidation
+
+import (
+	"testing"
+	"time"
+)
+
+// _test_listen creates a TCP bus on a free local port.
+func _test_listen(t *testing.T, onError func(err error)) (b *TcpBus) {
+	b, err := ListenTcp(TcpSettings{
+		Address:      "127.0.0.1:0",
+		DialTimeout:  time.Second,
+		WriteTimeout: time.Second,
+		OnError:      onError,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = b.Close() })
+
+	return b
+}
+
+// _test_eventually waits for the condition to become true.
+func _test_eventually(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(time.Second * 5)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition is not met")
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+}
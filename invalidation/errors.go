@@ -0,0 +1,19 @@
+package invalidation
+
+const (
+	ErrCacheIsNil           = "cache is nil"
+	ErrBusIsNil             = "bus is nil"
+	ErrNodeIdIsNotSet       = "node id is not set"
+	ErrNodeIdIsTooLong      = "node id is too long"
+	ErrEventKindIsUnknown   = "event kind is unknown: %v"
+	ErrFrameIsTooBig        = "frame is too big: %v"
+	ErrFrameIsCorrupted     = "frame is corrupted"
+	ErrBusIsClosed          = "bus is closed"
+	ErrAddressIsNotSet      = "address is not set"
+	ErrPublicationIsFailed  = "publication is failed: %w"
+	ErrPeerIsUnreachable    = "peer is unreachable: %v: %w"
+	ErrQueueIsFull          = "queue of peer is full: %v"
+	ErrTimeoutIsNegative    = "timeout is negative"
+	ErrQueueSizeIsNegative  = "queue size is negative"
+	ErrInvalidationIsFailed = "invalidation is failed: %w"
+)
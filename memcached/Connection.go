@@ -0,0 +1,481 @@
+package memcached
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+// Protocol limits.
+const (
+	maxLineLength = 2048
+	maxKeyLength  = 250
+)
+
+// Responses.
+const (
+	respError       = "ERROR"
+	respClientError = "CLIENT_ERROR "
+	respServerError = "SERVER_ERROR "
+	respStored      = "STORED"
+	respNotStored   = "NOT_STORED"
+	respDeleted     = "DELETED"
+	respNotFound    = "NOT_FOUND"
+	respTouched     = "TOUCHED"
+	respOk          = "OK"
+	respEnd         = "END"
+	respValue       = "VALUE"
+	respStat        = "STAT"
+	respVersion     = "VERSION"
+)
+
+const crlf = "\r\n"
+
+// Version is the version of the server reported by the version command.
+const Version = "1.6.0-vl"
+
+// errQuit is returned by a handler of the quit command.
+var errQuit = errors.New("quit")
+
+// connection is a client's connection to the server.
+type connection struct {
+	server *Server
+	conn   net.Conn
+	r      *bufio.Reader
+	w      *bufio.Writer
+}
+
+func newConnection(server *Server, conn net.Conn) (c *connection) {
+	return &connection{
+		server: server,
+		conn:   conn,
+		r:      bufio.NewReader(conn),
+		w:      bufio.NewWriter(conn),
+	}
+}
+
+// serve executes commands of the client until the connection is closed.
+// Responses are flushed when there are no more pipelined commands.
+func (c *connection) serve() (err error) {
+	for {
+		var line string
+		line, err = c.readLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			if err.Error() == ErrLineIsTooLong {
+				c.setWriteDeadline()
+				c.writeClientError(ErrLineIsTooLong)
+				return c.w.Flush()
+			}
+			return err
+		}
+
+		c.setWriteDeadline()
+		err = c.execute(line)
+		if err != nil {
+			if err == errQuit {
+				return c.w.Flush()
+			}
+			return err
+		}
+
+		if c.r.Buffered() == 0 {
+			err = c.w.Flush()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// setWriteDeadline limits the time of writing responses of a command by the
+// write timeout. It is set before the command is executed, as the buffered
+// writer flushes itself when its buffer is full.
+func (c *connection) setWriteDeadline() {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.server.settings.WriteTimeout))
+}
+
+// readLine reads a command line without its line terminator.
+func (c *connection) readLine() (line string, err error) {
+	var buf []byte
+	for {
+		var chunk []byte
+		var isPrefix bool
+		chunk, isPrefix, err = c.r.ReadLine()
+		if err != nil {
+			return "", err
+		}
+
+		buf = append(buf, chunk...)
+		if len(buf) > maxLineLength {
+			return "", errors.New(ErrLineIsTooLong)
+		}
+		if !isPrefix {
+			return string(buf), nil
+		}
+	}
+}
+
+// execute executes a command line. Errors of the client are reported to the
+// client, a returned error breaks the connection.
+func (c *connection) execute(line string) (err error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		c.writeLine(respError)
+		return nil
+	}
+
+	args := fields[1:]
+	switch fields[0] {
+	case "get":
+		return c.get(args, false)
+	case "gets":
+		return c.get(args, true)
+	case "set":
+		return c.store(storeModeSet, args)
+	case "add":
+		return c.store(storeModeAdd, args)
+	case "replace":
+		return c.store(storeModeReplace, args)
+	case "delete":
+		return c.delete(args)
+	case "touch":
+		return c.touch(args)
+	case "flush_all":
+		return c.flushAll(args)
+	case "stats":
+		return c.stats(args)
+	case "version":
+		c.writeLine(respVersion + " " + Version)
+		return nil
+	case "quit":
+		return errQuit
+	default:
+		c.writeLine(respError)
+		return nil
+	}
+}
+
+// get ::= get|gets <key>*
+func (c *connection) get(args []string, withCas bool) (err error) {
+	if len(args) == 0 {
+		c.writeLine(respError)
+		return nil
+	}
+	for _, key := range args {
+		if !isKeyValid(key) {
+			c.writeClientError(ErrCommandLineFormatIsBad)
+			return nil
+		}
+	}
+
+	for _, key := range args {
+		c.server.counters.cmdGet.Add(1)
+
+		it, found := c.server.getItem(key)
+		if !found {
+			c.server.counters.getMisses.Add(1)
+			continue
+		}
+		c.server.counters.getHits.Add(1)
+
+		header := fmt.Sprintf("%s %s %d %d", respValue, key, it.flags, len(it.value))
+		if withCas {
+			header += " " + strconv.FormatUint(it.cas, 10)
+		}
+		c.writeLine(header)
+		_, _ = c.w.Write(it.value)
+		_, _ = c.w.WriteString(crlf)
+	}
+
+	c.writeLine(respEnd)
+
+	return nil
+}
+
+// store ::= set|add|replace <key> <flags> <exptime> <bytes> [noreply]
+func (c *connection) store(mode storeMode, args []string) (err error) {
+	if (len(args) < 4) || (len(args) > 5) {
+		c.writeLine(respError)
+		return nil
+	}
+
+	key := args[0]
+	flags, flagsErr := strconv.ParseUint(args[1], 10, 32)
+	expTime, expTimeErr := strconv.ParseInt(args[2], 10, 64)
+	size, sizeErr := strconv.Atoi(args[3])
+	noReply := (len(args) == 5) && (args[4] == "noreply")
+	if (sizeErr != nil) || (size < 0) {
+		c.writeClientError(ErrCommandLineFormatIsBad)
+		return nil
+	}
+
+	// In other cases of errors the value is skipped, so that the connection
+	// stays usable.
+	if !isKeyValid(key) || (flagsErr != nil) || (expTimeErr != nil) {
+		err = c.skipValue(size)
+		if err != nil {
+			return err
+		}
+
+		c.writeClientError(ErrCommandLineFormatIsBad)
+		return nil
+	}
+
+	if size > c.server.settings.MaxValueSize {
+		err = c.skipValue(size)
+		if err != nil {
+			return err
+		}
+
+		c.writeServerError(ErrObjectIsTooLarge)
+		return nil
+	}
+
+	value := make([]byte, size+len(crlf))
+	_, err = io.ReadFull(c.r, value)
+	if err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(value, []byte(crlf)) {
+		c.writeClientError(ErrDataChunkIsBad)
+		return nil
+	}
+	value = value[:size]
+
+	c.server.counters.cmdSet.Add(1)
+
+	var isStored bool
+	isStored, err = c.server.storeItem(mode, key, uint32(flags), expTime, value)
+	if err != nil {
+		if err.Error() == vl.ErrRecordIsTooBig {
+			c.writeServerError(ErrObjectIsTooLarge)
+		} else {
+			c.writeServerError(err.Error())
+		}
+		return nil
+	}
+
+	if noReply {
+		return nil
+	}
+	if isStored {
+		c.writeLine(respStored)
+	} else {
+		c.writeLine(respNotStored)
+	}
+
+	return nil
+}
+
+// skipValue reads and discards a data block of the size.
+func (c *connection) skipValue(size int) (err error) {
+	_, err = io.CopyN(io.Discard, c.r, int64(size+len(crlf)))
+	return err
+}
+
+// delete ::= delete <key> [noreply]
+func (c *connection) delete(args []string) (err error) {
+	if (len(args) < 1) || (len(args) > 2) {
+		c.writeLine(respError)
+		return nil
+	}
+
+	key := args[0]
+	noReply := (len(args) == 2) && (args[1] == "noreply")
+	if !isKeyValid(key) {
+		c.writeClientError(ErrCommandLineFormatIsBad)
+		return nil
+	}
+
+	found := c.server.deleteItem(key)
+	if found {
+		c.server.counters.deleteHits.Add(1)
+	} else {
+		c.server.counters.deleteMisses.Add(1)
+	}
+
+	if noReply {
+		return nil
+	}
+	if found {
+		c.writeLine(respDeleted)
+	} else {
+		c.writeLine(respNotFound)
+	}
+
+	return nil
+}
+
+// touch ::= touch <key> <exptime> [noreply]
+func (c *connection) touch(args []string) (err error) {
+	if (len(args) < 2) || (len(args) > 3) {
+		c.writeLine(respError)
+		return nil
+	}
+
+	key := args[0]
+	expTime, expTimeErr := strconv.ParseInt(args[1], 10, 64)
+	noReply := (len(args) == 3) && (args[2] == "noreply")
+	if !isKeyValid(key) || (expTimeErr != nil) {
+		c.writeClientError(ErrCommandLineFormatIsBad)
+		return nil
+	}
+
+	c.server.counters.cmdTouch.Add(1)
+
+	var found bool
+	found, err = c.server.touchItem(key, expTime)
+	if err != nil {
+		c.writeServerError(err.Error())
+		return nil
+	}
+	if found {
+		c.server.counters.touchHits.Add(1)
+	} else {
+		c.server.counters.touchMisses.Add(1)
+	}
+
+	if noReply {
+		return nil
+	}
+	if found {
+		c.writeLine(respTouched)
+	} else {
+		c.writeLine(respNotFound)
+	}
+
+	return nil
+}
+
+// flush_all ::= flush_all [delay] [noreply]
+//
+// Only immediate flushing is supported.
+func (c *connection) flushAll(args []string) (err error) {
+	noReply := (len(args) > 0) && (args[len(args)-1] == "noreply")
+	if noReply {
+		args = args[:len(args)-1]
+	}
+	if len(args) > 1 {
+		c.writeLine(respError)
+		return nil
+	}
+	if len(args) == 1 {
+		delay, delayErr := strconv.ParseInt(args[0], 10, 64)
+		if delayErr != nil {
+			c.writeClientError(ErrCommandLineFormatIsBad)
+			return nil
+		}
+		if delay != 0 {
+			c.writeClientError(ErrDelayedFlushIsNotAllowed)
+			return nil
+		}
+	}
+
+	c.server.counters.cmdFlush.Add(1)
+
+	err = c.server.flushAll()
+	if err != nil {
+		c.writeServerError(err.Error())
+		return nil
+	}
+
+	if !noReply {
+		c.writeLine(respOk)
+	}
+
+	return nil
+}
+
+// stats ::= stats
+//
+// Only general statistics are supported.
+func (c *connection) stats(args []string) (err error) {
+	if len(args) > 0 {
+		c.writeLine(respError)
+		return nil
+	}
+
+	s := c.server
+	now := s.now()
+	size, _ := s.cache.GetSize()
+	volume, volumeLimit := s.cache.GetVolume()
+	cacheStats := s.cache.GetStats()
+	var evictions uint64
+	for reason, count := range cacheStats.Evictions {
+		if reason != vl.EvictionReasonExpired {
+			evictions += count
+		}
+	}
+
+	stats := []struct {
+		name  string
+		value any
+	}{
+		{"pid", os.Getpid()},
+		{"uptime", int64(now.Sub(s.startTime) / time.Second)},
+		{"time", now.Unix()},
+		{"version", Version},
+		{"curr_connections", s.counters.connections.Load()},
+		{"total_connections", s.counters.totalConnections.Load()},
+		{"cmd_get", s.counters.cmdGet.Load()},
+		{"cmd_set", s.counters.cmdSet.Load()},
+		{"cmd_flush", s.counters.cmdFlush.Load()},
+		{"cmd_touch", s.counters.cmdTouch.Load()},
+		{"get_hits", s.counters.getHits.Load()},
+		{"get_misses", s.counters.getMisses.Load()},
+		{"delete_hits", s.counters.deleteHits.Load()},
+		{"delete_misses", s.counters.deleteMisses.Load()},
+		{"touch_hits", s.counters.touchHits.Load()},
+		{"touch_misses", s.counters.touchMisses.Load()},
+		{"curr_items", size},
+		{"bytes", volume},
+		{"limit_maxbytes", volumeLimit},
+		{"evictions", evictions},
+	}
+	for _, st := range stats {
+		c.writeLine(fmt.Sprintf("%s %s %v", respStat, st.name, st.value))
+	}
+	c.writeLine(respEnd)
+
+	return nil
+}
+
+func (c *connection) writeLine(line string) {
+	_, _ = c.w.WriteString(line)
+	_, _ = c.w.WriteString(crlf)
+}
+
+func (c *connection) writeClientError(msg string) {
+	c.writeLine(respClientError + msg)
+}
+
+func (c *connection) writeServerError(msg string) {
+	c.writeLine(respServerError + msg)
+}
+
+// isKeyValid checks the length of a key and absence of control characters.
+func isKeyValid(key string) bool {
+	if (len(key) == 0) || (len(key) > maxKeyLength) {
+		return false
+	}
+
+	for i := 0; i < len(key); i++ {
+		if (key[i] <= ' ') || (key[i] == 0x7f) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,236 @@
+package memcached
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_connection_storage(t *testing.T) {
+	aTest := tester.New(t)
+
+	_, client, _ := _test_start(t, vl.Config[string, []byte]{}, Settings{})
+
+	// Test #1. Set and get.
+	aTest.MustBeEqual(client.do("set a 5 0 3\r\nabc\r\n", 1), "STORED")
+	aTest.MustBeEqual(client.do("get a\r\n", 3), "VALUE a 5 3|abc|END")
+	aTest.MustBeEqual(client.do("get b\r\n", 1), "END")
+	aTest.MustBeEqual(client.do("set b 0 0 0\r\n\r\n", 1), "STORED")
+	aTest.MustBeEqual(client.do("get a b c\r\n", 5), "VALUE a 5 3|abc|VALUE b 0 0||END")
+
+	// Test #2. CAS is changed by every write.
+	cas1 := client.do("gets a\r\n", 3)
+	aTest.MustBeEqual(client.do("set a 5 0 3\r\nabc\r\n", 1), "STORED")
+	cas2 := client.do("gets a\r\n", 3)
+	aTest.MustBeEqual(strings.HasPrefix(cas1, "VALUE a 5 3 "), true)
+	aTest.MustBeDifferent(cas1, cas2)
+
+	// Test #3. Add and replace.
+	aTest.MustBeEqual(client.do("add a 0 0 1\r\nx\r\n", 1), "NOT_STORED")
+	aTest.MustBeEqual(client.do("add c 0 0 1\r\nx\r\n", 1), "STORED")
+	aTest.MustBeEqual(client.do("replace d 0 0 1\r\ny\r\n", 1), "NOT_STORED")
+	aTest.MustBeEqual(client.do("replace c 0 0 1\r\ny\r\n", 1), "STORED")
+	aTest.MustBeEqual(client.do("get c\r\n", 3), "VALUE c 0 1|y|END")
+
+	// Test #4. Delete.
+	aTest.MustBeEqual(client.do("delete c\r\n", 1), "DELETED")
+	aTest.MustBeEqual(client.do("delete c\r\n", 1), "NOT_FOUND")
+
+	// Test #5. No reply.
+	client.send("set d 0 0 1 noreply\r\nz\r\ndelete a noreply\r\n")
+	aTest.MustBeEqual(client.do("get a d\r\n", 3), "VALUE d 0 1|z|END")
+
+	// Test #6. Flush.
+	aTest.MustBeEqual(client.do("flush_all\r\n", 1), "OK")
+	aTest.MustBeEqual(client.do("get b d\r\n", 1), "END")
+	aTest.MustBeEqual(client.do("flush_all 10\r\n", 1), "CLIENT_ERROR "+ErrDelayedFlushIsNotAllowed)
+	client.send("flush_all 0 noreply\r\n")
+	aTest.MustBeEqual(client.do("version\r\n", 1), "VERSION "+Version)
+}
+
+func Test_connection_existenceChecks(t *testing.T) {
+	aTest := tester.New(t)
+
+	s, client, _ := _test_start(t, vl.Config[string, []byte]{}, Settings{})
+	aTest.MustBeEqual(client.do("set a 0 0 1\r\nx\r\n", 1), "STORED")
+	aTest.MustBeEqual(client.do("set b 0 0 1\r\nx\r\n", 1), "STORED")
+
+	// Test. Failed add and replace, touch and delete do not read records.
+	aTest.MustBeEqual(client.do("add a 0 0 1\r\ny\r\n", 1), "NOT_STORED")
+	aTest.MustBeEqual(client.do("replace c 0 0 1\r\ny\r\n", 1), "NOT_STORED")
+	aTest.MustBeEqual(client.do("delete c\r\n", 1), "NOT_FOUND")
+	aTest.MustBeEqual(client.do("touch c 10\r\n", 1), "NOT_FOUND")
+	aTest.MustBeEqual(s.cache.PeekTopUids(2), []string{"b", "a"})
+	stats := s.cache.GetStats()
+	aTest.MustBeEqual(stats.Hits, uint64(0))
+	aTest.MustBeEqual(stats.Misses, uint64(0))
+}
+
+func Test_connection_expiration(t *testing.T) {
+	aTest := tester.New(t)
+
+	_, client, clock := _test_start(t, vl.Config[string, []byte]{RecordTtl: 100}, Settings{})
+
+	// Test #1. Relative expiration time.
+	aTest.MustBeEqual(client.do("set a 0 10 1\r\nx\r\n", 1), "STORED")
+	clock.Add(time.Second * 9)
+	aTest.MustBeEqual(client.do("get a\r\n", 3), "VALUE a 0 1|x|END")
+	clock.Add(time.Second)
+	aTest.MustBeEqual(client.do("get a\r\n", 1), "END")
+
+	// Test #2. Absolute expiration time.
+	aTest.MustBeEqual(client.do("set a 0 1700000015 1\r\nx\r\n", 1), "STORED")
+	aTest.MustBeEqual(client.do("get a\r\n", 3), "VALUE a 0 1|x|END")
+	clock.Add(time.Second * 5)
+	aTest.MustBeEqual(client.do("get a\r\n", 1), "END")
+
+	// Test #3. Negative expiration time.
+	aTest.MustBeEqual(client.do("set a 0 0 1\r\nx\r\n", 1), "STORED")
+	aTest.MustBeEqual(client.do("set a 0 -1 1\r\ny\r\n", 1), "STORED")
+	aTest.MustBeEqual(client.do("get a\r\n", 1), "END")
+
+	// Test #4. Touch.
+	aTest.MustBeEqual(client.do("set a 0 10 1\r\nx\r\n", 1), "STORED")
+	clock.Add(time.Second * 9)
+	aTest.MustBeEqual(client.do("touch a 10\r\n", 1), "TOUCHED")
+	clock.Add(time.Second * 9)
+	aTest.MustBeEqual(client.do("get a\r\n", 3), "VALUE a 0 1|x|END")
+	aTest.MustBeEqual(client.do("touch b 10\r\n", 1), "NOT_FOUND")
+	aTest.MustBeEqual(client.do("touch a -1\r\n", 1), "TOUCHED")
+	aTest.MustBeEqual(client.do("get a\r\n", 1), "END")
+
+	// Test #5. TTL of the cache.
+	aTest.MustBeEqual(client.do("set a 0 0 1\r\nx\r\n", 1), "STORED")
+	clock.Add(time.Second * 100)
+	aTest.MustBeEqual(client.do("get a\r\n", 1), "END")
+}
+
+func Test_connection_limits(t *testing.T) {
+	aTest := tester.New(t)
+
+	_, client, _ := _test_start(t, vl.Config[string, []byte]{VolumeLimit: 100}, Settings{MaxValueSize: 200})
+
+	// Test #1. Value exceeds the volume limit of the cache.
+	value := strings.Repeat("x", 150)
+	aTest.MustBeEqual(client.do("set a 0 0 150\r\n"+value+"\r\n", 1), "SERVER_ERROR "+ErrObjectIsTooLarge)
+
+	// Test #2. Value exceeds the size limit of the server.
+	value = strings.Repeat("x", 250)
+	aTest.MustBeEqual(client.do("set a 0 0 250\r\n"+value+"\r\n", 1), "SERVER_ERROR "+ErrObjectIsTooLarge)
+	aTest.MustBeEqual(client.do("set a 0 0 1\r\nx\r\n", 1), "STORED")
+
+	// Test #3. Eviction.
+	value = strings.Repeat("x", 50)
+	aTest.MustBeEqual(client.do("set b 0 0 50\r\n"+value+"\r\n", 1), "STORED")
+	aTest.MustBeEqual(client.do("set c 0 0 50\r\n"+value+"\r\n", 1), "STORED")
+	aTest.MustBeEqual(client.do("get a\r\n", 1), "END")
+}
+
+func Test_connection_errors(t *testing.T) {
+	aTest := tester.New(t)
+
+	_, client, _ := _test_start(t, vl.Config[string, []byte]{}, Settings{})
+
+	// Test #1. Unknown and malformed commands.
+	aTest.MustBeEqual(client.do("foo\r\n", 1), "ERROR")
+	aTest.MustBeEqual(client.do("\r\n", 1), "ERROR")
+	aTest.MustBeEqual(client.do("get\r\n", 1), "ERROR")
+	aTest.MustBeEqual(client.do("set a 0 0\r\n", 1), "ERROR")
+	aTest.MustBeEqual(client.do("set a 0 0 x\r\n", 1), "CLIENT_ERROR "+ErrCommandLineFormatIsBad)
+	aTest.MustBeEqual(client.do("get "+strings.Repeat("k", 251)+"\r\n", 1), "CLIENT_ERROR "+ErrCommandLineFormatIsBad)
+	aTest.MustBeEqual(client.do("stats items\r\n", 1), "ERROR")
+
+	// Test #2. Value of a command with bad flags or a bad key is skipped.
+	aTest.MustBeEqual(client.do("set a x 0 1\r\nx\r\n", 1), "CLIENT_ERROR "+ErrCommandLineFormatIsBad)
+	aTest.MustBeEqual(client.do("set "+strings.Repeat("k", 251)+" 0 0 3\r\nget\r\n", 1), "CLIENT_ERROR "+ErrCommandLineFormatIsBad)
+	aTest.MustBeEqual(client.do("get a\r\n", 1), "END")
+
+	// Test #3. Bad data chunk.
+	aTest.MustBeEqual(client.do("set a 0 0 1\r\nxyz\r\n", 2), "CLIENT_ERROR "+ErrDataChunkIsBad+"|ERROR")
+
+	// Test #4. Line is too long.
+	client.send(strings.Repeat("x", maxLineLength+1) + "\r\n")
+	aTest.MustBeEqual(client.readLines(1), "CLIENT_ERROR "+ErrLineIsTooLong)
+	aTest.MustBeEqual(client.isClosed(), true)
+}
+
+func Test_connection_stats(t *testing.T) {
+	aTest := tester.New(t)
+
+	_, client, _ := _test_start(t, vl.Config[string, []byte]{VolumeLimit: 1000}, Settings{})
+	aTest.MustBeEqual(client.do("set a 0 0 3\r\nabc\r\n", 1), "STORED")
+	aTest.MustBeEqual(client.do("get a b\r\n", 3), "VALUE a 0 3|abc|END")
+
+	// Test.
+	client.send("stats\r\n")
+	stats := map[string]string{}
+	for {
+		line := client.readLines(1)
+		if line == "END" {
+			break
+		}
+		fields := strings.Fields(line)
+		aTest.MustBeEqual(fields[0], "STAT")
+		stats[fields[1]] = fields[2]
+	}
+	aTest.MustBeEqual(stats["cmd_get"], "2")
+	aTest.MustBeEqual(stats["cmd_set"], "1")
+	aTest.MustBeEqual(stats["get_hits"], "1")
+	aTest.MustBeEqual(stats["get_misses"], "1")
+	aTest.MustBeEqual(stats["curr_items"], "1")
+	aTest.MustBeEqual(stats["curr_connections"], "1")
+	aTest.MustBeEqual(stats["bytes"], "23")
+	aTest.MustBeEqual(stats["limit_maxbytes"], "1000")
+	aTest.MustBeEqual(stats["time"], "1700000000")
+}
+
+func Test_connection_quit(t *testing.T) {
+	aTest := tester.New(t)
+
+	_, client, _ := _test_start(t, vl.Config[string, []byte]{}, Settings{})
+
+	// Test.
+	client.send("quit\r\n")
+	aTest.MustBeEqual(client.isClosed(), true)
+}
+
+func Test_connection_slowClient(t *testing.T) {
+	aTest := tester.New(t)
+
+	s, client, _ := _test_start(t, vl.Config[string, []byte]{}, Settings{WriteTimeout: time.Millisecond * 100})
+	value := strings.Repeat("v", 512*1024)
+	aTest.MustBeEqual(client.do("set a 0 0 "+strconv.Itoa(len(value))+"\r\n"+value+"\r\n", 1), respStored)
+
+	// Test. A client which does not read its responses is disconnected.
+	client.send(strings.Repeat("get a\r\n", 100))
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		s.connLock.Lock()
+		connsCount := len(s.conns)
+		s.connLock.Unlock()
+
+		if connsCount == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("client is not disconnected")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+}
+
+func Test_isKeyValid(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test.
+	aTest.MustBeEqual(isKeyValid("a"), true)
+	aTest.MustBeEqual(isKeyValid(strings.Repeat("k", maxKeyLength)), true)
+	aTest.MustBeEqual(isKeyValid(""), false)
+	aTest.MustBeEqual(isKeyValid(strings.Repeat("k", maxKeyLength+1)), false)
+	aTest.MustBeEqual(isKeyValid("a\x01b"), false)
+	aTest.MustBeEqual(isKeyValid("a\x7fb"), false)
+}
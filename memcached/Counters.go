@@ -0,0 +1,19 @@
+package memcached
+
+import "sync/atomic"
+
+// counters contains statistics of the server reported by the stats command.
+type counters struct {
+	connections      atomic.Uint64
+	totalConnections atomic.Uint64
+	cmdGet           atomic.Uint64
+	cmdSet           atomic.Uint64
+	cmdTouch         atomic.Uint64
+	cmdFlush         atomic.Uint64
+	getHits          atomic.Uint64
+	getMisses        atomic.Uint64
+	deleteHits       atomic.Uint64
+	deleteMisses     atomic.Uint64
+	touchHits        atomic.Uint64
+	touchMisses      atomic.Uint64
+}
@@ -0,0 +1,79 @@
+package memcached
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/vault-thirteen/Cache/internal/itemcodec"
+)
+
+// item is a value stored in the cache with its protocol attributes. The
+// expiration time is stored by the item codec, and the attributes precede
+// the value in the data of the codec's item:
+//
+//	flags uint32, opaque flags of the client
+//	cas   uint64, unique version of the item
+//	value [...]byte
+type item struct {
+	flags     uint32
+	cas       uint64
+	expiresAt int64
+	value     []byte
+}
+
+const itemAttributesSize = 4 + 8
+
+func (it item) encode() (buf []byte) {
+	buf = make([]byte, 0, itemAttributesSize+len(it.value))
+	buf = binary.BigEndian.AppendUint32(buf, it.flags)
+	buf = binary.BigEndian.AppendUint64(buf, it.cas)
+	buf = append(buf, it.value...)
+
+	return itemcodec.Item{ExpiresAt: it.expiresAt, Data: buf}.Encode()
+}
+
+// decodeItem parses an item. The value of the item shares memory with the
+// buffer.
+func decodeItem(buf []byte) (it item, err error) {
+	var ci itemcodec.Item
+	ci, err = itemcodec.Decode(buf)
+	if err != nil {
+		return it, err
+	}
+	if len(ci.Data) < itemAttributesSize {
+		return it, errors.New(ErrItemIsCorrupted)
+	}
+
+	it = item{
+		flags:     binary.BigEndian.Uint32(ci.Data[0:4]),
+		cas:       binary.BigEndian.Uint64(ci.Data[4:12]),
+		expiresAt: ci.ExpiresAt,
+		value:     ci.Data[itemAttributesSize:],
+	}
+
+	return it, nil
+}
+
+func (it item) isAlive(now int64) bool {
+	return itemcodec.Item{ExpiresAt: it.expiresAt}.IsAlive(now)
+}
+
+// maxRelativeExpTime is the largest expiration time which is counted from
+// the current moment. Larger expiration times are Unix times.
+const maxRelativeExpTime = 60 * 60 * 24 * 30
+
+// expiresAt converts an expiration time of the protocol into a Unix time.
+// Zero means that the item does not expire by itself, and a negative
+// expiration time makes the item expired immediately.
+func expiresAt(expTime int64, now int64) int64 {
+	switch {
+	case expTime == 0:
+		return 0
+	case expTime < 0:
+		return now
+	case expTime <= maxRelativeExpTime:
+		return now + expTime
+	default:
+		return expTime
+	}
+}
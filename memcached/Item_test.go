@@ -0,0 +1,57 @@
+package memcached
+
+import (
+	"testing"
+
+	"github.com/vault-thirteen/Cache/internal/itemcodec"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_item_encode(t *testing.T) {
+	aTest := tester.New(t)
+	var it item
+	var err error
+
+	// Test #1. OK.
+	buf := item{flags: 1, cas: 2, expiresAt: 3, value: []byte("abc")}.encode()
+	aTest.MustBeEqual(len(buf), itemcodec.HeaderSize+itemAttributesSize+3)
+	it, err = decodeItem(buf)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(it, item{flags: 1, cas: 2, expiresAt: 3, value: []byte("abc")})
+
+	// Test #2. Empty value.
+	it, err = decodeItem(item{}.encode())
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(len(it.value), 0)
+
+	// Test #3. Corrupted item.
+	_, err = decodeItem([]byte("abc"))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), itemcodec.ErrItemIsCorrupted)
+
+	// Test #4. Item without attributes.
+	_, err = decodeItem(itemcodec.Item{Data: []byte("abc")}.Encode())
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrItemIsCorrupted)
+}
+
+func Test_item_isAlive(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test.
+	aTest.MustBeEqual(item{expiresAt: 0}.isAlive(100), true)
+	aTest.MustBeEqual(item{expiresAt: 101}.isAlive(100), true)
+	aTest.MustBeEqual(item{expiresAt: 100}.isAlive(100), false)
+}
+
+func Test_expiresAt(t *testing.T) {
+	aTest := tester.New(t)
+	const now = 1_000_000_000
+
+	// Test.
+	aTest.MustBeEqual(expiresAt(0, now), int64(0))
+	aTest.MustBeEqual(expiresAt(-1, now), int64(now))
+	aTest.MustBeEqual(expiresAt(10, now), int64(now+10))
+	aTest.MustBeEqual(expiresAt(maxRelativeExpTime, now), int64(now+maxRelativeExpTime))
+	aTest.MustBeEqual(expiresAt(maxRelativeExpTime+1, now), int64(maxRelativeExpTime+1))
+}
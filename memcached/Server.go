@@ -0,0 +1,305 @@
+// Package memcached serves a cache over a subset of the memcached text
+// protocol, so that services written in other languages may share the cache
+// using ordinary memcached clients. Supported commands are get, gets, set,
+// add, replace, delete, touch, flush_all, stats, version and quit.
+package memcached
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+// Server is a memcached server of a cache. Items are stored in the cache as
+// records with the UID equal to the key. Expiration times of items are
+// checked in addition to the TTL of the cache, so an item lives until its own
+// expiration time or until its record is removed by the cache, whichever is
+// earlier.
+type Server struct {
+	cache     *vl.Cache[string, []byte]
+	settings  Settings
+	startTime time.Time
+	counters  *counters
+	lastCas   *atomic.Uint64
+
+	// lock makes composite operations over items atomic, e.g. checking
+	// presence of an item and storing it.
+	lock *sync.Mutex
+
+	// connLock guards listeners and connections.
+	connLock  *sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	isClosed  bool
+	wg        *sync.WaitGroup
+}
+
+// NewServer creates a server of the cache.
+func NewServer(cache *vl.Cache[string, []byte], settings Settings) (s *Server, err error) {
+	if cache == nil {
+		return nil, errors.New(ErrCacheIsNil)
+	}
+
+	err = settings.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Server{
+		cache:     cache,
+		settings:  settings.withDefaults(),
+		counters:  new(counters),
+		lastCas:   new(atomic.Uint64),
+		lock:      new(sync.Mutex),
+		connLock:  new(sync.Mutex),
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+		wg:        new(sync.WaitGroup),
+	}
+	s.startTime = s.now()
+
+	return s, nil
+}
+
+func (s *Server) now() time.Time {
+	if s.settings.Clock == nil {
+		return time.Now()
+	}
+
+	return s.settings.Clock.Now()
+}
+
+// Serve accepts connections of the listener and serves them until the server
+// is closed. The listener is closed by the server.
+func (s *Server) Serve(l net.Listener) (err error) {
+	s.connLock.Lock()
+	if s.isClosed {
+		s.connLock.Unlock()
+		_ = l.Close()
+		return errors.New(ErrServerIsClosed)
+	}
+	s.listeners[l] = struct{}{}
+	s.connLock.Unlock()
+
+	for {
+		var conn net.Conn
+		conn, err = l.Accept()
+		if err != nil {
+			s.connLock.Lock()
+			isClosed := s.isClosed
+			s.connLock.Unlock()
+
+			if isClosed {
+				return errors.New(ErrServerIsClosed)
+			}
+
+			return err
+		}
+
+		s.connLock.Lock()
+		if s.isClosed {
+			s.connLock.Unlock()
+			_ = conn.Close()
+			return errors.New(ErrServerIsClosed)
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.connLock.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+// ListenAndServe listens on the TCP address and serves connections until the
+// server is closed.
+func (s *Server) ListenAndServe(address string) (err error) {
+	var l net.Listener
+	l, err = net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(l)
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.connLock.Lock()
+		delete(s.conns, conn)
+		s.connLock.Unlock()
+
+		_ = conn.Close()
+	}()
+
+	s.counters.connections.Add(1)
+	s.counters.totalConnections.Add(1)
+	defer s.counters.connections.Add(^uint64(0))
+
+	err := newConnection(s, conn).serve()
+	if err != nil {
+		s.reportError(err)
+	}
+}
+
+func (s *Server) reportError(err error) {
+	if s.settings.OnError != nil {
+		s.settings.OnError(err)
+	}
+}
+
+// Close stops listening, closes all the connections and waits for them to be
+// finished. The cache is not closed.
+func (s *Server) Close() (err error) {
+	s.connLock.Lock()
+	if s.isClosed {
+		s.connLock.Unlock()
+		return nil
+	}
+	s.isClosed = true
+
+	var errs []error
+	for l := range s.listeners {
+		errs = append(errs, l.Close())
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.connLock.Unlock()
+
+	s.wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// getItem returns an alive item. An expired item is removed.
+func (s *Server) getItem(key string) (it item, found bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.getItemUnsafe(key)
+}
+
+func (s *Server) getItemUnsafe(key string) (it item, found bool) {
+	return s.readItemUnsafe(key, s.cache.GetRecord)
+}
+
+// peekItemUnsafe is a variant of the getItemUnsafe method for checks of
+// existence. Like the RecordExists method of the cache, it does not move the
+// record, does not prolong its life and does not change statistics.
+func (s *Server) peekItemUnsafe(key string) (it item, found bool) {
+	return s.readItemUnsafe(key, s.cache.PeekRecord)
+}
+
+// readItemUnsafe reads an alive item using the read function of the cache.
+func (s *Server) readItemUnsafe(key string, read func(uid string) ([]byte, error)) (it item, found bool) {
+	buf, err := read(key)
+	if err != nil {
+		return it, false
+	}
+
+	it, err = decodeItem(buf)
+	if err != nil {
+		s.cache.RemoveRecord(key)
+		return it, false
+	}
+
+	if !it.isAlive(s.now().Unix()) {
+		s.cache.RemoveRecord(key)
+		return it, false
+	}
+
+	return it, true
+}
+
+// storeMode is a condition of storing an item.
+type storeMode byte
+
+const (
+	storeModeSet = storeMode(iota)
+	storeModeAdd
+	storeModeReplace
+)
+
+// storeItem stores the item according to the mode. The expiration time is
+// given in terms of the protocol. An item which is expired immediately is
+// not stored, but an existing item with its key is removed.
+func (s *Server) storeItem(mode storeMode, key string, flags uint32, expTime int64, value []byte) (isStored bool, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if mode != storeModeSet {
+		_, found := s.peekItemUnsafe(key)
+		if (mode == storeModeAdd) && found {
+			return false, nil
+		}
+		if (mode == storeModeReplace) && !found {
+			return false, nil
+		}
+	}
+
+	now := s.now().Unix()
+	it := item{
+		flags:     flags,
+		cas:       s.lastCas.Add(1),
+		expiresAt: expiresAt(expTime, now),
+		value:     value,
+	}
+
+	if !it.isAlive(now) {
+		s.cache.RemoveRecord(key)
+		return true, nil
+	}
+
+	err = s.cache.AddRecord(key, it.encode())
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// touchItem changes the expiration time of an item.
+func (s *Server) touchItem(key string, expTime int64) (found bool, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var it item
+	it, found = s.peekItemUnsafe(key)
+	if !found {
+		return false, nil
+	}
+
+	now := s.now().Unix()
+	it.expiresAt = expiresAt(expTime, now)
+	if !it.isAlive(now) {
+		s.cache.RemoveRecord(key)
+		return true, nil
+	}
+
+	return true, s.cache.AddRecord(key, it.encode())
+}
+
+// deleteItem removes an alive item.
+func (s *Server) deleteItem(key string) (found bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, found = s.peekItemUnsafe(key)
+	if found {
+		s.cache.RemoveRecord(key)
+	}
+
+	return found
+}
+
+func (s *Server) flushAll() (err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.cache.Clear()
+}
@@ -0,0 +1,51 @@
+package memcached
+
+import (
+	"net"
+	"testing"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_NewServer(t *testing.T) {
+	aTest := tester.New(t)
+	var s *Server
+	var err error
+
+	// Test #1. Bad arguments.
+	_, err = NewServer(nil, Settings{})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrCacheIsNil)
+	_, err = NewServer(vl.NewCache[string, []byte](0, 0, 60), Settings{MaxValueSize: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrMaxValueSizeIsNegative)
+	_, err = NewServer(vl.NewCache[string, []byte](0, 0, 60), Settings{WriteTimeout: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrWriteTimeoutIsNegative)
+
+	// Test #2. OK.
+	s, err = NewServer(vl.NewCache[string, []byte](0, 0, 60), Settings{})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(s.settings.MaxValueSize, DefaultMaxValueSize)
+	aTest.MustBeEqual(s.settings.WriteTimeout, DefaultWriteTimeout)
+}
+
+func Test_Server_Close(t *testing.T) {
+	aTest := tester.New(t)
+
+	s, client, _ := _test_start(t, vl.Config[string, []byte]{}, Settings{})
+	aTest.MustBeEqual(client.do("version\r\n", 1), "VERSION "+Version)
+
+	// Test #1. Connections are closed.
+	aTest.MustBeNoError(s.Close())
+	aTest.MustBeEqual(client.isClosed(), true)
+	aTest.MustBeNoError(s.Close())
+
+	// Test #2. Closed server does not serve.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	aTest.MustBeNoError(err)
+	err = s.Serve(l)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrServerIsClosed)
+}
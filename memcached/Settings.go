@@ -0,0 +1,61 @@
+package memcached
+
+import (
+	"errors"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+// DefaultMaxValueSize is the default maximum size of a value, it equals the
+// default item size limit of memcached.
+const DefaultMaxValueSize = 1024 * 1024
+
+// DefaultWriteTimeout is the default timeout of writing responses to a
+// client.
+const DefaultWriteTimeout = time.Second * 5
+
+// Settings contains settings of the server.
+type Settings struct {
+	// MaxValueSize is a maximum size of a value in bytes. Larger values are
+	// rejected without reading them into memory. Zero selects the default
+	// size.
+	MaxValueSize int
+
+	// WriteTimeout is a timeout of writing responses of a command to a
+	// client. A client which does not read them in time is disconnected.
+	// Zero selects the default timeout.
+	WriteTimeout time.Duration
+
+	// Clock is an optional source of current time used for expiration
+	// times of items. It should be the clock of the cache.
+	Clock vl.Clock
+
+	// OnError is an optional callback which receives errors of connections,
+	// e.g. network errors.
+	OnError func(err error)
+}
+
+func (s Settings) validate() (err error) {
+	if s.MaxValueSize < 0 {
+		return errors.New(ErrMaxValueSizeIsNegative)
+	}
+	if s.WriteTimeout < 0 {
+		return errors.New(ErrWriteTimeoutIsNegative)
+	}
+
+	return nil
+}
+
+// withDefaults returns the settings where zero values are replaced by
+// default values.
+func (s Settings) withDefaults() Settings {
+	if s.MaxValueSize == 0 {
+		s.MaxValueSize = DefaultMaxValueSize
+	}
+	if s.WriteTimeout == 0 {
+		s.WriteTimeout = DefaultWriteTimeout
+	}
+
+	return s
+}
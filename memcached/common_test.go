@@ -0,0 +1,98 @@
+package memcached
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/cachetest"
+)
+
+// _test_client is a minimal client of the text protocol.
+type _test_client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// _test_start starts a server of a new cache on a free local port and
+// connects a client to it.
+func _test_start(t *testing.T, cfg vl.Config[string, []byte], settings Settings) (s *Server, client *_test_client, clock *cachetest.Clock) {
+	clock = cachetest.NewClock(time.Unix(1_700_000_000, 0))
+	cfg.Clock = clock
+	settings.Clock = clock
+	if cfg.RecordTtl == 0 {
+		cfg.RecordTtl = 3600
+	}
+
+	c, err := vl.NewFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = NewServer(c, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = s.Serve(l) }()
+	t.Cleanup(func() { _ = s.Close() })
+
+	return s, _test_dial(t, l.Addr().String()), clock
+}
+
+func _test_dial(t *testing.T, address string) (client *_test_client) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return &_test_client{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+// send sends raw text to the server.
+func (c *_test_client) send(text string) {
+	_, err := c.conn.Write([]byte(text))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// readLines reads the number of lines of the response and joins them with
+// the '|' character.
+func (c *_test_client) readLines(n int) (response string) {
+	_ = c.conn.SetReadDeadline(time.Now().Add(time.Second * 5))
+
+	var lines []string
+	for i := 0; i < n; i++ {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\r\n"))
+	}
+
+	return strings.Join(lines, "|")
+}
+
+// do sends the command and reads the number of lines of the response.
+func (c *_test_client) do(command string, n int) (response string) {
+	c.send(command)
+	return c.readLines(n)
+}
+
+// isClosed checks whether the server has closed the connection.
+func (c *_test_client) isClosed() bool {
+	_ = c.conn.SetReadDeadline(time.Now().Add(time.Second * 5))
+	_, err := c.r.ReadByte()
+	return err == io.EOF
+}
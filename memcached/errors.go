@@ -0,0 +1,14 @@
+package memcached
+
+const (
+	ErrCacheIsNil               = "cache is nil"
+	ErrMaxValueSizeIsNegative   = "max value size is negative"
+	ErrWriteTimeoutIsNegative   = "write timeout is negative"
+	ErrServerIsClosed           = "server is closed"
+	ErrItemIsCorrupted          = "item is corrupted"
+	ErrLineIsTooLong            = "line is too long"
+	ErrCommandLineFormatIsBad   = "bad command line format"
+	ErrDataChunkIsBad           = "bad data chunk"
+	ErrObjectIsTooLarge         = "object too large for cache"
+	ErrDelayedFlushIsNotAllowed = "delayed flush is not supported"
+)
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	nvl "github.com/vault-thirteen/Cache/NVL"
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+// Collector collects metrics of a single cache.
+type Collector interface {
+	Collect() (m Metrics)
+}
+
+// CollectorFunc is an adapter which allows to use an ordinary function as a
+// collector.
+type CollectorFunc func() (m Metrics)
+
+// Collect calls the function.
+func (f CollectorFunc) Collect() (m Metrics) {
+	return f()
+}
+
+// FromVL creates a collector of a cache with volume calculation.
+func FromVL[U vl.UidType, D vl.DataType](cache *vl.Cache[U, D]) (c Collector) {
+	return CollectorFunc(func() (m Metrics) {
+		m.Size, m.SizeLimit = cache.GetSize()
+		m.Volume, m.VolumeLimit = cache.GetVolume()
+		m.HasVolume = true
+
+		stats := cache.GetStats()
+		m.Hits = stats.Hits
+		m.Misses = stats.Misses
+		m.ExpiredOnRead = stats.ExpiredOnRead
+		m.StaleHits = stats.StaleHits
+		m.Evictions = make(map[string]uint64, len(stats.Evictions))
+		for reason, count := range stats.Evictions {
+			m.Evictions[string(reason)] = count
+		}
+
+		return m
+	})
+}
+
+// FromNVL creates a collector of a cache without volume calculation.
+func FromNVL[U nvl.UidType, D nvl.DataType](cache *nvl.Cache[U, D]) (c Collector) {
+	return CollectorFunc(func() (m Metrics) {
+		m.Size, m.SizeLimit = cache.GetSize()
+
+		stats := cache.GetStats()
+		m.Hits = stats.Hits
+		m.Misses = stats.Misses
+		m.ExpiredOnRead = stats.ExpiredOnRead
+		m.StaleHits = stats.StaleHits
+		m.Evictions = make(map[string]uint64, len(stats.Evictions))
+		for reason, count := range stats.Evictions {
+			m.Evictions[string(reason)] = count
+		}
+
+		return m
+	})
+}
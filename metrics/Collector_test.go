@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"testing"
+
+	nvl "github.com/vault-thirteen/Cache/NVL"
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_FromVL(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	cache := vl.NewCache[string, string](2, 100, 60)
+	err = cache.AddRecord("A", "1")
+	aTest.MustBeNoError(err)
+	err = cache.AddRecord("B", "22")
+	aTest.MustBeNoError(err)
+	err = cache.AddRecord("C", "333") // A is evicted.
+	aTest.MustBeNoError(err)
+	_, err = cache.GetRecord("C")
+	aTest.MustBeNoError(err)
+	_, err = cache.GetRecord("A")
+	aTest.MustBeAnError(err)
+
+	// Test.
+	m := FromVL(cache).Collect()
+	aTest.MustBeEqual(m.Size, 2)
+	aTest.MustBeEqual(m.SizeLimit, 2)
+	aTest.MustBeEqual(m.Volume, 5)
+	aTest.MustBeEqual(m.VolumeLimit, 100)
+	aTest.MustBeEqual(m.HasVolume, true)
+	aTest.MustBeEqual(m.Hits, uint64(1))
+	aTest.MustBeEqual(m.Misses, uint64(1))
+	aTest.MustBeEqual(m.ExpiredOnRead, uint64(0))
+	aTest.MustBeEqual(m.Evictions, map[string]uint64{"size": 1})
+}
+
+func Test_FromNVL(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	cache := nvl.NewCache[string, int](1, 60)
+	err = cache.AddRecord("A", 1)
+	aTest.MustBeNoError(err)
+	err = cache.AddRecord("B", 2) // A is evicted.
+	aTest.MustBeNoError(err)
+	_, err = cache.GetRecord("B")
+	aTest.MustBeNoError(err)
+
+	// Test.
+	m := FromNVL(cache).Collect()
+	aTest.MustBeEqual(m.Size, 1)
+	aTest.MustBeEqual(m.SizeLimit, 1)
+	aTest.MustBeEqual(m.HasVolume, false)
+	aTest.MustBeEqual(m.Hits, uint64(1))
+	aTest.MustBeEqual(m.Misses, uint64(0))
+	aTest.MustBeEqual(m.Evictions, map[string]uint64{"size": 1})
+}
@@ -0,0 +1,214 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is a content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Exporter exports metrics of named caches in the Prometheus text exposition
+// format. It may be used as an HTTP handler for the scraping endpoint.
+type Exporter struct {
+	collectors map[string]Collector
+	lock       *sync.RWMutex
+}
+
+// NewExporter creates a new exporter.
+func NewExporter() (e *Exporter) {
+	return &Exporter{
+		collectors: make(map[string]Collector),
+		lock:       new(sync.RWMutex),
+	}
+}
+
+// Register registers a collector of a cache having the specified name.
+func (e *Exporter) Register(name string, c Collector) (err error) {
+	if len(name) == 0 {
+		return errors.New(ErrNameIsEmpty)
+	}
+	if c == nil {
+		return errors.New(ErrCollectorIsNil)
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	_, isUsed := e.collectors[name]
+	if isUsed {
+		return fmt.Errorf(ErrNameIsUsed, name)
+	}
+
+	e.collectors[name] = c
+
+	return nil
+}
+
+// Unregister removes a collector of a cache having the specified name.
+func (e *Exporter) Unregister(name string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	delete(e.collectors, name)
+}
+
+// WriteTo writes metrics of all the registered caches.
+func (e *Exporter) WriteTo(w io.Writer) (n int64, err error) {
+	names, metrics := e.collect()
+
+	buf := new(bytes.Buffer)
+	for _, f := range families {
+		f.write(buf, names, metrics)
+	}
+
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP writes metrics of all the registered caches into the response.
+func (e *Exporter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	rw.Header().Set("Content-Type", ContentType)
+	_, _ = e.WriteTo(rw)
+}
+
+// collect returns sorted names of caches and metrics of each cache.
+func (e *Exporter) collect() (names []string, metrics map[string]Metrics) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	names = make([]string, 0, len(e.collectors))
+	metrics = make(map[string]Metrics, len(e.collectors))
+	for name, c := range e.collectors {
+		names = append(names, name)
+		metrics[name] = c.Collect()
+	}
+	slices.Sort(names)
+
+	return names, metrics
+}
+
+// family is a family of metrics having the same name.
+type family struct {
+	name       string
+	help       string
+	metricType string
+	isVolume   bool
+	value      func(m Metrics) uint64
+	labelName  string
+	values     func(m Metrics) map[string]uint64
+}
+
+var families = []family{
+	{
+		name:       "cache_size",
+		help:       "Number of records in the cache.",
+		metricType: "gauge",
+		value:      func(m Metrics) uint64 { return uint64(m.Size) },
+	},
+	{
+		name:       "cache_size_limit",
+		help:       "Maximum number of records in the cache, zero means no limit.",
+		metricType: "gauge",
+		value:      func(m Metrics) uint64 { return uint64(m.SizeLimit) },
+	},
+	{
+		name:       "cache_volume_bytes",
+		help:       "Total volume of records in the cache.",
+		metricType: "gauge",
+		isVolume:   true,
+		value:      func(m Metrics) uint64 { return uint64(m.Volume) },
+	},
+	{
+		name:       "cache_volume_limit_bytes",
+		help:       "Maximum total volume of records in the cache, zero means no limit.",
+		metricType: "gauge",
+		isVolume:   true,
+		value:      func(m Metrics) uint64 { return uint64(m.VolumeLimit) },
+	},
+	{
+		name:       "cache_hits_total",
+		help:       "Number of read requests which have found an alive record.",
+		metricType: "counter",
+		value:      func(m Metrics) uint64 { return m.Hits },
+	},
+	{
+		name:       "cache_misses_total",
+		help:       "Number of read requests which have not found an alive record.",
+		metricType: "counter",
+		value:      func(m Metrics) uint64 { return m.Misses },
+	},
+	{
+		name:       "cache_expired_on_read_total",
+		help:       "Number of read requests which have found an outdated record.",
+		metricType: "counter",
+		value:      func(m Metrics) uint64 { return m.ExpiredOnRead },
+	},
+	{
+		name:       "cache_stale_hits_total",
+		help:       "Number of read requests which have been served with stale data.",
+		metricType: "counter",
+		value:      func(m Metrics) uint64 { return m.StaleHits },
+	},
+	{
+		name:       "cache_evictions_total",
+		help:       "Number of records removed by the cache itself.",
+		metricType: "counter",
+		labelName:  "reason",
+		values:     func(m Metrics) map[string]uint64 { return m.Evictions },
+	},
+}
+
+func (f family) write(buf *bytes.Buffer, names []string, metrics map[string]Metrics) {
+	headerIsWritten := false
+	writeHeader := func() {
+		if headerIsWritten {
+			return
+		}
+		buf.WriteString("# HELP " + f.name + " " + f.help + "\n")
+		buf.WriteString("# TYPE " + f.name + " " + f.metricType + "\n")
+		headerIsWritten = true
+	}
+
+	for _, name := range names {
+		m := metrics[name]
+		if f.isVolume && !m.HasVolume {
+			continue
+		}
+
+		if f.values == nil {
+			writeHeader()
+			writeSample(buf, f.name, `cache="`+escapeLabelValue(name)+`"`, f.value(m))
+			continue
+		}
+
+		values := f.values(m)
+		labelValues := make([]string, 0, len(values))
+		for lv := range values {
+			labelValues = append(labelValues, lv)
+		}
+		slices.Sort(labelValues)
+
+		for _, lv := range labelValues {
+			writeHeader()
+			labels := `cache="` + escapeLabelValue(name) + `",` + f.labelName + `="` + escapeLabelValue(lv) + `"`
+			writeSample(buf, f.name, labels, values[lv])
+		}
+	}
+}
+
+func writeSample(buf *bytes.Buffer, name string, labels string, value uint64) {
+	buf.WriteString(name + "{" + labels + "} " + strconv.FormatUint(value, 10) + "\n")
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func _test_collector(m Metrics) Collector {
+	return CollectorFunc(func() Metrics { return m })
+}
+
+func Test_Register(t *testing.T) {
+	aTest := tester.New(t)
+	var e = NewExporter()
+	var err error
+
+	// Test #1. Empty name.
+	err = e.Register("", _test_collector(Metrics{}))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrNameIsEmpty)
+
+	// Test #2. No collector.
+	err = e.Register("a", nil)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrCollectorIsNil)
+
+	// Test #3. OK.
+	err = e.Register("a", _test_collector(Metrics{}))
+	aTest.MustBeNoError(err)
+
+	// Test #4. Name is used.
+	err = e.Register("a", _test_collector(Metrics{}))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrNameIsUsed, "a"))
+
+	// Test #5. Name is released.
+	e.Unregister("a")
+	err = e.Register("a", _test_collector(Metrics{}))
+	aTest.MustBeNoError(err)
+}
+
+func Test_WriteTo(t *testing.T) {
+	aTest := tester.New(t)
+	var e = NewExporter()
+	var err error
+
+	err = e.Register("users", _test_collector(Metrics{
+		Size:          3,
+		SizeLimit:     10,
+		Volume:        100,
+		VolumeLimit:   1000,
+		HasVolume:     true,
+		Hits:          5,
+		Misses:        2,
+		ExpiredOnRead: 1,
+		StaleHits:     6,
+		Evictions:     map[string]uint64{"volume": 4, "size": 7},
+	}))
+	aTest.MustBeNoError(err)
+	err = e.Register(`ob"jects`, _test_collector(Metrics{
+		Size:      1,
+		SizeLimit: 2,
+		Hits:      3,
+		Misses:    4,
+		Evictions: map[string]uint64{},
+	}))
+	aTest.MustBeNoError(err)
+
+	// Test.
+	buf := new(bytes.Buffer)
+	_, err = e.WriteTo(buf)
+	aTest.MustBeNoError(err)
+	expected := `# HELP cache_size Number of records in the cache.
+# TYPE cache_size gauge
+cache_size{cache="ob\"jects"} 1
+cache_size{cache="users"} 3
+# HELP cache_size_limit Maximum number of records in the cache, zero means no limit.
+# TYPE cache_size_limit gauge
+cache_size_limit{cache="ob\"jects"} 2
+cache_size_limit{cache="users"} 10
+# HELP cache_volume_bytes Total volume of records in the cache.
+# TYPE cache_volume_bytes gauge
+cache_volume_bytes{cache="users"} 100
+# HELP cache_volume_limit_bytes Maximum total volume of records in the cache, zero means no limit.
+# TYPE cache_volume_limit_bytes gauge
+cache_volume_limit_bytes{cache="users"} 1000
+# HELP cache_hits_total Number of read requests which have found an alive record.
+# TYPE cache_hits_total counter
+cache_hits_total{cache="ob\"jects"} 3
+cache_hits_total{cache="users"} 5
+# HELP cache_misses_total Number of read requests which have not found an alive record.
+# TYPE cache_misses_total counter
+cache_misses_total{cache="ob\"jects"} 4
+cache_misses_total{cache="users"} 2
+# HELP cache_expired_on_read_total Number of read requests which have found an outdated record.
+# TYPE cache_expired_on_read_total counter
+cache_expired_on_read_total{cache="ob\"jects"} 0
+cache_expired_on_read_total{cache="users"} 1
+# HELP cache_stale_hits_total Number of read requests which have been served with stale data.
+# TYPE cache_stale_hits_total counter
+cache_stale_hits_total{cache="ob\"jects"} 0
+cache_stale_hits_total{cache="users"} 6
+# HELP cache_evictions_total Number of records removed by the cache itself.
+# TYPE cache_evictions_total counter
+cache_evictions_total{cache="users",reason="size"} 7
+cache_evictions_total{cache="users",reason="volume"} 4
+`
+	aTest.MustBeEqual(buf.String(), expected)
+}
+
+func Test_ServeHTTP(t *testing.T) {
+	aTest := tester.New(t)
+	var e = NewExporter()
+	var err error
+
+	err = e.Register("a", _test_collector(Metrics{Size: 1}))
+	aTest.MustBeNoError(err)
+
+	// Test.
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	aTest.MustBeEqual(rec.Code, http.StatusOK)
+	aTest.MustBeEqual(rec.Header().Get("Content-Type"), ContentType)
+	aTest.MustBeEqual(bytes.Contains(rec.Body.Bytes(), []byte(`cache_size{cache="a"} 1`+"\n")), true)
+}
+
+func Test_escapeLabelValue(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test.
+	aTest.MustBeEqual(escapeLabelValue("a\\b\"c\nd"), `a\\b\"c\nd`)
+}
@@ -0,0 +1,20 @@
+package metrics
+
+// Metrics is a set of values collected from a cache at some moment.
+type Metrics struct {
+	Size        int
+	SizeLimit   int
+	Volume      int
+	VolumeLimit int
+
+	// HasVolume is set when the cache measures volume of its records.
+	HasVolume bool
+
+	Hits          uint64
+	Misses        uint64
+	ExpiredOnRead uint64
+	StaleHits     uint64
+
+	// Evictions is a number of evicted records grouped by the reason.
+	Evictions map[string]uint64
+}
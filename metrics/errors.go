@@ -0,0 +1,7 @@
+package metrics
+
+const (
+	ErrNameIsEmpty    = "name is empty"
+	ErrNameIsUsed     = `name is already used: %v`
+	ErrCollectorIsNil = "collector is nil"
+)
@@ -0,0 +1,37 @@
+package otelhooks
+
+import (
+	"context"
+
+	nvl "github.com/vault-thirteen/Cache/NVL"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NVL is an instrumentation of a cache without volume calculation.
+type NVL[U nvl.UidType] struct {
+	hooks hooks
+}
+
+// NewNVL creates an instrumentation of a cache without volume calculation.
+// Each operation is recorded as an event of the span found in the operation's
+// context. If the tracer is set, each operation also gets its own child span
+// which holds the event.
+func NewNVL[U nvl.UidType](cacheName string, tracer trace.Tracer) (i *NVL[U]) {
+	return &NVL[U]{
+		hooks: hooks{
+			cacheName: cacheName,
+			tracer:    tracer,
+		},
+	}
+}
+
+// StartOperation starts a span if the tracer is set.
+func (i *NVL[U]) StartOperation(ctx context.Context, op nvl.Operation, uid U) (ctxOut context.Context) {
+	return i.hooks.start(ctx, string(op), uid)
+}
+
+// EndOperation adds an event to the current span and ends the span if it has
+// been started by StartOperation.
+func (i *NVL[U]) EndOperation(ctx context.Context, op nvl.Operation, uid U, result nvl.OperationResult) {
+	i.hooks.end(ctx, string(op), uid, string(result.Outcome), nil, result.Err)
+}
@@ -0,0 +1,40 @@
+package otelhooks
+
+import (
+	"context"
+	"testing"
+
+	nvl "github.com/vault-thirteen/Cache/NVL"
+	"github.com/vault-thirteen/auxie/tester"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_NVL(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	cache := nvl.NewCache[int, string](0, 60)
+	cache.SetInstrumentation(NewNVL[int]("objects", nil))
+
+	// Test.
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	err = cache.AddRecordCtx(ctx, 1, "x")
+	aTest.MustBeNoError(err)
+	_, err = cache.GetRecordCtx(ctx, 1)
+	aTest.MustBeNoError(err)
+	parent.End()
+
+	spans := sr.Ended()
+	aTest.MustBeEqual(len(spans), 1)
+	events := spans[0].Events()
+	aTest.MustBeEqual(len(events), 2)
+	aTest.MustBeEqual(_test_attributes(events[0].Attributes), map[string]string{
+		AttributeCacheName: "objects",
+		AttributeUid:       "1",
+		AttributeOutcome:   "added",
+	})
+	aTest.MustBeEqual(_test_attributes(events[1].Attributes)[AttributeOutcome], "hit")
+}
@@ -0,0 +1,37 @@
+package otelhooks
+
+import (
+	"context"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// VL is an instrumentation of a cache with volume calculation.
+type VL[U vl.UidType] struct {
+	hooks hooks
+}
+
+// NewVL creates an instrumentation of a cache with volume calculation. Each
+// operation is recorded as an event of the span found in the operation's
+// context. If the tracer is set, each operation also gets its own child span
+// which holds the event.
+func NewVL[U vl.UidType](cacheName string, tracer trace.Tracer) (i *VL[U]) {
+	return &VL[U]{
+		hooks: hooks{
+			cacheName: cacheName,
+			tracer:    tracer,
+		},
+	}
+}
+
+// StartOperation starts a span if the tracer is set.
+func (i *VL[U]) StartOperation(ctx context.Context, op vl.Operation, uid U) (ctxOut context.Context) {
+	return i.hooks.start(ctx, string(op), uid)
+}
+
+// EndOperation adds an event to the current span and ends the span if it has
+// been started by StartOperation.
+func (i *VL[U]) EndOperation(ctx context.Context, op vl.Operation, uid U, result vl.OperationResult) {
+	i.hooks.end(ctx, string(op), uid, string(result.Outcome), &result.VolumeDelta, result.Err)
+}
@@ -0,0 +1,101 @@
+package otelhooks
+
+import (
+	"context"
+	"testing"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func _test_attributes(attrs []attribute.KeyValue) (m map[string]string) {
+	m = make(map[string]string)
+	for _, a := range attrs {
+		m[string(a.Key)] = a.Value.Emit()
+	}
+	return m
+}
+
+func Test_VL_Events(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	cache := vl.NewCache[string, string](0, 0, 60)
+	cache.SetInstrumentation(NewVL[string]("users", nil))
+
+	// Test.
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	err = cache.AddRecordCtx(ctx, "A", "123")
+	aTest.MustBeNoError(err)
+	_, err = cache.GetRecordCtx(ctx, "A")
+	aTest.MustBeNoError(err)
+	_, err = cache.GetRecordCtx(ctx, "B")
+	aTest.MustBeAnError(err)
+	parent.End()
+
+	spans := sr.Ended()
+	aTest.MustBeEqual(len(spans), 1)
+	events := spans[0].Events()
+	aTest.MustBeEqual(len(events), 3)
+
+	aTest.MustBeEqual(events[0].Name, "cache.AddRecord")
+	aTest.MustBeEqual(_test_attributes(events[0].Attributes), map[string]string{
+		AttributeCacheName:   "users",
+		AttributeUid:         "A",
+		AttributeOutcome:     "added",
+		AttributeVolumeDelta: "3",
+	})
+
+	aTest.MustBeEqual(events[1].Name, "cache.GetRecord")
+	aTest.MustBeEqual(_test_attributes(events[1].Attributes)[AttributeOutcome], "hit")
+
+	aTest.MustBeEqual(events[2].Name, "cache.GetRecord")
+	aTest.MustBeEqual(_test_attributes(events[2].Attributes), map[string]string{
+		AttributeCacheName:   "users",
+		AttributeUid:         "B",
+		AttributeOutcome:     "miss",
+		AttributeVolumeDelta: "0",
+		AttributeError:       "record is not found, uid=B",
+	})
+}
+
+func Test_VL_Spans(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	cache := vl.NewCache[string, string](0, 2, 60)
+	cache.SetInstrumentation(NewVL[string]("users", tp.Tracer("cache")))
+
+	// Test.
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	err = cache.AddRecordCtx(ctx, "A", "123")
+	aTest.MustBeAnError(err)
+	err = cache.Clear()
+	aTest.MustBeNoError(err)
+	parent.End()
+
+	spans := sr.Ended()
+	aTest.MustBeEqual(len(spans), 3)
+
+	aTest.MustBeEqual(spans[0].Name(), "cache.AddRecord")
+	aTest.MustBeEqual(spans[0].Parent().SpanID(), parent.SpanContext().SpanID())
+	aTest.MustBeEqual(spans[0].Status().Code, codes.Error)
+	aTest.MustBeEqual(len(spans[0].Events()), 2) // Cache event & error.
+	aTest.MustBeEqual(spans[0].Events()[0].Name, "cache.AddRecord")
+	aTest.MustBeEqual(_test_attributes(spans[0].Attributes())[AttributeOutcome], "failed")
+
+	aTest.MustBeEqual(spans[1].Name(), "cache.Clear")
+	aTest.MustBeEqual(spans[1].Parent().IsValid(), false)
+	aTest.MustBeEqual(spans[1].Status().Code, codes.Unset)
+
+	aTest.MustBeEqual(spans[2].Name(), "parent")
+	aTest.MustBeEqual(len(spans[2].Events()), 0)
+}
@@ -0,0 +1,78 @@
+package otelhooks
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	AttributeCacheName   = "cache.name"
+	AttributeUid         = "cache.uid"
+	AttributeOutcome     = "cache.outcome"
+	AttributeVolumeDelta = "cache.volume_delta"
+	AttributeError       = "cache.error"
+
+	// EventPrefix is a prefix of names of span events and of spans.
+	EventPrefix = "cache."
+)
+
+// hooks is a part of the adapter which does not depend on the cache's
+// package.
+type hooks struct {
+	cacheName string
+	tracer    trace.Tracer
+}
+
+// startedSpanKey is a key of a context value holding the span started by the
+// adapter.
+type startedSpanKey struct{}
+
+func (h hooks) start(ctx context.Context, op string, uid any) (ctxOut context.Context) {
+	if h.tracer == nil {
+		return ctx
+	}
+
+	var span trace.Span
+	ctx, span = h.tracer.Start(ctx, EventPrefix+op,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			attribute.String(AttributeCacheName, h.cacheName),
+			attribute.String(AttributeUid, fmt.Sprint(uid)),
+		),
+	)
+
+	return context.WithValue(ctx, startedSpanKey{}, span)
+}
+
+func (h hooks) end(ctx context.Context, op string, uid any, outcome string, volumeDelta *int, err error) {
+	span := trace.SpanFromContext(ctx)
+
+	attrs := []attribute.KeyValue{
+		attribute.String(AttributeCacheName, h.cacheName),
+		attribute.String(AttributeUid, fmt.Sprint(uid)),
+		attribute.String(AttributeOutcome, outcome),
+	}
+	if volumeDelta != nil {
+		attrs = append(attrs, attribute.Int(AttributeVolumeDelta, *volumeDelta))
+	}
+	if err != nil {
+		attrs = append(attrs, attribute.String(AttributeError, err.Error()))
+	}
+	span.AddEvent(EventPrefix+op, trace.WithAttributes(attrs...))
+
+	startedSpan, isStartedHere := ctx.Value(startedSpanKey{}).(trace.Span)
+	if !isStartedHere || (startedSpan != span) {
+		return
+	}
+
+	span.SetAttributes(attribute.String(AttributeOutcome, outcome))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
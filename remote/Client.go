@@ -0,0 +1,259 @@
+// Package remote contains a client of a cache served by the RESP server of
+// the 'resp' package. The client has the method set of the cache.Cache
+// interface, so that code may switch between a local cache and a remote one.
+// Connections to the server are pooled, commands may be limited by a timeout,
+// and records read from the server may be kept in a small local near cache
+// which is invalidated by messages pushed by the server.
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/resp"
+)
+
+// Client is a client of a remote cache. Records are keys of a logical
+// database of the server, record UIDs are the keys. The TTL and the limits
+// of the cache are those of the server. The client is safe for concurrent
+// use.
+type Client struct {
+	settings Settings
+	pool     *pool
+
+	// near is the optional near cache, tracker keeps it coherent.
+	near    *vl.Cache[string, []byte]
+	tracker *tracker
+}
+
+// Dial creates a client of the server. The server is checked to be
+// reachable. Tracking of keys for the near cache is started in background,
+// records are read from the server until it is established.
+func Dial(settings Settings) (c *Client, err error) {
+	err = settings.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.PoolSize == 0 {
+		settings.PoolSize = DefaultPoolSize
+	}
+	if settings.ReconnectInterval == 0 {
+		settings.ReconnectInterval = DefaultReconnectInterval
+	}
+
+	c = &Client{
+		settings: settings,
+		pool:     newPool(settings),
+	}
+
+	if settings.NearCache != nil {
+		c.near, err = vl.NewFromConfig(*settings.NearCache)
+		if err != nil {
+			return nil, fmt.Errorf(ErrNearCacheIsNotValid, err)
+		}
+	}
+
+	_, err = c.do("PING")
+	if err != nil {
+		c.pool.close()
+		if c.near != nil {
+			_ = c.near.Close()
+		}
+		return nil, err
+	}
+
+	if c.near != nil {
+		c.tracker = newTracker(settings, c.near)
+	}
+
+	return c, nil
+}
+
+// do executes the command using a pooled connection. Connections broken by
+// network errors are not reused.
+func (c *Client) do(args ...string) (reply any, err error) {
+	var conn *resp.Client
+	conn, err = c.pool.get()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.settings.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(c.settings.Timeout))
+	}
+
+	reply, err = conn.Do(args...)
+
+	var replyErr resp.Error
+	isBroken := (err != nil) && !errors.As(err, &replyErr)
+	c.pool.put(conn, isBroken)
+
+	return reply, err
+}
+
+// RecordExists checks whether the specified record exists or not. Errors
+// are reported to the OnError callback, a record is considered absent then.
+func (c *Client) RecordExists(uid string) (recordExists bool) {
+	recordExists, err := c.CheckRecord(uid)
+	if err != nil {
+		c.reportError(err)
+		return false
+	}
+
+	return recordExists
+}
+
+// CheckRecord is a variant of the RecordExists method which returns an error
+// instead of reporting it.
+func (c *Client) CheckRecord(uid string) (recordExists bool, err error) {
+	if c.tracker != nil {
+		_, isActive := c.tracker.state()
+		if isActive && c.near.RecordExists(uid) {
+			return true, nil
+		}
+	}
+
+	var reply any
+	reply, err = c.do("EXISTS", uid)
+	if err != nil {
+		return false, err
+	}
+
+	return reply == int64(1), nil
+}
+
+// AddRecord adds a new record or updates an existing one.
+func (c *Client) AddRecord(uid string, data []byte) (err error) {
+	_, err = c.do("SET", uid, string(data))
+
+	// The record is removed from the near cache before the invalidation
+	// message comes, so that the client reads its own writes.
+	if c.tracker != nil {
+		c.tracker.invalidate(uid)
+	}
+
+	return err
+}
+
+// GetRecord reads a record. A record found in the near cache is not read
+// from the server.
+func (c *Client) GetRecord(uid string) (data []byte, err error) {
+	var epoch uint64
+	var isActive bool
+	if c.tracker != nil {
+		epoch, isActive = c.tracker.state()
+		if isActive {
+			data, err = c.near.GetRecord(uid)
+			if err == nil {
+				return data, nil
+			}
+		}
+	}
+
+	var reply any
+	reply, err = c.do("GET", uid)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, fmt.Errorf(ErrRecordIsNotFound, uid)
+	}
+
+	value, ok := reply.(string)
+	if !ok {
+		return nil, fmt.Errorf(ErrReplyIsUnexpected, reply)
+	}
+	data = []byte(value)
+
+	if isActive {
+		c.tracker.store(uid, data, epoch)
+	}
+
+	return data, nil
+}
+
+// RemoveRecord removes a record if it exists. Errors are reported to the
+// OnError callback.
+func (c *Client) RemoveRecord(uid string) {
+	_, err := c.remove(uid)
+	if err != nil {
+		c.reportError(err)
+	}
+}
+
+// RemoveExistingRecord removes an existing record, it returns an error if
+// the record is not found.
+func (c *Client) RemoveExistingRecord(uid string) (err error) {
+	var isRemoved bool
+	isRemoved, err = c.remove(uid)
+	if err != nil {
+		return err
+	}
+	if !isRemoved {
+		return fmt.Errorf(ErrRecordIsNotFound, uid)
+	}
+
+	return nil
+}
+
+func (c *Client) remove(uid string) (isRemoved bool, err error) {
+	var reply any
+	reply, err = c.do("DEL", uid)
+
+	if c.tracker != nil {
+		c.tracker.invalidate(uid)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	count, ok := reply.(int64)
+	if !ok {
+		return false, fmt.Errorf(ErrReplyIsUnexpected, reply)
+	}
+
+	return count > 0, nil
+}
+
+// Clear removes all records of the database.
+func (c *Client) Clear() (err error) {
+	_, err = c.do("FLUSHDB")
+
+	if c.tracker != nil {
+		c.tracker.invalidateAll()
+	}
+
+	return err
+}
+
+// NearCache returns the near cache, it is nil when the near cache is not
+// configured.
+func (c *Client) NearCache() (near *vl.Cache[string, []byte]) {
+	return c.near
+}
+
+func (c *Client) reportError(err error) {
+	if c.settings.OnError != nil {
+		c.settings.OnError(err)
+	}
+}
+
+// Close stops tracking of keys and closes the connections and the near
+// cache. Commands which are being executed are finished.
+func (c *Client) Close() (err error) {
+	if c.tracker != nil {
+		c.tracker.close()
+	}
+
+	c.pool.close()
+
+	if c.near != nil {
+		return c.near.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	cache "github.com/vault-thirteen/Cache"
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/cachetest"
+	"github.com/vault-thirteen/Cache/resp"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+// stringCache adapts the client to string data for the conformance suite.
+type stringCache struct {
+	c *Client
+}
+
+func (sc stringCache) RecordExists(uid string) (recordExists bool) {
+	return sc.c.RecordExists(uid)
+}
+
+func (sc stringCache) AddRecord(uid string, data string) (err error) {
+	return sc.c.AddRecord(uid, []byte(data))
+}
+
+func (sc stringCache) GetRecord(uid string) (data string, err error) {
+	var buf []byte
+	buf, err = sc.c.GetRecord(uid)
+	return string(buf), err
+}
+
+func (sc stringCache) RemoveRecord(uid string) {
+	sc.c.RemoveRecord(uid)
+}
+
+func (sc stringCache) RemoveExistingRecord(uid string) (err error) {
+	return sc.c.RemoveExistingRecord(uid)
+}
+
+func (sc stringCache) Clear() (err error) {
+	return sc.c.Clear()
+}
+
+func Test_Conformance(t *testing.T) {
+	t.Run("Remote", func(t *testing.T) {
+		cachetest.Run(t, func(t *testing.T, s cachetest.Settings) cache.Cache[string, string] {
+			_, address := _test_serve(t)
+			return stringCache{_test_dial(t, Settings{Address: address})}
+		}, cachetest.Features{})
+	})
+
+	t.Run("NearCache", func(t *testing.T) {
+		cachetest.Run(t, func(t *testing.T, s cachetest.Settings) cache.Cache[string, string] {
+			_, address := _test_serve(t)
+			c := _test_dial(t, Settings{Address: address, NearCache: _test_near_cache()})
+			_test_wait_tracking(t, c)
+
+			return stringCache{c}
+		}, cachetest.Features{})
+	})
+}
+
+func Test_Dial(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	_, address := _test_serve(t)
+
+	// Test #1. Bad settings.
+	for _, tc := range []struct {
+		settings Settings
+		err      string
+	}{
+		{Settings{}, ErrAddressIsNotSet},
+		{Settings{Address: address, Database: -1}, ErrDatabaseIsNegative},
+		{Settings{Address: address, PoolSize: -1}, ErrPoolSizeIsNegative},
+		{Settings{Address: address, Timeout: -1}, ErrTimeoutIsNegative},
+		{Settings{Address: address, DialTimeout: -1}, ErrTimeoutIsNegative},
+		{Settings{Address: address, ReconnectInterval: -1}, ErrTimeoutIsNegative},
+	} {
+		_, err = Dial(tc.settings)
+		aTest.MustBeAnError(err)
+		aTest.MustBeEqual(err.Error(), tc.err)
+	}
+
+	// Test #2. Bad near cache.
+	_, err = Dial(Settings{Address: address, NearCache: &vl.Config[string, []byte]{}})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf("near cache is not valid: %v", vl.ErrTtlIsZero))
+
+	// Test #3. Bad database.
+	_, err = Dial(Settings{Address: address, Database: 2})
+	aTest.MustBeAnError(err)
+	var replyErr resp.Error
+	aTest.MustBeEqual(errors.As(err, &replyErr), true)
+
+	// Test #4. Unreachable server.
+	s, unreachable := _test_serve(t)
+	aTest.MustBeNoError(s.Close())
+	_, err = Dial(Settings{Address: unreachable, DialTimeout: time.Second})
+	aTest.MustBeAnError(err)
+
+	// Test #5. Defaults.
+	c := _test_dial(t, Settings{Address: address})
+	aTest.MustBeEqual(c.settings.PoolSize, DefaultPoolSize)
+	aTest.MustBeEqual(c.settings.ReconnectInterval, DefaultReconnectInterval)
+	aTest.MustBeEqual(c.NearCache(), (*vl.Cache[string, []byte])(nil))
+}
+
+func Test_Client(t *testing.T) {
+	aTest := tester.New(t)
+	var data []byte
+	var err error
+
+	_, address := _test_serve(t)
+	var errs []error
+	var errsLock sync.Mutex
+	c0 := _test_dial(t, Settings{Address: address})
+	c1 := _test_dial(t, Settings{Address: address, Database: 1, OnError: func(err error) {
+		errsLock.Lock()
+		defer errsLock.Unlock()
+		errs = append(errs, err)
+	}})
+
+	// Test #1. Records.
+	aTest.MustBeNoError(c0.AddRecord("a", []byte("1")))
+	aTest.MustBeEqual(c0.RecordExists("a"), true)
+	data, err = c0.GetRecord("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("1"))
+
+	// Test #2. Databases are separate.
+	aTest.MustBeEqual(c1.RecordExists("a"), false)
+	_, err = c1.GetRecord("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrRecordIsNotFound, "a"))
+	aTest.MustBeNoError(c1.AddRecord("a", []byte("2")))
+	aTest.MustBeNoError(c1.Clear())
+	aTest.MustBeEqual(c0.RecordExists("a"), true)
+
+	// Test #3. Removal.
+	err = c0.RemoveExistingRecord("b")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrRecordIsNotFound, "b"))
+	aTest.MustBeNoError(c0.RemoveExistingRecord("a"))
+	aTest.MustBeEqual(c0.RecordExists("a"), false)
+	aTest.MustBeNoError(c0.AddRecord("a", []byte("1")))
+	c0.RemoveRecord("a")
+	aTest.MustBeEqual(c0.RecordExists("a"), false)
+
+	// Test #4. Errors of methods without errors are reported.
+	aTest.MustBeNoError(c1.Close())
+	aTest.MustBeEqual(c1.RecordExists("a"), false)
+	c1.RemoveRecord("a")
+	errsLock.Lock()
+	aTest.MustBeEqual(len(errs), 2)
+	aTest.MustBeEqual(errs[0].Error(), ErrClientIsClosed)
+	errsLock.Unlock()
+	_, err = c1.GetRecord("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrClientIsClosed)
+}
+
+func Test_Client_pool(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	s, address := _test_serve(t)
+	c := _test_dial(t, Settings{Address: address, PoolSize: 1, Timeout: time.Millisecond * 100})
+
+	// Test #1. The connection is reused.
+	aTest.MustBeNoError(c.AddRecord("a", []byte("1")))
+	aTest.MustBeEqual(c.RecordExists("a"), true)
+	aTest.MustBeEqual(len(c.pool.idle), 1)
+	aTest.MustBeEqual(len(c.pool.slots), 1)
+
+	// Test #2. The pool is exhausted.
+	conn, err := c.pool.get()
+	aTest.MustBeNoError(err)
+	_, err = c.GetRecord("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrPoolIsExhausted)
+	_, err = c.CheckRecord("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrPoolIsExhausted)
+	c.pool.put(conn, false)
+	_, err = c.GetRecord("a")
+	aTest.MustBeNoError(err)
+
+	// Test #3. A broken connection is replaced.
+	aTest.MustBeNoError(s.Close())
+	_, err = c.GetRecord("a")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(len(c.pool.idle), 0)
+	aTest.MustBeEqual(len(c.pool.slots), 0)
+
+	// Test #4. Closed client.
+	aTest.MustBeNoError(c.Close())
+	_, err = c.pool.get()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrClientIsClosed)
+}
+
+func Test_Client_nearCache(t *testing.T) {
+	aTest := tester.New(t)
+	var data []byte
+	var err error
+
+	s, address := _test_serve(t)
+	var errs = make(chan error, 10)
+	c := _test_dial(t, Settings{
+		Address:           address,
+		NearCache:         _test_near_cache(),
+		ReconnectInterval: time.Hour,
+		OnError:           func(err error) { errs <- err },
+	})
+	_test_wait_tracking(t, c)
+	other := _test_dial(t, Settings{Address: address})
+	near := c.NearCache()
+
+	// Test #1. Records read from the server are cached.
+	aTest.MustBeNoError(c.AddRecord("a", []byte("1")))
+	aTest.MustBeEqual(near.RecordExists("a"), false)
+	data, err = c.GetRecord("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("1"))
+	aTest.MustBeEqual(near.RecordExists("a"), true)
+
+	// Test #2. Own modifications are visible at once.
+	aTest.MustBeNoError(c.AddRecord("a", []byte("2")))
+	aTest.MustBeEqual(near.RecordExists("a"), false)
+	data, err = c.GetRecord("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("2"))
+
+	// Test #3. Modifications of other clients are pushed.
+	aTest.MustBeNoError(other.AddRecord("a", []byte("3")))
+	_test_eventually(t, func() bool { return !near.RecordExists("a") })
+	data, err = c.GetRecord("a")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("3"))
+
+	other.RemoveRecord("a")
+	_test_eventually(t, func() bool { return !near.RecordExists("a") })
+	aTest.MustBeEqual(c.RecordExists("a"), false)
+
+	aTest.MustBeNoError(other.AddRecord("b", []byte("1")))
+	_, err = c.GetRecord("b")
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(other.Clear())
+	_test_eventually(t, func() bool { return !near.RecordExists("b") })
+
+	// Test #4. The near cache is not used without tracking.
+	aTest.MustBeNoError(s.Close())
+	_test_eventually(t, func() bool {
+		_, isActive := c.tracker.state()
+		return !isActive
+	})
+	err = <-errs
+	aTest.MustBeAnError(err)
+	_, err = c.GetRecord("b")
+	aTest.MustBeAnError(err)
+}
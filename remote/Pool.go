@@ -0,0 +1,138 @@
+package remote
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vault-thirteen/Cache/resp"
+)
+
+// pool is a pool of connections to the server. Connections are created on
+// demand until their number reaches the size of the pool, idle connections
+// are reused.
+type pool struct {
+	settings Settings
+
+	// idle contains idle connections.
+	idle chan *resp.Client
+
+	// slots limit the number of connections, each connection occupies a
+	// slot.
+	slots chan struct{}
+
+	// lock guards returning of connections to the pool after it is closed.
+	lock     *sync.Mutex
+	isClosed bool
+	closeCh  chan struct{}
+}
+
+func newPool(settings Settings) (p *pool) {
+	return &pool{
+		settings: settings,
+		idle:     make(chan *resp.Client, settings.PoolSize),
+		slots:    make(chan struct{}, settings.PoolSize),
+		lock:     new(sync.Mutex),
+		closeCh:  make(chan struct{}),
+	}
+}
+
+// get returns an idle connection or a new one. When all the connections are
+// busy, it waits for a connection to be returned, at most for the timeout of
+// a command.
+func (p *pool) get() (conn *resp.Client, err error) {
+	select {
+	case <-p.closeCh:
+		return nil, errors.New(ErrClientIsClosed)
+	default:
+	}
+
+	select {
+	case conn = <-p.idle:
+		return conn, nil
+	default:
+	}
+
+	var timeout <-chan time.Time
+	if p.settings.Timeout > 0 {
+		timer := time.NewTimer(p.settings.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case conn = <-p.idle:
+		return conn, nil
+	case p.slots <- struct{}{}:
+		conn, err = p.dial()
+		if err != nil {
+			<-p.slots
+			return nil, err
+		}
+		return conn, nil
+	case <-timeout:
+		return nil, errors.New(ErrPoolIsExhausted)
+	case <-p.closeCh:
+		return nil, errors.New(ErrClientIsClosed)
+	}
+}
+
+func (p *pool) dial() (conn *resp.Client, err error) {
+	conn, err = resp.Dial(p.settings.Address, p.settings.DialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.settings.Database != 0 {
+		if p.settings.Timeout > 0 {
+			_ = conn.SetDeadline(time.Now().Add(p.settings.Timeout))
+		}
+
+		_, err = conn.Do("SELECT", strconv.Itoa(p.settings.Database))
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// put returns the connection to the pool. A broken connection is closed.
+func (p *pool) put(conn *resp.Client, isBroken bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if !isBroken && !p.isClosed {
+		// The channel has room for all the connections.
+		p.idle <- conn
+		return
+	}
+
+	_ = conn.Close()
+	<-p.slots
+}
+
+// close closes idle connections. Busy connections are closed when they are
+// returned.
+func (p *pool) close() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.isClosed {
+		return
+	}
+	p.isClosed = true
+	close(p.closeCh)
+
+	for {
+		select {
+		case conn := <-p.idle:
+			_ = conn.Close()
+			<-p.slots
+		default:
+			return
+		}
+	}
+}
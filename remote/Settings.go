@@ -0,0 +1,72 @@
+package remote
+
+import (
+	"errors"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+const (
+	// DefaultPoolSize is the default maximum number of connections used for
+	// commands.
+	DefaultPoolSize = 8
+
+	// DefaultReconnectInterval is the default interval between attempts to
+	// restore the tracking connection of the near cache.
+	DefaultReconnectInterval = time.Second
+)
+
+// Settings contains settings of the client.
+type Settings struct {
+	// Address is a TCP address of the RESP server, e.g. "127.0.0.1:6379".
+	Address string
+
+	// Database is an index of the logical database of the server.
+	Database int
+
+	// PoolSize is a maximum number of connections used for commands. Zero
+	// selects the default size.
+	PoolSize int
+
+	// DialTimeout is an optional timeout of connecting to the server.
+	DialTimeout time.Duration
+
+	// Timeout is an optional timeout of a command, including waiting for a
+	// free connection of the pool.
+	Timeout time.Duration
+
+	// NearCache is an optional configuration of the local cache of records
+	// read from the server. The near cache is used only while the client
+	// tracks keys of the server, modifications of the keys made by any
+	// client remove them from the near cache. The TTL of the near cache
+	// limits the time during which a key expired on the server may be
+	// served, so it should be short.
+	NearCache *vl.Config[string, []byte]
+
+	// ReconnectInterval is an interval between attempts to restore the
+	// tracking connection. Zero selects the default interval.
+	ReconnectInterval time.Duration
+
+	// OnError is an optional callback which receives errors which can not be
+	// returned to a caller, i.e. errors of the RemoveRecord and RecordExists
+	// methods and errors of tracking of keys.
+	OnError func(err error)
+}
+
+func (s Settings) validate() (err error) {
+	if len(s.Address) == 0 {
+		return errors.New(ErrAddressIsNotSet)
+	}
+	if s.Database < 0 {
+		return errors.New(ErrDatabaseIsNegative)
+	}
+	if s.PoolSize < 0 {
+		return errors.New(ErrPoolSizeIsNegative)
+	}
+	if (s.DialTimeout < 0) || (s.Timeout < 0) || (s.ReconnectInterval < 0) {
+		return errors.New(ErrTimeoutIsNegative)
+	}
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/resp"
+)
+
+// tracker keeps the near cache coherent with the server. It holds a
+// dedicated connection tracking all the keys of the server and removes keys
+// from the near cache when the server pushes their invalidation messages.
+// While the connection is broken, the near cache is empty and is not used.
+type tracker struct {
+	settings Settings
+	near     *vl.Cache[string, []byte]
+
+	// lock guards the state of tracking and the connection.
+	lock *sync.Mutex
+
+	// epoch is changed by each invalidation. A record read from the server
+	// is added to the near cache only if no invalidation has happened during
+	// the reading, otherwise a stale record could replace the invalidated
+	// one.
+	epoch    uint64
+	isActive bool
+	conn     *resp.Client
+
+	closeCh chan struct{}
+	wg      *sync.WaitGroup
+}
+
+func newTracker(settings Settings, near *vl.Cache[string, []byte]) (t *tracker) {
+	t = &tracker{
+		settings: settings,
+		near:     near,
+		lock:     new(sync.Mutex),
+		closeCh:  make(chan struct{}),
+		wg:       new(sync.WaitGroup),
+	}
+
+	t.wg.Add(1)
+	go t.run()
+
+	return t
+}
+
+// run tracks keys until the tracker is closed, broken connections are
+// restored.
+func (t *tracker) run() {
+	defer t.wg.Done()
+
+	for {
+		err := t.track()
+		t.setActive(false)
+
+		select {
+		case <-t.closeCh:
+			return
+		default:
+		}
+
+		t.reportError(fmt.Errorf(ErrTrackingIsFailed, err))
+
+		select {
+		case <-t.closeCh:
+			return
+		case <-time.After(t.settings.ReconnectInterval):
+		}
+	}
+}
+
+// track connects to the server and applies invalidation messages until the
+// connection is broken.
+func (t *tracker) track() (err error) {
+	var conn *resp.Client
+	conn, err = resp.Dial(t.settings.Address, t.settings.DialTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	t.lock.Lock()
+	select {
+	case <-t.closeCh:
+		t.lock.Unlock()
+		return errors.New(ErrClientIsClosed)
+	default:
+	}
+	t.conn = conn
+	t.lock.Unlock()
+
+	if t.settings.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(t.settings.Timeout))
+	}
+	_, err = conn.Do("HELLO", "3")
+	if err != nil {
+		return err
+	}
+	_, err = conn.Do("CLIENT", "TRACKING", "ON", "BCAST")
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	t.setActive(true)
+
+	for {
+		var message []any
+		message, err = conn.Receive()
+		if err != nil {
+			return err
+		}
+
+		t.apply(message)
+	}
+}
+
+// apply applies an invalidation message. Other messages are ignored.
+func (t *tracker) apply(message []any) {
+	if (len(message) != 2) || (message[0] != "invalidate") {
+		return
+	}
+
+	if message[1] == nil {
+		t.invalidateAll()
+		return
+	}
+
+	keys, _ := message[1].([]any)
+	for _, key := range keys {
+		if uid, ok := key.(string); ok {
+			t.invalidate(uid)
+		}
+	}
+}
+
+// setActive changes the state of tracking. The near cache is cleared in both
+// cases, as invalidation messages may have been lost.
+func (t *tracker) setActive(isActive bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.isActive = isActive
+	t.epoch++
+	t.clear()
+}
+
+// state returns the current epoch and tells whether the near cache may be
+// used.
+func (t *tracker) state() (epoch uint64, isActive bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.epoch, t.isActive
+}
+
+// store adds a record read from the server to the near cache if no
+// invalidation has happened since the epoch.
+func (t *tracker) store(uid string, data []byte, epoch uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !t.isActive || (t.epoch != epoch) {
+		return
+	}
+
+	// Records which do not fit the near cache are simply not cached.
+	_ = t.near.AddRecord(uid, data)
+}
+
+// invalidate removes a record from the near cache.
+func (t *tracker) invalidate(uid string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.epoch++
+	t.near.RemoveRecord(uid)
+}
+
+// invalidateAll removes all the records from the near cache.
+func (t *tracker) invalidateAll() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.epoch++
+	t.clear()
+}
+
+func (t *tracker) clear() {
+	err := t.near.Clear()
+	if err != nil {
+		t.reportError(fmt.Errorf(ErrTrackingIsFailed, err))
+	}
+}
+
+func (t *tracker) reportError(err error) {
+	if t.settings.OnError != nil {
+		t.settings.OnError(err)
+	}
+}
+
+// close stops tracking and waits for it to be finished.
+func (t *tracker) close() {
+	t.lock.Lock()
+	select {
+	case <-t.closeCh:
+		t.lock.Unlock()
+		return
+	default:
+	}
+	close(t.closeCh)
+	if t.conn != nil {
+		_ = t.conn.Close()
+	}
+	t.lock.Unlock()
+
+	t.wg.Wait()
+}
@@ -0,0 +1,68 @@
+package remote
+
+import (
+	"sync"
+	"testing"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_tracker_store(t *testing.T) {
+	aTest := tester.New(t)
+
+	tr := &tracker{
+		near: vl.NewCache[string, []byte](0, 0, 60),
+		lock: new(sync.Mutex),
+	}
+
+	// Test #1. Inactive tracker.
+	epoch, isActive := tr.state()
+	aTest.MustBeEqual(isActive, false)
+	tr.store("a", []byte("1"), epoch)
+	aTest.MustBeEqual(tr.near.RecordExists("a"), false)
+
+	// Test #2. Active tracker.
+	tr.setActive(true)
+	epoch, isActive = tr.state()
+	aTest.MustBeEqual(isActive, true)
+	tr.store("a", []byte("1"), epoch)
+	aTest.MustBeEqual(tr.near.RecordExists("a"), true)
+
+	// Test #3. A record read before an invalidation is not stored.
+	epoch, _ = tr.state()
+	tr.invalidate("b")
+	tr.store("b", []byte("1"), epoch)
+	aTest.MustBeEqual(tr.near.RecordExists("b"), false)
+
+	// Test #4. Deactivation clears the near cache.
+	tr.setActive(false)
+	aTest.MustBeEqual(tr.near.RecordExists("a"), false)
+}
+
+func Test_tracker_apply(t *testing.T) {
+	aTest := tester.New(t)
+
+	tr := &tracker{
+		near: vl.NewCache[string, []byte](0, 0, 60),
+		lock: new(sync.Mutex),
+	}
+	for _, uid := range []string{"a", "b", "c"} {
+		aTest.MustBeNoError(tr.near.AddRecord(uid, []byte(uid)))
+	}
+
+	// Test #1. Other messages are ignored.
+	tr.apply([]any{"message", []any{"a"}})
+	tr.apply([]any{"invalidate"})
+	aTest.MustBeEqual(tr.near.RecordExists("a"), true)
+
+	// Test #2. Keys.
+	tr.apply([]any{"invalidate", []any{"a", "b"}})
+	aTest.MustBeEqual(tr.near.RecordExists("a"), false)
+	aTest.MustBeEqual(tr.near.RecordExists("b"), false)
+	aTest.MustBeEqual(tr.near.RecordExists("c"), true)
+
+	// Test #3. All the keys.
+	tr.apply([]any{"invalidate", nil})
+	aTest.MustBeEqual(tr.near.RecordExists("c"), false)
+}
@@ -0,0 +1,68 @@
+package remote
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/resp"
+)
+
+// _test_serve starts a RESP server of two new databases on a free local
+// port.
+func _test_serve(t *testing.T) (s *resp.Server, address string) {
+	var databases []*vl.Cache[string, []byte]
+	for i := 0; i < 2; i++ {
+		databases = append(databases, vl.NewCache[string, []byte](0, 0, 3600))
+	}
+
+	s, err := resp.NewServer(databases, resp.Settings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = s.Serve(l) }()
+	t.Cleanup(func() { _ = s.Close() })
+
+	return s, l.Addr().String()
+}
+
+// _test_dial creates a client which is closed at the end of the test.
+func _test_dial(t *testing.T, settings Settings) (c *Client) {
+	c, err := Dial(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c
+}
+
+// _test_near_cache is a configuration of a near cache.
+func _test_near_cache() (cfg *vl.Config[string, []byte]) {
+	return &vl.Config[string, []byte]{SizeLimit: 100, RecordTtl: 60}
+}
+
+// _test_wait_tracking waits for the client to track keys.
+func _test_wait_tracking(t *testing.T, c *Client) {
+	_test_eventually(t, func() bool {
+		_, isActive := c.tracker.state()
+		return isActive
+	})
+}
+
+// _test_eventually waits for the condition to become true.
+func _test_eventually(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(time.Second * 5)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition is not met")
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+}
@@ -0,0 +1,16 @@
+package remote
+
+import vl "github.com/vault-thirteen/Cache/VL"
+
+const (
+	ErrAddressIsNotSet     = "address is not set"
+	ErrDatabaseIsNegative  = "database is negative"
+	ErrPoolSizeIsNegative  = "pool size is negative"
+	ErrTimeoutIsNegative   = "timeout is negative"
+	ErrClientIsClosed      = "client is closed"
+	ErrPoolIsExhausted     = "connection pool is exhausted"
+	ErrReplyIsUnexpected   = "reply is unexpected: %v"
+	ErrRecordIsNotFound    = vl.ErrRecordIsNotFound
+	ErrTrackingIsFailed    = "tracking is failed: %w"
+	ErrNearCacheIsNotValid = "near cache is not valid: %w"
+)
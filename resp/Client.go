@@ -0,0 +1,252 @@
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Error is an error reply of the server.
+type Error string
+
+// Error returns the message of the error reply.
+func (e Error) Error() string {
+	return string(e)
+}
+
+// Client is a minimal RESP client. It sends commands as arrays of bulk
+// strings and decodes replies of both protocol versions into Go values:
+// simple, bulk and verbatim strings into strings, integers into int64,
+// booleans into bool, doubles into float64, arrays, sets and pushes into
+// []any, maps into map[string]any and nulls into nil. The client is not safe
+// for concurrent use.
+//
+// Pushed messages received while waiting for a reply of a command are
+// skipped, they are read by the Receive method.
+type Client struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+// Dial connects to the server at the TCP address.
+func Dial(address string, timeout time.Duration) (c *Client, err error) {
+	var conn net.Conn
+	conn, err = net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Client{
+		conn: conn,
+		r:    bufio.NewReader(conn),
+		w:    bufio.NewWriter(conn),
+	}
+
+	return c, nil
+}
+
+// Do sends the command and returns its reply. An error reply is returned as
+// an error of the Error type.
+func (c *Client) Do(args ...string) (reply any, err error) {
+	if len(args) == 0 {
+		return nil, errors.New(ErrCommandIsNotSet)
+	}
+
+	_, _ = c.w.WriteString("*" + strconv.Itoa(len(args)) + crlf)
+	for _, arg := range args {
+		_, _ = c.w.WriteString("$" + strconv.Itoa(len(arg)) + crlf + arg + crlf)
+	}
+	err = c.w.Flush()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var isPush bool
+		isPush, err = c.isPushNext()
+		if err != nil {
+			return nil, err
+		}
+
+		reply, err = c.readReply()
+		if err != nil {
+			return nil, err
+		}
+
+		if !isPush {
+			break
+		}
+	}
+
+	if e, isError := reply.(Error); isError {
+		return nil, e
+	}
+
+	return reply, nil
+}
+
+// Receive waits for a pushed message, e.g. an invalidation message of a
+// connection tracking keys, and returns it. Replies which are not pushed are
+// skipped.
+func (c *Client) Receive() (message []any, err error) {
+	for {
+		var isPush bool
+		isPush, err = c.isPushNext()
+		if err != nil {
+			return nil, err
+		}
+
+		var reply any
+		reply, err = c.readReply()
+		if err != nil {
+			return nil, err
+		}
+
+		if isPush {
+			message, _ = reply.([]any)
+			return message, nil
+		}
+	}
+}
+
+func (c *Client) isPushNext() (isPush bool, err error) {
+	var b []byte
+	b, err = c.r.Peek(1)
+	if err != nil {
+		return false, err
+	}
+
+	return b[0] == '>', nil
+}
+
+func (c *Client) readReply() (reply any, err error) {
+	var line string
+	line, err = c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf(ErrReplyTypeIsUnknown, "")
+	}
+
+	kind, payload := line[0], line[1:]
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return Error(payload), nil
+	case ':':
+		return parseInteger(payload)
+	case '_':
+		return nil, nil
+	case '#':
+		return payload == "t", nil
+	case ',':
+		return strconv.ParseFloat(payload, 64)
+	case '$', '=':
+		var size int64
+		size, err = parseInteger(payload)
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+
+		buf := make([]byte, size+int64(len(crlf)))
+		_, err = io.ReadFull(c.r, buf)
+		if err != nil {
+			return nil, err
+		}
+
+		text := string(buf[:size])
+		if (kind == '=') && (len(text) >= 4) {
+			// The format of a verbatim string is skipped.
+			text = text[4:]
+		}
+
+		return text, nil
+	case '*', '~', '>':
+		var count int64
+		count, err = parseInteger(payload)
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 {
+			return nil, nil
+		}
+
+		items := make([]any, 0, count)
+		for i := int64(0); i < count; i++ {
+			var item any
+			item, err = c.readReply()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+
+		return items, nil
+	case '%':
+		var count int64
+		count, err = parseInteger(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		m := make(map[string]any, count)
+		for i := int64(0); i < count; i++ {
+			var key, value any
+			key, err = c.readReply()
+			if err != nil {
+				return nil, err
+			}
+			value, err = c.readReply()
+			if err != nil {
+				return nil, err
+			}
+			m[fmt.Sprint(key)] = value
+		}
+
+		return m, nil
+	default:
+		return nil, fmt.Errorf(ErrReplyTypeIsUnknown, kind)
+	}
+}
+
+func (c *Client) readLine() (line string, err error) {
+	line, err = c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if (len(line) < 2) || (line[len(line)-2] != '\r') {
+		return "", errors.New(ErrLineTerminatorIsAbsent)
+	}
+
+	return line[:len(line)-2], nil
+}
+
+func parseInteger(s string) (n int64, err error) {
+	n, err = strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf(ErrIntegerIsBad, s)
+	}
+
+	return n, nil
+}
+
+// SetDeadline sets the deadline of reading and writing of the connection. A
+// zero time disables the deadline.
+func (c *Client) SetDeadline(t time.Time) (err error) {
+	return c.conn.SetDeadline(t)
+}
+
+// Close closes the connection.
+func (c *Client) Close() (err error) {
+	return c.conn.Close()
+}
@@ -0,0 +1,83 @@
+package resp
+
+import (
+	"bufio"
+	"net"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+// _test_client_with_reply creates a client reading the raw reply.
+func _test_client_with_reply(reply string) (c *Client) {
+	clientConn, serverConn := net.Pipe()
+	go func() {
+		_, _ = bufio.NewReader(serverConn).ReadString('\n')
+		_, _ = serverConn.Write([]byte(reply))
+	}()
+
+	return &Client{conn: clientConn, r: bufio.NewReader(clientConn), w: bufio.NewWriter(clientConn)}
+}
+
+func Test_Client_Do(t *testing.T) {
+	aTest := tester.New(t)
+	var reply any
+	var err error
+
+	// Test #1. Types of replies.
+	for _, tc := range []struct {
+		raw   string
+		reply any
+	}{
+		{"+OK\r\n", "OK"},
+		{":-5\r\n", int64(-5)},
+		{"$3\r\nabc\r\n", "abc"},
+		{"$-1\r\n", nil},
+		{"_\r\n", nil},
+		{"#t\r\n", true},
+		{",1.5\r\n", 1.5},
+		{"=7\r\ntxt:abc\r\n", "abc"},
+		{"*2\r\n:1\r\n$1\r\na\r\n", []any{int64(1), "a"}},
+		{"*-1\r\n", nil},
+		{"~1\r\n+x\r\n", []any{"x"}},
+		{"%1\r\n+k\r\n:2\r\n", map[string]any{"k": int64(2)}},
+		{"*1\r\n-ERR x\r\n", []any{Error("ERR x")}},
+	} {
+		reply, err = _test_client_with_reply(tc.raw).Do("X")
+		aTest.MustBeNoError(err)
+		aTest.MustBeEqual(reply, tc.reply)
+	}
+
+	// Test #2. Error reply.
+	_, err = _test_client_with_reply("-ERR x\r\n").Do("X")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err, error(Error("ERR x")))
+
+	// Test #3. Malformed replies.
+	for _, raw := range []string{"?\r\n", "\r\n", ":x\r\n", "+OK\n"} {
+		_, err = _test_client_with_reply(raw).Do("X")
+		aTest.MustBeAnError(err)
+	}
+
+	// Test #4. No command.
+	_, err = new(Client).Do()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrCommandIsNotSet)
+}
+
+func Test_Client_Receive(t *testing.T) {
+	aTest := tester.New(t)
+	var reply any
+	var message []any
+	var err error
+
+	// Test #1. Pushed messages are skipped by commands.
+	client := _test_client_with_reply(">1\r\n+x\r\n+OK\r\n>2\r\n+y\r\n_\r\n")
+	reply, err = client.Do("X")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(reply, "OK")
+
+	message, err = client.Receive()
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(message, []any{"y", nil})
+}
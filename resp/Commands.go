@@ -0,0 +1,525 @@
+package resp
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/internal/itemcodec"
+)
+
+// Version is the version of the Redis protocol reported by the server.
+const Version = "7.0.0"
+
+// Error replies.
+const (
+	replyErrSyntax        = "ERR syntax error"
+	replyErrNotInteger    = "ERR value is not an integer or out of range"
+	replyErrDbIndex       = "ERR DB index is out of range"
+	replyErrExpireTime    = "ERR invalid expire time in '%s' command"
+	replyErrNoProto       = "NOPROTO unsupported protocol version"
+	replyErrOutOfMemory   = "OOM command not allowed when used memory > 'maxmemory'"
+	replyErrCommandFailed = "ERR %v"
+	replyErrTrackingMode  = "ERR only the BCAST mode of tracking is supported"
+	replyErrTrackingProto = "ERR tracking requires RESP3"
+)
+
+// command is a handler of a command with its arity. A positive arity is the
+// exact number of arguments including the name of the command, a negative
+// arity is the minimum number.
+type command struct {
+	handler func(c *connection, args []string) (err error)
+	arity   int
+}
+
+var commands = map[string]command{
+	"ping":    {(*connection).ping, -1},
+	"quit":    {(*connection).quit, 1},
+	"select":  {(*connection).selectDb, 2},
+	"hello":   {(*connection).hello, -1},
+	"client":  {(*connection).client, -2},
+	"command": {(*connection).command, -1},
+	"get":     {(*connection).get, 2},
+	"set":     {(*connection).set, -3},
+	"del":     {(*connection).del, -2},
+	"exists":  {(*connection).exists, -2},
+	"ttl":     {(*connection).ttl, 2},
+	"pttl":    {(*connection).pttl, 2},
+	"expire":  {(*connection).expire, 3},
+	"flushdb": {(*connection).flushDb, -1},
+	"dbsize":  {(*connection).dbSize, 1},
+	"info":    {(*connection).info, -1},
+}
+
+// PING [message]
+func (c *connection) ping(args []string) (err error) {
+	switch len(args) {
+	case 0:
+		c.writeSimple("PONG")
+	case 1:
+		c.writeBulk([]byte(args[0]))
+	default:
+		c.writeError("ERR wrong number of arguments for 'ping' command")
+	}
+
+	return nil
+}
+
+// QUIT
+func (c *connection) quit(_ []string) (err error) {
+	c.writeSimple("OK")
+	return errQuit
+}
+
+// SELECT index
+func (c *connection) selectDb(args []string) (err error) {
+	db, err := strconv.Atoi(args[0])
+	if err != nil {
+		c.writeError(replyErrNotInteger)
+		return nil
+	}
+	if (db < 0) || (db >= len(c.server.databases)) {
+		c.writeError(replyErrDbIndex)
+		return nil
+	}
+
+	c.db = db
+	c.writeSimple("OK")
+
+	return nil
+}
+
+// HELLO [protover]
+//
+// Authentication and naming of connections are not supported.
+func (c *connection) hello(args []string) (err error) {
+	if len(args) > 1 {
+		c.writeError(replyErrSyntax)
+		return nil
+	}
+
+	if len(args) == 1 {
+		version, err := strconv.Atoi(args[0])
+		if err != nil {
+			c.writeError("ERR Protocol version is not an integer or out of range")
+			return nil
+		}
+		if (version != protocolVersion2) && (version != protocolVersion3) {
+			c.writeError(replyErrNoProto)
+			return nil
+		}
+		c.protocolVersion = version
+	}
+
+	c.writeMapHeader(6)
+	c.writeBulk([]byte("server"))
+	c.writeBulk([]byte("redis"))
+	c.writeBulk([]byte("version"))
+	c.writeBulk([]byte(Version))
+	c.writeBulk([]byte("proto"))
+	c.writeInteger(int64(c.protocolVersion))
+	c.writeBulk([]byte("mode"))
+	c.writeBulk([]byte("standalone"))
+	c.writeBulk([]byte("role"))
+	c.writeBulk([]byte("master"))
+	c.writeBulk([]byte("modules"))
+	c.writeArrayHeader(0)
+
+	return nil
+}
+
+// CLIENT ID
+// CLIENT TRACKING ON BCAST [NOLOOP]
+// CLIENT TRACKING OFF
+//
+// Only the broadcasting mode of tracking is supported, a tracking connection
+// receives invalidation messages of all the keys of all the databases.
+// Invalidation messages are pushed, so tracking requires RESP3.
+func (c *connection) client(args []string) (err error) {
+	switch strings.ToUpper(args[0]) {
+	case "ID":
+		if len(args) != 1 {
+			c.writeError(replyErrSyntax)
+			return nil
+		}
+		c.writeInteger(c.id)
+
+	case "TRACKING":
+		c.tracking(args[1:])
+
+	default:
+		c.writeError(fmt.Sprintf("ERR unknown subcommand '%s'", args[0]))
+	}
+
+	return nil
+}
+
+func (c *connection) tracking(args []string) {
+	if len(args) == 0 {
+		c.writeError(replyErrSyntax)
+		return
+	}
+
+	switch strings.ToUpper(args[0]) {
+	case "ON":
+		var isBroadcast, noLoop bool
+		for _, option := range args[1:] {
+			switch strings.ToUpper(option) {
+			case "BCAST":
+				isBroadcast = true
+			case "NOLOOP":
+				noLoop = true
+			default:
+				c.writeError(replyErrSyntax)
+				return
+			}
+		}
+
+		if !isBroadcast {
+			c.writeError(replyErrTrackingMode)
+			return
+		}
+		if c.protocolVersion != protocolVersion3 {
+			c.writeError(replyErrTrackingProto)
+			return
+		}
+
+		c.server.startTracking(c, noLoop)
+
+	case "OFF":
+		if len(args) != 1 {
+			c.writeError(replyErrSyntax)
+			return
+		}
+
+		c.server.stopTracking(c)
+
+	default:
+		c.writeError(replyErrSyntax)
+		return
+	}
+
+	c.writeSimple("OK")
+}
+
+// COMMAND [subcommand]
+//
+// Documentation of commands is not provided, an empty array is returned, so
+// that clients requesting it at start work.
+func (c *connection) command(_ []string) (err error) {
+	c.writeArrayHeader(0)
+	return nil
+}
+
+// GET key
+func (c *connection) get(args []string) (err error) {
+	it, found := c.getItem(args[0])
+	if !found {
+		c.writeNull()
+		return nil
+	}
+
+	c.writeBulk(it.Data)
+
+	return nil
+}
+
+// getItem reads an item of the selected database and counts the result.
+func (c *connection) getItem(key string) (it itemcodec.Item, found bool) {
+	it, found = c.server.getItem(c.db, key)
+	if found {
+		c.server.counters.keyspaceHits.Add(1)
+	} else {
+		c.server.counters.keyspaceMisses.Add(1)
+	}
+
+	return it, found
+}
+
+// SET key value [EX seconds | PX milliseconds]
+func (c *connection) set(args []string) (err error) {
+	key, value := args[0], args[1]
+
+	var expiresAt int64
+	var unit time.Duration
+	options := args[2:]
+	for len(options) > 0 {
+		option := strings.ToUpper(options[0])
+		if ((option != "EX") && (option != "PX")) || (len(options) < 2) || (unit != 0) {
+			c.writeError(replyErrSyntax)
+			return nil
+		}
+
+		unit = time.Second
+		if option == "PX" {
+			unit = time.Millisecond
+		}
+
+		ttl, parseErr := strconv.ParseInt(options[1], 10, 64)
+		if parseErr != nil {
+			c.writeError(replyErrNotInteger)
+			return nil
+		}
+		if ttl <= 0 {
+			c.writeError(fmt.Sprintf(replyErrExpireTime, "set"))
+			return nil
+		}
+
+		var isValid bool
+		expiresAt, isValid = expirationTime(c.server.nowMs(), ttl, unit)
+		if !isValid {
+			c.writeError(fmt.Sprintf(replyErrExpireTime, "set"))
+			return nil
+		}
+		options = options[2:]
+	}
+
+	err = c.server.setItem(c.db, key, []byte(value), expiresAt)
+	if err != nil {
+		c.writeStorageError(err)
+		return nil
+	}
+
+	c.invalidate(key)
+	c.writeSimple("OK")
+
+	return nil
+}
+
+func (c *connection) writeStorageError(err error) {
+	if err.Error() == vl.ErrRecordIsTooBig {
+		c.writeError(replyErrOutOfMemory)
+		return
+	}
+
+	c.writeError(fmt.Sprintf(replyErrCommandFailed, err))
+}
+
+// DEL key [key ...]
+func (c *connection) del(args []string) (err error) {
+	var count int64
+	for _, key := range args {
+		if c.server.deleteKey(c.db, key) {
+			c.invalidate(key)
+			count++
+		}
+	}
+
+	c.writeInteger(count)
+
+	return nil
+}
+
+// expirationTime returns the Unix time in milliseconds at which a key with
+// the positive TTL expires. The time is not valid when it overflows.
+func expirationTime(nowMs int64, ttl int64, unit time.Duration) (expiresAt int64, isValid bool) {
+	unitMs := int64(unit / time.Millisecond)
+	if ttl > (math.MaxInt64-nowMs)/unitMs {
+		return 0, false
+	}
+
+	return nowMs + ttl*unitMs, true
+}
+
+// EXISTS key [key ...]
+//
+// Keys are not touched, like by the RecordExists method of the cache.
+func (c *connection) exists(args []string) (err error) {
+	var count int64
+	for _, key := range args {
+		_, found := c.server.peekItem(c.db, key)
+		if found {
+			count++
+		}
+	}
+
+	c.writeInteger(count)
+
+	return nil
+}
+
+// TTL key
+func (c *connection) ttl(args []string) (err error) {
+	ttl := c.remainingTtl(args[0])
+	if ttl > 0 {
+		ttl = (ttl + 500) / 1000
+	}
+
+	c.writeInteger(ttl)
+
+	return nil
+}
+
+// PTTL key
+func (c *connection) pttl(args []string) (err error) {
+	c.writeInteger(c.remainingTtl(args[0]))
+	return nil
+}
+
+// remainingTtl returns the remaining time to live of a key in milliseconds,
+// -2 if the key does not exist and -1 if the key has no expiration time. The
+// TTL of the cache is not taken into account, and the key is not touched.
+func (c *connection) remainingTtl(key string) (ttl int64) {
+	it, found := c.server.peekItem(c.db, key)
+	if !found {
+		return -2
+	}
+	if it.ExpiresAt == 0 {
+		return -1
+	}
+
+	return it.ExpiresAt - c.server.nowMs()
+}
+
+// EXPIRE key seconds
+//
+// A non-positive number of seconds removes the key.
+func (c *connection) expire(args []string) (err error) {
+	seconds, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		c.writeError(replyErrNotInteger)
+		return nil
+	}
+
+	expiresAt := c.server.nowMs()
+	if seconds > 0 {
+		var isValid bool
+		expiresAt, isValid = expirationTime(expiresAt, seconds, time.Second)
+		if !isValid {
+			c.writeError(fmt.Sprintf(replyErrExpireTime, "expire"))
+			return nil
+		}
+	}
+
+	var found bool
+	found, err = c.server.setExpiration(c.db, args[0], expiresAt)
+	if err != nil {
+		c.writeStorageError(err)
+		return nil
+	}
+
+	if found {
+		c.invalidate(args[0])
+		c.writeInteger(1)
+	} else {
+		c.writeInteger(0)
+	}
+
+	return nil
+}
+
+// FLUSHDB [ASYNC | SYNC]
+//
+// The database is always flushed synchronously.
+func (c *connection) flushDb(args []string) (err error) {
+	if len(args) > 1 {
+		c.writeError(replyErrSyntax)
+		return nil
+	}
+	if len(args) == 1 {
+		mode := strings.ToUpper(args[0])
+		if (mode != "ASYNC") && (mode != "SYNC") {
+			c.writeError(replyErrSyntax)
+			return nil
+		}
+	}
+
+	err = c.server.flush(c.db)
+	if err != nil {
+		c.writeError(fmt.Sprintf(replyErrCommandFailed, err))
+		return nil
+	}
+
+	c.invalidateAll()
+	c.writeSimple("OK")
+
+	return nil
+}
+
+// DBSIZE
+//
+// Expired keys which have not been removed yet are counted.
+func (c *connection) dbSize(_ []string) (err error) {
+	size, _ := c.server.databases[c.db].GetSize()
+	c.writeInteger(int64(size))
+
+	return nil
+}
+
+// INFO [section [section ...]]
+//
+// Supported sections are server, clients, memory, stats and keyspace.
+func (c *connection) info(args []string) (err error) {
+	sections := map[string]bool{}
+	for _, arg := range args {
+		sections[strings.ToLower(arg)] = true
+	}
+	isAll := (len(sections) == 0) || sections["all"] || sections["default"] || sections["everything"]
+
+	s := c.server
+	var sb strings.Builder
+	addSection := func(name string, lines ...string) {
+		if !isAll && !sections[strings.ToLower(name)] {
+			return
+		}
+		if sb.Len() > 0 {
+			sb.WriteString(crlf)
+		}
+
+		sb.WriteString("# " + name + crlf)
+		for _, line := range lines {
+			sb.WriteString(line + crlf)
+		}
+	}
+
+	var usedMemory, maxMemory int
+	var evictedKeys uint64
+	var keyspace []string
+	for i, db := range s.databases {
+		volume, volumeLimit := db.GetVolume()
+		usedMemory += volume
+		maxMemory += volumeLimit
+
+		for reason, count := range db.GetStats().Evictions {
+			if reason != vl.EvictionReasonExpired {
+				evictedKeys += count
+			}
+		}
+
+		size, _ := db.GetSize()
+		if size > 0 {
+			keyspace = append(keyspace, fmt.Sprintf("db%d:keys=%d", i, size))
+		}
+	}
+
+	addSection("Server",
+		"redis_version:"+Version,
+		"redis_mode:standalone",
+		fmt.Sprintf("process_id:%d", os.Getpid()),
+		fmt.Sprintf("uptime_in_seconds:%d", int64(s.now().Sub(s.startTime)/time.Second)),
+	)
+	addSection("Clients",
+		fmt.Sprintf("connected_clients:%d", s.counters.connections.Load()),
+	)
+	addSection("Memory",
+		fmt.Sprintf("used_memory:%d", usedMemory),
+		fmt.Sprintf("maxmemory:%d", maxMemory),
+	)
+	addSection("Stats",
+		fmt.Sprintf("total_connections_received:%d", s.counters.totalConnections.Load()),
+		fmt.Sprintf("total_commands_processed:%d", s.counters.totalCommands.Load()),
+		fmt.Sprintf("keyspace_hits:%d", s.counters.keyspaceHits.Load()),
+		fmt.Sprintf("keyspace_misses:%d", s.counters.keyspaceMisses.Load()),
+		fmt.Sprintf("expired_keys:%d", s.counters.expiredKeys.Load()),
+		fmt.Sprintf("evicted_keys:%d", evictedKeys),
+	)
+	addSection("Keyspace", keyspace...)
+
+	c.writeVerbatim(sb.String())
+
+	return nil
+}
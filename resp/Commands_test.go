@@ -0,0 +1,261 @@
+package resp
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_connection_connectionCommands(t *testing.T) {
+	aTest := tester.New(t)
+
+	_, client, _ := _test_start(t, 2, vl.Config[string, []byte]{}, Settings{})
+
+	// Test #1. PING.
+	aTest.MustBeEqual(_test_do(t, client, "PING"), "PONG")
+	aTest.MustBeEqual(_test_do(t, client, "ping", "hi"), "hi")
+	aTest.MustBeEqual(_test_error(t, client, "PING", "a", "b"), "ERR wrong number of arguments for 'ping' command")
+
+	// Test #2. SELECT.
+	aTest.MustBeEqual(_test_do(t, client, "SET", "a", "0"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "SELECT", "1"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "GET", "a"), nil)
+	aTest.MustBeEqual(_test_do(t, client, "SET", "a", "1"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "SELECT", "0"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "GET", "a"), "0")
+	aTest.MustBeEqual(_test_error(t, client, "SELECT", "2"), replyErrDbIndex)
+	aTest.MustBeEqual(_test_error(t, client, "SELECT", "x"), replyErrNotInteger)
+
+	// Test #3. HELLO.
+	reply := _test_do(t, client, "HELLO", "3")
+	aTest.MustBeEqual(reply.(map[string]any)["proto"], int64(3))
+	aTest.MustBeEqual(reply.(map[string]any)["version"], Version)
+	reply = _test_do(t, client, "HELLO", "2")
+	aTest.MustBeEqual(len(reply.([]any)), 12)
+	aTest.MustBeEqual(_test_error(t, client, "HELLO", "4"), replyErrNoProto)
+	aTest.MustBeEqual(_test_error(t, client, "HELLO", "3", "AUTH"), replyErrSyntax)
+
+	// Test #4. COMMAND.
+	aTest.MustBeEqual(_test_do(t, client, "COMMAND", "DOCS"), []any{})
+
+	// Test #5. QUIT.
+	aTest.MustBeEqual(_test_do(t, client, "QUIT"), "OK")
+	_, err := client.Do("PING")
+	aTest.MustBeAnError(err)
+}
+
+func Test_connection_client(t *testing.T) {
+	aTest := tester.New(t)
+
+	s, tracker, _ := _test_start(t, 2, vl.Config[string, []byte]{}, Settings{})
+	client := _test_dial_server(t, s)
+
+	// Test #1. CLIENT ID.
+	trackerId := _test_do(t, tracker, "CLIENT", "ID")
+	aTest.MustBeDifferent(trackerId, _test_do(t, client, "CLIENT", "ID"))
+	aTest.MustBeEqual(_test_error(t, client, "CLIENT", "ID", "x"), replyErrSyntax)
+	aTest.MustBeEqual(_test_error(t, client, "CLIENT", "KILL"), "ERR unknown subcommand 'KILL'")
+
+	// Test #2. Unsupported tracking.
+	aTest.MustBeEqual(_test_error(t, tracker, "CLIENT", "TRACKING", "ON", "BCAST"), replyErrTrackingProto)
+	_test_do(t, tracker, "HELLO", "3")
+	aTest.MustBeEqual(_test_error(t, tracker, "CLIENT", "TRACKING", "ON"), replyErrTrackingMode)
+	aTest.MustBeEqual(_test_error(t, tracker, "CLIENT", "TRACKING", "ON", "PREFIX"), replyErrSyntax)
+	aTest.MustBeEqual(_test_error(t, tracker, "CLIENT", "TRACKING"), replyErrSyntax)
+
+	// Test #3. Modifications of other connections.
+	aTest.MustBeEqual(_test_do(t, tracker, "CLIENT", "TRACKING", "ON", "BCAST"), "OK")
+	_test_do(t, client, "SET", "a", "1")
+	aTest.MustBeEqual(_test_receive(t, tracker), []any{"invalidate", []any{"a"}})
+	_test_do(t, client, "DEL", "x", "a")
+	aTest.MustBeEqual(_test_receive(t, tracker), []any{"invalidate", []any{"a"}})
+	_test_do(t, client, "SELECT", "1")
+	_test_do(t, client, "SET", "b", "1")
+	aTest.MustBeEqual(_test_receive(t, tracker), []any{"invalidate", []any{"b"}})
+	_test_do(t, client, "EXPIRE", "b", "10")
+	aTest.MustBeEqual(_test_receive(t, tracker), []any{"invalidate", []any{"b"}})
+	_test_do(t, client, "FLUSHDB")
+	aTest.MustBeEqual(_test_receive(t, tracker), []any{"invalidate", nil})
+
+	// Test #4. Own modifications.
+	aTest.MustBeEqual(_test_do(t, tracker, "SET", "c", "1"), "OK")
+	aTest.MustBeEqual(_test_receive(t, tracker), []any{"invalidate", []any{"c"}})
+
+	// Test #5. Own modifications are skipped with NOLOOP.
+	aTest.MustBeEqual(_test_do(t, tracker, "CLIENT", "TRACKING", "ON", "BCAST", "NOLOOP"), "OK")
+	_test_do(t, tracker, "SET", "d", "1")
+	_test_do(t, client, "SET", "e", "1")
+	aTest.MustBeEqual(_test_receive(t, tracker), []any{"invalidate", []any{"e"}})
+
+	// Test #6. Tracking is stopped.
+	aTest.MustBeEqual(_test_do(t, tracker, "CLIENT", "TRACKING", "OFF"), "OK")
+	_test_do(t, client, "SET", "f", "1")
+	s.trackLock.Lock()
+	aTest.MustBeEqual(len(s.trackers), 0)
+	s.trackLock.Unlock()
+}
+
+func Test_connection_slowTracker(t *testing.T) {
+	aTest := tester.New(t)
+
+	s, client, _ := _test_start(t, 1, vl.Config[string, []byte]{}, Settings{WriteTimeout: time.Millisecond * 100, PushQueueSize: 1})
+	tracker := _test_dial_server(t, s)
+	_test_do(t, tracker, "HELLO", "3")
+	aTest.MustBeEqual(_test_do(t, tracker, "CLIENT", "TRACKING", "ON", "BCAST"), "OK")
+
+	// Test #1. A tracker which does not read its messages does not block
+	// modifications of keys.
+	key := strings.Repeat("k", 64*1024)
+	for i := 0; i < 256; i++ {
+		aTest.MustBeEqual(_test_do(t, client, "SET", key+strconv.Itoa(i), "1"), "OK")
+	}
+
+	// Test #2. The tracker is disconnected.
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		s.trackLock.Lock()
+		trackersCount := len(s.trackers)
+		s.trackLock.Unlock()
+
+		if trackersCount == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("tracker is not disconnected")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+}
+
+func Test_connection_keyCommands(t *testing.T) {
+	aTest := tester.New(t)
+
+	s, client, clock := _test_start(t, 1, vl.Config[string, []byte]{RecordTtl: 1000}, Settings{})
+
+	// Test #1. SET and GET.
+	aTest.MustBeEqual(_test_do(t, client, "SET", "a", "1"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "GET", "a"), "1")
+	aTest.MustBeEqual(_test_do(t, client, "GET", "b"), nil)
+	aTest.MustBeEqual(_test_do(t, client, "SET", "b", ""), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "GET", "b"), "")
+
+	// Test #2. EXISTS and DEL.
+	aTest.MustBeEqual(_test_do(t, client, "EXISTS", "a", "b", "c", "a"), int64(3))
+	aTest.MustBeEqual(_test_do(t, client, "DEL", "a", "c"), int64(1))
+	aTest.MustBeEqual(_test_do(t, client, "EXISTS", "a"), int64(0))
+
+	// Test #3. SET with an expiration time.
+	aTest.MustBeEqual(_test_do(t, client, "SET", "a", "1", "EX", "10"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "TTL", "a"), int64(10))
+	aTest.MustBeEqual(_test_do(t, client, "PTTL", "a"), int64(10_000))
+	clock.Add(time.Millisecond * 9_600)
+	aTest.MustBeEqual(_test_do(t, client, "TTL", "a"), int64(0))
+	aTest.MustBeEqual(_test_do(t, client, "PTTL", "a"), int64(400))
+	clock.Add(time.Millisecond * 400)
+	aTest.MustBeEqual(_test_do(t, client, "GET", "a"), nil)
+	aTest.MustBeEqual(_test_do(t, client, "SET", "a", "1", "px", "1500"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "TTL", "a"), int64(2))
+	clock.Add(time.Millisecond * 1500)
+	aTest.MustBeEqual(_test_do(t, client, "EXISTS", "a"), int64(0))
+
+	// Test #4. TTL of missing and persistent keys.
+	aTest.MustBeEqual(_test_do(t, client, "TTL", "a"), int64(-2))
+	aTest.MustBeEqual(_test_do(t, client, "TTL", "b"), int64(-1))
+	aTest.MustBeEqual(_test_do(t, client, "PTTL", "b"), int64(-1))
+
+	// Test #5. SET overwrites the expiration time.
+	aTest.MustBeEqual(_test_do(t, client, "SET", "a", "1", "EX", "10"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "SET", "a", "2"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "TTL", "a"), int64(-1))
+
+	// Test #6. Bad options of SET.
+	aTest.MustBeEqual(_test_error(t, client, "SET", "a", "1", "EX"), replyErrSyntax)
+	aTest.MustBeEqual(_test_error(t, client, "SET", "a", "1", "NX"), replyErrSyntax)
+	aTest.MustBeEqual(_test_error(t, client, "SET", "a", "1", "EX", "1", "PX", "1"), replyErrSyntax)
+	aTest.MustBeEqual(_test_error(t, client, "SET", "a", "1", "EX", "x"), replyErrNotInteger)
+	aTest.MustBeEqual(_test_error(t, client, "SET", "a", "1", "EX", "0"), "ERR invalid expire time in 'set' command")
+	aTest.MustBeEqual(_test_error(t, client, "SET", "a", "1", "EX", "9223372036854775807"), "ERR invalid expire time in 'set' command")
+	aTest.MustBeEqual(_test_error(t, client, "SET", "a", "1", "PX", "9223372036854775807"), "ERR invalid expire time in 'set' command")
+	aTest.MustBeEqual(_test_do(t, client, "TTL", "a"), int64(-1))
+
+	// Test #7. EXPIRE.
+	aTest.MustBeEqual(_test_do(t, client, "EXPIRE", "a", "20"), int64(1))
+	aTest.MustBeEqual(_test_do(t, client, "TTL", "a"), int64(20))
+	aTest.MustBeEqual(_test_do(t, client, "GET", "a"), "2")
+	aTest.MustBeEqual(_test_do(t, client, "EXPIRE", "c", "20"), int64(0))
+	aTest.MustBeEqual(_test_do(t, client, "EXPIRE", "a", "0"), int64(1))
+	aTest.MustBeEqual(_test_do(t, client, "EXISTS", "a"), int64(0))
+	aTest.MustBeEqual(_test_error(t, client, "EXPIRE", "a", "x"), replyErrNotInteger)
+	aTest.MustBeEqual(_test_do(t, client, "SET", "a", "1"), "OK")
+	aTest.MustBeEqual(_test_error(t, client, "EXPIRE", "a", "9223372036854775807"), "ERR invalid expire time in 'expire' command")
+	aTest.MustBeEqual(_test_do(t, client, "TTL", "a"), int64(-1))
+	aTest.MustBeEqual(_test_do(t, client, "EXPIRE", "a", "-9223372036854775808"), int64(1))
+	aTest.MustBeEqual(_test_do(t, client, "EXISTS", "a"), int64(0))
+
+	// Test #8. EXISTS, TTL and PTTL do not touch keys.
+	aTest.MustBeEqual(_test_do(t, client, "SET", "a", "1"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "SET", "c", "1"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "EXISTS", "a"), int64(1))
+	aTest.MustBeEqual(_test_do(t, client, "TTL", "a"), int64(-1))
+	aTest.MustBeEqual(_test_do(t, client, "PTTL", "a"), int64(-1))
+	aTest.MustBeEqual(s.databases[0].PeekTopUids(2), []string{"c", "a"})
+
+	// Test #9. TTL of the cache.
+	clock.Add(time.Second * 1000)
+	aTest.MustBeEqual(_test_do(t, client, "GET", "a"), nil)
+}
+
+func Test_connection_dbCommands(t *testing.T) {
+	aTest := tester.New(t)
+
+	_, client, _ := _test_start(t, 2, vl.Config[string, []byte]{VolumeLimit: 100}, Settings{})
+
+	// Test #1. DBSIZE.
+	aTest.MustBeEqual(_test_do(t, client, "DBSIZE"), int64(0))
+	aTest.MustBeEqual(_test_do(t, client, "SET", "a", "1"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "SET", "b", "1"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "DBSIZE"), int64(2))
+
+	// Test #2. Too big value.
+	aTest.MustBeEqual(_test_error(t, client, "SET", "c", strings.Repeat("x", 100)), replyErrOutOfMemory)
+
+	// Test #3. FLUSHDB flushes the selected database only.
+	aTest.MustBeEqual(_test_do(t, client, "SELECT", "1"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "SET", "a", "1"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "SELECT", "0"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "FLUSHDB", "SYNC"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "DBSIZE"), int64(0))
+	aTest.MustBeEqual(_test_do(t, client, "SELECT", "1"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "DBSIZE"), int64(1))
+	aTest.MustBeEqual(_test_error(t, client, "FLUSHDB", "LATER"), replyErrSyntax)
+	aTest.MustBeEqual(_test_do(t, client, "FLUSHDB"), "OK")
+}
+
+func Test_connection_info(t *testing.T) {
+	aTest := tester.New(t)
+
+	_, client, _ := _test_start(t, 2, vl.Config[string, []byte]{VolumeLimit: 1000}, Settings{})
+	aTest.MustBeEqual(_test_do(t, client, "SET", "a", "12"), "OK")
+	aTest.MustBeEqual(_test_do(t, client, "GET", "a"), "12")
+	aTest.MustBeEqual(_test_do(t, client, "GET", "b"), nil)
+
+	// Test #1. All sections.
+	info := _test_do(t, client, "INFO").(string)
+	for _, line := range []string{
+		"# Server", "redis_version:" + Version, "uptime_in_seconds:0",
+		"# Clients", "connected_clients:1",
+		"# Memory", "used_memory:10", "maxmemory:2000",
+		"# Stats", "keyspace_hits:1", "keyspace_misses:1", "evicted_keys:0",
+		"# Keyspace", "db0:keys=1",
+	} {
+		aTest.MustBeEqual(strings.Contains(info, line+"\r\n"), true)
+	}
+
+	// Test #2. Selected sections.
+	info = _test_do(t, client, "INFO", "memory", "KEYSPACE").(string)
+	aTest.MustBeEqual(info, "# Memory\r\nused_memory:10\r\nmaxmemory:2000\r\n\r\n# Keyspace\r\ndb0:keys=1\r\n")
+}
@@ -0,0 +1,339 @@
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Protocol limits.
+const (
+	maxLineLength  = 64 * 1024
+	maxArrayLength = 1024 * 1024
+)
+
+// Protocol versions.
+const (
+	protocolVersion2 = 2
+	protocolVersion3 = 3
+)
+
+const crlf = "\r\n"
+
+// errQuit is returned by a handler of the QUIT command.
+var errQuit = errors.New("quit")
+
+// protocolError is a violation of the protocol by the client. It breaks the
+// connection.
+type protocolError struct {
+	msg string
+}
+
+func (pe protocolError) Error() string {
+	return fmt.Sprintf(ErrProtocolIsBroken, pe.msg)
+}
+
+// connection is a client's connection to the server.
+type connection struct {
+	server *Server
+	conn   net.Conn
+	r      *bufio.Reader
+	w      *bufio.Writer
+
+	// db is an index of the selected database.
+	db int
+
+	// protocolVersion is a version of the protocol of replies.
+	protocolVersion int
+
+	// id is a unique identifier of the connection.
+	id int64
+
+	// wLock guards the writer, as invalidation messages are pushed to a
+	// tracking connection by its tracker.
+	wLock *sync.Mutex
+
+	// invalidatedKeys are keys modified by the current command. Clients
+	// tracking keys are notified after the command is executed, so that a
+	// connection never holds locks of other connections.
+	invalidatedKeys []string
+	isFlushed       bool
+}
+
+func newConnection(server *Server, conn net.Conn) (c *connection) {
+	return &connection{
+		server:          server,
+		conn:            conn,
+		r:               bufio.NewReader(conn),
+		w:               bufio.NewWriter(conn),
+		protocolVersion: protocolVersion2,
+		id:              server.nextConnectionId.Add(1),
+		wLock:           new(sync.Mutex),
+	}
+}
+
+// serve executes commands of the client until the connection is closed.
+// Replies are flushed when there are no more pipelined commands.
+func (c *connection) serve() (err error) {
+	defer c.server.stopTracking(c)
+
+	for {
+		var args []string
+		args, err = c.readCommand()
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			var pe protocolError
+			if errors.As(err, &pe) {
+				c.wLock.Lock()
+				c.setWriteDeadline()
+				c.writeError("ERR Protocol error: " + pe.msg)
+				_ = c.w.Flush()
+				c.wLock.Unlock()
+			}
+			return err
+		}
+
+		c.wLock.Lock()
+		c.setWriteDeadline()
+		if len(args) > 0 {
+			err = c.execute(args)
+		}
+		if (err == nil) && (c.r.Buffered() == 0) {
+			err = c.w.Flush()
+		}
+		if err == errQuit {
+			err = c.w.Flush()
+			if err == nil {
+				err = errQuit
+			}
+		}
+		c.wLock.Unlock()
+
+		c.notifyTrackers()
+
+		if err != nil {
+			if err == errQuit {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// invalidate registers a key modified by the current command.
+func (c *connection) invalidate(key string) {
+	c.invalidatedKeys = append(c.invalidatedKeys, key)
+}
+
+// invalidateAll registers flushing of a database by the current command.
+func (c *connection) invalidateAll() {
+	c.isFlushed = true
+}
+
+// notifyTrackers sends invalidation messages of the current command to
+// clients tracking keys.
+func (c *connection) notifyTrackers() {
+	if c.isFlushed {
+		c.server.broadcastInvalidation(c, nil)
+	} else if len(c.invalidatedKeys) > 0 {
+		c.server.broadcastInvalidation(c, c.invalidatedKeys)
+	}
+
+	c.invalidatedKeys = nil
+	c.isFlushed = false
+}
+
+// setWriteDeadline limits the time of writing to the connection by the write
+// timeout. It is set before anything is written, as the buffered writer
+// flushes itself when its buffer is full. The lock of the writer must be
+// held.
+func (c *connection) setWriteDeadline() {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.server.settings.WriteTimeout))
+}
+
+// pushInvalidation writes an invalidation message of the keys to a tracking
+// connection. Nil keys invalidate all the keys.
+func (c *connection) pushInvalidation(keys []string) (err error) {
+	c.wLock.Lock()
+	defer c.wLock.Unlock()
+
+	c.setWriteDeadline()
+	_, _ = c.w.WriteString(">2" + crlf)
+	c.writeBulk([]byte("invalidate"))
+	if keys == nil {
+		c.writeNull()
+	} else {
+		c.writeArrayHeader(len(keys))
+		for _, key := range keys {
+			c.writeBulk([]byte(key))
+		}
+	}
+
+	return c.w.Flush()
+}
+
+// readCommand reads a command as an array of bulk strings or as an inline
+// command, i.e. a line of words separated by spaces. An empty command is
+// returned for an empty array or an empty line.
+func (c *connection) readCommand() (args []string, err error) {
+	var b byte
+	b, err = c.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if b != '*' {
+		err = c.r.UnreadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		var line string
+		line, err = c.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		return strings.Fields(line), nil
+	}
+
+	var line string
+	line, err = c.readLine()
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := strconv.Atoi(line)
+	if (err != nil) || (count > maxArrayLength) {
+		return nil, protocolError{msg: "invalid multibulk length"}
+	}
+
+	args = make([]string, 0, max(count, 0))
+	for i := 0; i < count; i++ {
+		line, err = c.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if (len(line) == 0) || (line[0] != '$') {
+			return nil, protocolError{msg: fmt.Sprintf("expected '$', got '%.1s'", line)}
+		}
+
+		size, sizeErr := strconv.Atoi(line[1:])
+		if (sizeErr != nil) || (size < 0) || (size > c.server.settings.MaxBulkSize) {
+			return nil, protocolError{msg: "invalid bulk length"}
+		}
+
+		buf := make([]byte, size+len(crlf))
+		_, err = io.ReadFull(c.r, buf)
+		if err != nil {
+			return nil, err
+		}
+		if string(buf[size:]) != crlf {
+			return nil, protocolError{msg: "bulk string is not terminated"}
+		}
+
+		args = append(args, string(buf[:size]))
+	}
+
+	return args, nil
+}
+
+// readLine reads a line without its line terminator.
+func (c *connection) readLine() (line string, err error) {
+	var buf []byte
+	for {
+		var chunk []byte
+		var isPrefix bool
+		chunk, isPrefix, err = c.r.ReadLine()
+		if err != nil {
+			return "", err
+		}
+
+		buf = append(buf, chunk...)
+		if len(buf) > maxLineLength {
+			return "", protocolError{msg: "too big inline request"}
+		}
+		if !isPrefix {
+			return string(buf), nil
+		}
+	}
+}
+
+// execute executes a command. Errors of the command are replied to the
+// client, a returned error breaks the connection.
+func (c *connection) execute(args []string) (err error) {
+	c.server.counters.totalCommands.Add(1)
+
+	name := strings.ToLower(args[0])
+	cmd, cmdExists := commands[name]
+	if !cmdExists {
+		c.writeError(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+		return nil
+	}
+
+	if ((cmd.arity > 0) && (len(args) != cmd.arity)) || ((cmd.arity < 0) && (len(args) < -cmd.arity)) {
+		c.writeError(fmt.Sprintf("ERR wrong number of arguments for '%s' command", name))
+		return nil
+	}
+
+	return cmd.handler(c, args[1:])
+}
+
+func (c *connection) writeSimple(s string) {
+	_, _ = c.w.WriteString("+" + s + crlf)
+}
+
+func (c *connection) writeError(s string) {
+	_, _ = c.w.WriteString("-" + s + crlf)
+}
+
+func (c *connection) writeInteger(n int64) {
+	_, _ = c.w.WriteString(":" + strconv.FormatInt(n, 10) + crlf)
+}
+
+func (c *connection) writeBulk(b []byte) {
+	_, _ = c.w.WriteString("$" + strconv.Itoa(len(b)) + crlf)
+	_, _ = c.w.Write(b)
+	_, _ = c.w.WriteString(crlf)
+}
+
+func (c *connection) writeNull() {
+	if c.protocolVersion == protocolVersion3 {
+		_, _ = c.w.WriteString("_" + crlf)
+	} else {
+		_, _ = c.w.WriteString("$-1" + crlf)
+	}
+}
+
+func (c *connection) writeArrayHeader(n int) {
+	_, _ = c.w.WriteString("*" + strconv.Itoa(n) + crlf)
+}
+
+// writeMapHeader starts a map of the number of pairs. Maps of RESP2 are flat
+// arrays of keys and values.
+func (c *connection) writeMapHeader(n int) {
+	if c.protocolVersion == protocolVersion3 {
+		_, _ = c.w.WriteString("%" + strconv.Itoa(n) + crlf)
+	} else {
+		c.writeArrayHeader(n * 2)
+	}
+}
+
+// writeVerbatim writes a verbatim text string of RESP3 or a bulk string of
+// RESP2.
+func (c *connection) writeVerbatim(text string) {
+	if c.protocolVersion != protocolVersion3 {
+		c.writeBulk([]byte(text))
+		return
+	}
+
+	_, _ = c.w.WriteString("=" + strconv.Itoa(len(text)+4) + crlf + "txt:" + text + crlf)
+}
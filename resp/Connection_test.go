@@ -0,0 +1,117 @@
+package resp
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+// _test_raw sends raw text to the server and reads the number of lines of
+// the reply joined with the '|' character.
+func _test_raw(t *testing.T, conn net.Conn, r *bufio.Reader, text string, n int) (reply string) {
+	_, err := conn.Write([]byte(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second * 5))
+
+	var lines []string
+	for i := 0; i < n; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\r\n"))
+	}
+
+	return strings.Join(lines, "|")
+}
+
+func Test_connection_protocol(t *testing.T) {
+	aTest := tester.New(t)
+
+	_, client, _ := _test_start(t, 1, vl.Config[string, []byte]{}, Settings{})
+	conn, err := net.Dial("tcp", client.conn.RemoteAddr().String())
+	aTest.MustBeNoError(err)
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+
+	// Test #1. Inline commands.
+	aTest.MustBeEqual(_test_raw(t, conn, r, "SET a 1\r\nGET a\r\n", 3), "+OK|$1|1")
+	aTest.MustBeEqual(_test_raw(t, conn, r, "\r\nPING\n", 1), "+PONG")
+
+	// Test #2. Pipelined arrays.
+	aTest.MustBeEqual(_test_raw(t, conn, r, "*1\r\n$4\r\nPING\r\n*2\r\n$3\r\nGET\r\n$1\r\nb\r\n*0\r\n", 2), "+PONG|$-1")
+
+	// Test #3. Binary-safe values.
+	aTest.MustBeEqual(_test_raw(t, conn, r, "*3\r\n$3\r\nSET\r\n$1\r\nb\r\n$4\r\nx\r\ny\r\n", 1), "+OK")
+	aTest.MustBeEqual(_test_raw(t, conn, r, "*2\r\n$3\r\nGET\r\n$1\r\nb\r\n", 3), "$4|x|y")
+
+	// Test #4. Protocol error breaks the connection.
+	aTest.MustBeEqual(_test_raw(t, conn, r, "*1\r\n:1\r\n", 1), "-ERR Protocol error: expected '$', got ':'")
+	_, err = r.ReadByte()
+	aTest.MustBeEqual(err, io.EOF)
+}
+
+func Test_connection_protocolErrors(t *testing.T) {
+	aTest := tester.New(t)
+
+	_, client, _ := _test_start(t, 1, vl.Config[string, []byte]{}, Settings{})
+	address := client.conn.RemoteAddr().String()
+
+	// Test.
+	for _, tc := range []struct {
+		request string
+		reply   string
+	}{
+		{"*x\r\n", "-ERR Protocol error: invalid multibulk length"},
+		{"*1\r\n$-1\r\n", "-ERR Protocol error: invalid bulk length"},
+		{"*1\r\n$1\r\nab\r\n", "-ERR Protocol error: bulk string is not terminated"},
+		{strings.Repeat("x", maxLineLength+1) + "\r\n", "-ERR Protocol error: too big inline request"},
+	} {
+		conn, err := net.Dial("tcp", address)
+		aTest.MustBeNoError(err)
+		r := bufio.NewReader(conn)
+		aTest.MustBeEqual(_test_raw(t, conn, r, tc.request, 1), tc.reply)
+		_ = conn.Close()
+	}
+}
+
+func Test_connection_resp3(t *testing.T) {
+	aTest := tester.New(t)
+
+	_, client, _ := _test_start(t, 1, vl.Config[string, []byte]{}, Settings{})
+	conn, err := net.Dial("tcp", client.conn.RemoteAddr().String())
+	aTest.MustBeNoError(err)
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+
+	// Test #1. RESP2.
+	aTest.MustBeEqual(_test_raw(t, conn, r, "HELLO\r\n", 3), "*12|$6|server")
+	_, _ = r.Discard(r.Buffered())
+	aTest.MustBeEqual(_test_raw(t, conn, r, "GET a\r\n", 1), "$-1")
+
+	// Test #2. RESP3.
+	aTest.MustBeEqual(_test_raw(t, conn, r, "HELLO 3\r\n", 3), "%6|$6|server")
+	_, _ = r.Discard(r.Buffered())
+	aTest.MustBeEqual(_test_raw(t, conn, r, "GET a\r\n", 1), "_")
+	aTest.MustBeEqual(_test_raw(t, conn, r, "INFO clients\r\n", 4), "=36|txt:# Clients|connected_clients:2|")
+}
+
+func Test_connection_commandErrors(t *testing.T) {
+	aTest := tester.New(t)
+
+	_, client, _ := _test_start(t, 1, vl.Config[string, []byte]{}, Settings{})
+
+	// Test.
+	aTest.MustBeEqual(_test_error(t, client, "FOO"), "ERR unknown command 'FOO'")
+	aTest.MustBeEqual(_test_error(t, client, "GET"), "ERR wrong number of arguments for 'get' command")
+	aTest.MustBeEqual(_test_error(t, client, "GET", "a", "b"), "ERR wrong number of arguments for 'get' command")
+	aTest.MustBeEqual(_test_error(t, client, "DBSIZE", "x"), "ERR wrong number of arguments for 'dbsize' command")
+}
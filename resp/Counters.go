@@ -0,0 +1,13 @@
+package resp
+
+import "sync/atomic"
+
+// counters contains statistics of the server reported by the INFO command.
+type counters struct {
+	connections      atomic.Uint64
+	totalConnections atomic.Uint64
+	totalCommands    atomic.Uint64
+	keyspaceHits     atomic.Uint64
+	keyspaceMisses   atomic.Uint64
+	expiredKeys      atomic.Uint64
+}
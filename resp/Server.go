@@ -0,0 +1,365 @@
+// Package resp serves caches over the Redis serialization protocol, RESP2 and
+// RESP3, so that tools which speak Redis may use the caches. Supported
+// commands are GET, SET with the EX and PX options, DEL, EXISTS, TTL, PTTL,
+// EXPIRE, FLUSHDB, DBSIZE and INFO, as well as connection commands PING,
+// SELECT, HELLO, CLIENT, COMMAND and QUIT.
+//
+// Clients using RESP3 may track keys in the broadcasting mode of the CLIENT
+// TRACKING command, so that modifications of keys are pushed to them as
+// invalidation messages. It allows clients to keep local copies of keys. A
+// client which does not read its messages is disconnected.
+package resp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/internal/itemcodec"
+)
+
+// Server is a RESP server of caches. Each cache is a logical database
+// selected by its index, keys are stored as records with the UID equal to the
+// key. Expiration times of keys are checked in addition to the TTL of the
+// cache, so a key lives until its own expiration time or until its record is
+// removed by the cache, whichever is earlier.
+type Server struct {
+	databases []*vl.Cache[string, []byte]
+	settings  Settings
+	startTime time.Time
+	counters  *counters
+
+	// lock makes composite operations over keys atomic, e.g. reading a key
+	// and changing its expiration time.
+	lock *sync.Mutex
+
+	// connLock guards listeners and connections.
+	connLock  *sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	isClosed  bool
+	wg        *sync.WaitGroup
+
+	nextConnectionId atomic.Int64
+
+	// trackLock guards trackers of connections tracking keys.
+	trackLock *sync.Mutex
+	trackers  map[*connection]*tracker
+}
+
+// NewServer creates a server of the caches. The first cache is the database
+// selected by default.
+func NewServer(databases []*vl.Cache[string, []byte], settings Settings) (s *Server, err error) {
+	if len(databases) == 0 {
+		return nil, errors.New(ErrDatabasesAreNotSet)
+	}
+	for i, db := range databases {
+		if db == nil {
+			return nil, fmt.Errorf(ErrDatabaseIsNil, i)
+		}
+	}
+
+	err = settings.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Server{
+		databases: databases,
+		settings:  settings.withDefaults(),
+		counters:  new(counters),
+		lock:      new(sync.Mutex),
+		connLock:  new(sync.Mutex),
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+		wg:        new(sync.WaitGroup),
+		trackLock: new(sync.Mutex),
+		trackers:  make(map[*connection]*tracker),
+	}
+	s.startTime = s.now()
+
+	return s, nil
+}
+
+func (s *Server) now() time.Time {
+	if s.settings.Clock == nil {
+		return time.Now()
+	}
+
+	return s.settings.Clock.Now()
+}
+
+// Serve accepts connections of the listener and serves them until the server
+// is closed. The listener is closed by the server.
+func (s *Server) Serve(l net.Listener) (err error) {
+	s.connLock.Lock()
+	if s.isClosed {
+		s.connLock.Unlock()
+		_ = l.Close()
+		return errors.New(ErrServerIsClosed)
+	}
+	s.listeners[l] = struct{}{}
+	s.connLock.Unlock()
+
+	for {
+		var conn net.Conn
+		conn, err = l.Accept()
+		if err != nil {
+			s.connLock.Lock()
+			isClosed := s.isClosed
+			s.connLock.Unlock()
+
+			if isClosed {
+				return errors.New(ErrServerIsClosed)
+			}
+
+			return err
+		}
+
+		s.connLock.Lock()
+		if s.isClosed {
+			s.connLock.Unlock()
+			_ = conn.Close()
+			return errors.New(ErrServerIsClosed)
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.connLock.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+// ListenAndServe listens on the TCP address and serves connections until the
+// server is closed.
+func (s *Server) ListenAndServe(address string) (err error) {
+	var l net.Listener
+	l, err = net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(l)
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.connLock.Lock()
+		delete(s.conns, conn)
+		s.connLock.Unlock()
+
+		_ = conn.Close()
+	}()
+
+	s.counters.connections.Add(1)
+	s.counters.totalConnections.Add(1)
+	defer s.counters.connections.Add(^uint64(0))
+
+	err := newConnection(s, conn).serve()
+	if err != nil {
+		s.reportError(err)
+	}
+}
+
+func (s *Server) reportError(err error) {
+	if s.settings.OnError != nil {
+		s.settings.OnError(err)
+	}
+}
+
+// Close stops listening, closes all the connections and waits for them to be
+// finished. The caches are not closed.
+func (s *Server) Close() (err error) {
+	s.connLock.Lock()
+	if s.isClosed {
+		s.connLock.Unlock()
+		return nil
+	}
+	s.isClosed = true
+
+	var errs []error
+	for l := range s.listeners {
+		errs = append(errs, l.Close())
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.connLock.Unlock()
+
+	s.wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (s *Server) nowMs() int64 {
+	return s.now().UnixMilli()
+}
+
+// getItem returns an alive item of the database. An expired item is removed.
+func (s *Server) getItem(db int, key string) (it itemcodec.Item, found bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.getItemUnsafe(db, key)
+}
+
+// peekItem is a variant of the getItem method which, like the RecordExists
+// method of the cache, does not move the record and does not prolong its
+// life.
+func (s *Server) peekItem(db int, key string) (it itemcodec.Item, found bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.readItemUnsafe(db, key, s.databases[db].PeekRecord)
+}
+
+func (s *Server) getItemUnsafe(db int, key string) (it itemcodec.Item, found bool) {
+	return s.readItemUnsafe(db, key, s.databases[db].GetRecord)
+}
+
+// readItemUnsafe reads an alive item using the read function of the cache.
+func (s *Server) readItemUnsafe(db int, key string, read func(uid string) ([]byte, error)) (it itemcodec.Item, found bool) {
+	c := s.databases[db]
+
+	buf, err := read(key)
+	if err != nil {
+		return it, false
+	}
+
+	it, err = itemcodec.Decode(buf)
+	if err != nil {
+		c.RemoveRecord(key)
+		return it, false
+	}
+
+	if !it.IsAlive(s.nowMs()) {
+		c.RemoveRecord(key)
+		s.counters.expiredKeys.Add(1)
+		return it, false
+	}
+
+	return it, true
+}
+
+// setItem stores the value with its expiration time in milliseconds.
+func (s *Server) setItem(db int, key string, value []byte, expiresAt int64) (err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.databases[db].AddRecord(key, itemcodec.Item{ExpiresAt: expiresAt, Data: value}.Encode())
+}
+
+// setExpiration changes the expiration time of a key. A key with an
+// expiration time in the past is removed.
+func (s *Server) setExpiration(db int, key string, expiresAt int64) (found bool, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var it itemcodec.Item
+	it, found = s.getItemUnsafe(db, key)
+	if !found {
+		return false, nil
+	}
+
+	it.ExpiresAt = expiresAt
+	if !it.IsAlive(s.nowMs()) {
+		s.databases[db].RemoveRecord(key)
+		return true, nil
+	}
+
+	return true, s.databases[db].AddRecord(key, it.Encode())
+}
+
+// deleteKey removes an alive key.
+func (s *Server) deleteKey(db int, key string) (found bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, found = s.getItemUnsafe(db, key)
+	if found {
+		s.databases[db].RemoveRecord(key)
+	}
+
+	return found
+}
+
+func (s *Server) flush(db int) (err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.databases[db].Clear()
+}
+
+// startTracking makes the connection receive invalidation messages of all the
+// keys. Tracking again only changes the noLoop option.
+func (s *Server) startTracking(c *connection, noLoop bool) {
+	s.trackLock.Lock()
+	defer s.trackLock.Unlock()
+
+	t, isTracking := s.trackers[c]
+	if isTracking {
+		t.noLoop = noLoop
+		return
+	}
+
+	t = newTracker(c, noLoop, s.settings.PushQueueSize)
+	s.trackers[c] = t
+
+	// The goroutine is added while the connection is being served, so the
+	// wait group can not be waited for with a zero counter.
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		t.run()
+	}()
+}
+
+// stopTracking stops pushing invalidation messages to the connection. It does
+// not wait for the pusher, which may be waiting for the writer of the
+// connection.
+func (s *Server) stopTracking(c *connection) {
+	s.trackLock.Lock()
+	defer s.trackLock.Unlock()
+
+	t, isTracking := s.trackers[c]
+	if !isTracking {
+		return
+	}
+
+	t.stop()
+	delete(s.trackers, c)
+}
+
+// broadcastInvalidation queues an invalidation message of the keys modified by
+// the origin connection to the connections tracking keys. Nil keys
+// invalidate all the keys. A connection whose queue is full does not read
+// its messages, it stops tracking and is closed, so that its client does not
+// keep stale keys.
+func (s *Server) broadcastInvalidation(origin *connection, keys []string) {
+	var errs []error
+
+	s.trackLock.Lock()
+	for c, t := range s.trackers {
+		if (c == origin) && t.noLoop {
+			continue
+		}
+
+		err := t.enqueue(keys)
+		if err != nil {
+			t.stop()
+			delete(s.trackers, c)
+			_ = c.conn.Close()
+			errs = append(errs, err)
+		}
+	}
+	s.trackLock.Unlock()
+
+	for _, err := range errs {
+		s.reportError(err)
+	}
+}
@@ -0,0 +1,59 @@
+package resp
+
+import (
+	"net"
+	"testing"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_NewServer(t *testing.T) {
+	aTest := tester.New(t)
+	var c = vl.NewCache[string, []byte](0, 0, 60)
+	var s *Server
+	var err error
+
+	// Test #1. Bad arguments.
+	_, err = NewServer(nil, Settings{})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrDatabasesAreNotSet)
+	_, err = NewServer([]*vl.Cache[string, []byte]{c, nil}, Settings{})
+	aTest.MustBeAnError(err)
+	_, err = NewServer([]*vl.Cache[string, []byte]{c}, Settings{MaxBulkSize: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrMaxBulkSizeIsNegative)
+	_, err = NewServer([]*vl.Cache[string, []byte]{c}, Settings{WriteTimeout: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrWriteTimeoutIsNegative)
+	_, err = NewServer([]*vl.Cache[string, []byte]{c}, Settings{PushQueueSize: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrPushQueueSizeIsNegative)
+
+	// Test #2. OK.
+	s, err = NewServer([]*vl.Cache[string, []byte]{c}, Settings{})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(s.settings.MaxBulkSize, DefaultMaxBulkSize)
+	aTest.MustBeEqual(s.settings.WriteTimeout, DefaultWriteTimeout)
+	aTest.MustBeEqual(s.settings.PushQueueSize, DefaultPushQueueSize)
+}
+
+func Test_Server_Close(t *testing.T) {
+	aTest := tester.New(t)
+
+	s, client, _ := _test_start(t, 1, vl.Config[string, []byte]{}, Settings{})
+	aTest.MustBeEqual(_test_do(t, client, "PING"), "PONG")
+
+	// Test #1. Connections are closed.
+	aTest.MustBeNoError(s.Close())
+	_, err := client.Do("PING")
+	aTest.MustBeAnError(err)
+	aTest.MustBeNoError(s.Close())
+
+	// Test #2. Closed server does not serve.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	aTest.MustBeNoError(err)
+	err = s.Serve(l)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrServerIsClosed)
+}
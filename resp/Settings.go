@@ -0,0 +1,78 @@
+package resp
+
+import (
+	"errors"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+// DefaultMaxBulkSize is the default maximum size of a bulk string of a
+// request.
+const DefaultMaxBulkSize = 64 * 1024 * 1024
+
+const (
+	// DefaultWriteTimeout is the default timeout of writing replies and
+	// invalidation messages to a client.
+	DefaultWriteTimeout = time.Second * 5
+
+	// DefaultPushQueueSize is the default maximum number of invalidation
+	// messages waiting to be pushed to a client tracking keys.
+	DefaultPushQueueSize = 1024
+)
+
+// Settings contains settings of the server.
+type Settings struct {
+	// MaxBulkSize is a maximum size of a bulk string of a request in bytes,
+	// i.e. of a key or a value. Requests with larger bulk strings break the
+	// connection. Zero selects the default size.
+	MaxBulkSize int
+
+	// WriteTimeout is a timeout of writing replies and invalidation messages
+	// to a client. A client which does not read them in time is
+	// disconnected. Zero selects the default timeout.
+	WriteTimeout time.Duration
+
+	// PushQueueSize is a maximum number of invalidation messages waiting to
+	// be pushed to a client tracking keys. A client whose queue overflows
+	// is disconnected. Zero selects the default size.
+	PushQueueSize int
+
+	// Clock is an optional source of current time used for expiration
+	// times of keys. It should be the clock of the caches.
+	Clock vl.Clock
+
+	// OnError is an optional callback which receives errors of connections,
+	// e.g. network errors and protocol errors.
+	OnError func(err error)
+}
+
+func (s Settings) validate() (err error) {
+	if s.MaxBulkSize < 0 {
+		return errors.New(ErrMaxBulkSizeIsNegative)
+	}
+	if s.WriteTimeout < 0 {
+		return errors.New(ErrWriteTimeoutIsNegative)
+	}
+	if s.PushQueueSize < 0 {
+		return errors.New(ErrPushQueueSizeIsNegative)
+	}
+
+	return nil
+}
+
+// withDefaults returns the settings where zero values are replaced by
+// default values.
+func (s Settings) withDefaults() Settings {
+	if s.MaxBulkSize == 0 {
+		s.MaxBulkSize = DefaultMaxBulkSize
+	}
+	if s.WriteTimeout == 0 {
+		s.WriteTimeout = DefaultWriteTimeout
+	}
+	if s.PushQueueSize == 0 {
+		s.PushQueueSize = DefaultPushQueueSize
+	}
+
+	return s
+}
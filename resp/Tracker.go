@@ -0,0 +1,66 @@
+package resp
+
+import (
+	"fmt"
+)
+
+// tracker pushes invalidation messages to a connection tracking keys.
+// Messages are queued by connections modifying keys and are written by a
+// goroutine of the tracker, so that a client which does not read its
+// messages does not delay modifications of keys.
+type tracker struct {
+	c *connection
+
+	// noLoop tells whether the connection is not notified of its own
+	// modifications. It is guarded by the tracking lock of the server.
+	noLoop bool
+
+	// queue contains keys of invalidation messages, nil keys invalidate all
+	// the keys.
+	queue chan []string
+
+	// done is closed when the tracker is stopped.
+	done chan struct{}
+}
+
+func newTracker(c *connection, noLoop bool, queueSize int) (t *tracker) {
+	return &tracker{
+		c:      c,
+		noLoop: noLoop,
+		queue:  make(chan []string, queueSize),
+		done:   make(chan struct{}),
+	}
+}
+
+// enqueue queues an invalidation message of the keys without waiting.
+func (t *tracker) enqueue(keys []string) (err error) {
+	select {
+	case t.queue <- keys:
+		return nil
+	default:
+		return fmt.Errorf(ErrPushQueueIsFull, t.c.id)
+	}
+}
+
+// run pushes queued messages until the tracker is stopped. A connection which
+// fails to receive a message is closed, its error is reported by its reader.
+func (t *tracker) run() {
+	for {
+		select {
+		case <-t.done:
+			return
+
+		case keys := <-t.queue:
+			err := t.c.pushInvalidation(keys)
+			if err != nil {
+				_ = t.c.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// stop stops the pusher. Queued messages are dropped.
+func (t *tracker) stop() {
+	close(t.done)
+}
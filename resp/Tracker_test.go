@@ -0,0 +1,24 @@
+package resp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_tracker_enqueue(t *testing.T) {
+	aTest := tester.New(t)
+
+	c := &connection{id: 7}
+	tr := newTracker(c, false, 1)
+
+	// Test #1. Message is queued.
+	aTest.MustBeNoError(tr.enqueue([]string{"a"}))
+
+	// Test #2. Overflow.
+	err := tr.enqueue(nil)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrPushQueueIsFull, 7))
+	aTest.MustBeEqual(<-tr.queue, []string{"a"})
+}
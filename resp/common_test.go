@@ -0,0 +1,111 @@
+package resp
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/cachetest"
+)
+
+// _test_start starts a server of new caches on a free local port and
+// connects a client to it. The clock of the caches is set to the server.
+func _test_start(t *testing.T, databasesCount int, cfg vl.Config[string, []byte], settings Settings) (s *Server, client *Client, clock *cachetest.Clock) {
+	clock = cachetest.NewClock(time.Unix(1_700_000_000, 0))
+	cfg.Clock = clock
+	if cfg.RecordTtl == 0 {
+		cfg.RecordTtl = 3600
+	}
+
+	var databases []*vl.Cache[string, []byte]
+	for i := 0; i < databasesCount; i++ {
+		c, err := vl.NewFromConfig(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		databases = append(databases, c)
+	}
+
+	settings.Clock = clock
+	s, err := NewServer(databases, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = s.Serve(l) }()
+	t.Cleanup(func() { _ = s.Close() })
+
+	return s, _test_dial(t, l.Addr().String()), clock
+}
+
+func _test_dial(t *testing.T, address string) (client *Client) {
+	client, err := Dial(address, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+// _test_do executes the command which must succeed.
+func _test_do(t *testing.T, client *Client, args ...string) (reply any) {
+	t.Helper()
+
+	reply, err := client.Do(args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return reply
+}
+
+// _test_error executes the command which must fail and returns the error.
+func _test_error(t *testing.T, client *Client, args ...string) (msg string) {
+	t.Helper()
+
+	_, err := client.Do(args...)
+	if err == nil {
+		t.Fatal("error is expected")
+	}
+	if _, isError := err.(Error); !isError {
+		t.Fatal(err)
+	}
+
+	return err.Error()
+}
+
+// _test_dial_server connects another client to the server.
+func _test_dial_server(t *testing.T, s *Server) (client *Client) {
+	s.connLock.Lock()
+	var address string
+	for l := range s.listeners {
+		address = l.Addr().String()
+	}
+	s.connLock.Unlock()
+
+	return _test_dial(t, address)
+}
+
+// _test_receive waits for a pushed message.
+func _test_receive(t *testing.T, client *Client) (message []any) {
+	t.Helper()
+
+	err := client.SetDeadline(time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.SetDeadline(time.Time{}) }()
+
+	message, err = client.Receive()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return message
+}
@@ -0,0 +1,16 @@
+package resp
+
+const (
+	ErrDatabasesAreNotSet      = "databases are not set"
+	ErrDatabaseIsNil           = "database is nil: %v"
+	ErrMaxBulkSizeIsNegative   = "max bulk size is negative"
+	ErrWriteTimeoutIsNegative  = "write timeout is negative"
+	ErrPushQueueSizeIsNegative = "push queue size is negative"
+	ErrPushQueueIsFull         = "push queue of connection %v is full, the connection is closed"
+	ErrServerIsClosed          = "server is closed"
+	ErrProtocolIsBroken        = "protocol error: %v"
+	ErrReplyTypeIsUnknown      = "reply type is unknown: %q"
+	ErrCommandIsNotSet         = "command is not set"
+	ErrIntegerIsBad            = "integer is bad: %q"
+	ErrLineTerminatorIsAbsent  = "line terminator is absent"
+)
@@ -0,0 +1,246 @@
+// Package rest provides an HTTP interface of a cache for operational tools.
+// Records are read, written and removed using the 'GET', 'PUT' and 'DELETE'
+// methods of the '/records/{uid}' resource, existence of a record is checked
+// using the 'HEAD' method. The cache is cleared by 'POST /clear', and its
+// statistics are returned by 'GET /stats'.
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/inspector"
+	"github.com/vault-thirteen/Cache/internal/itemcodec"
+)
+
+// TtlHeader is a header of a record's TTL in seconds. In a 'PUT' request it
+// overrides the TTL of the cache for the record. As the TTL of the cache is
+// still applied, an override can only shorten the life of a record. In a
+// response it contains the remaining TTL of a record having an override.
+const TtlHeader = "X-Cache-Ttl"
+
+// Handler is an HTTP handler of a cache. The handler may be mounted under a
+// prefix using the http.StripPrefix function. Errors are returned as JSON
+// objects having the 'error' field.
+type Handler struct {
+	cache    *vl.Cache[string, []byte]
+	settings Settings
+	mux      *http.ServeMux
+	stats    inspector.Source
+
+	// lock makes composite operations over records atomic, e.g. reading a
+	// record and removing it when its TTL override has expired.
+	lock *sync.Mutex
+}
+
+// NewHandler creates a handler of the cache.
+func NewHandler(cache *vl.Cache[string, []byte], settings Settings) (h *Handler, err error) {
+	if cache == nil {
+		return nil, errors.New(ErrCacheIsNil)
+	}
+
+	err = settings.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(settings.Name) == 0 {
+		settings.Name = DefaultName
+	}
+	if settings.MaxBodySize == 0 {
+		settings.MaxBodySize = DefaultMaxBodySize
+	}
+
+	h = &Handler{
+		cache:    cache,
+		settings: settings,
+		mux:      http.NewServeMux(),
+		stats:    inspector.FromVL(cache),
+		lock:     new(sync.Mutex),
+	}
+
+	h.mux.HandleFunc("GET /records/{uid}", h.getRecord)
+	h.mux.HandleFunc("HEAD /records/{uid}", h.recordExists)
+	h.mux.HandleFunc("PUT /records/{uid}", h.putRecord)
+	h.mux.HandleFunc("DELETE /records/{uid}", h.deleteRecord)
+	h.mux.HandleFunc("POST /clear", h.clear)
+	h.mux.HandleFunc("GET /stats", h.getStats)
+
+	return h, nil
+}
+
+// ServeHTTP serves a request.
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	h.mux.ServeHTTP(rw, req)
+}
+
+func (h *Handler) now() int64 {
+	if h.settings.Clock == nil {
+		return time.Now().Unix()
+	}
+
+	return h.settings.Clock.Now().Unix()
+}
+
+// readItem reads an alive item using the read function of the cache. A
+// record whose TTL override has expired is removed.
+func (h *Handler) readItem(uid string, read func(uid string) ([]byte, error)) (it itemcodec.Item, found bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.readItemUnsafe(uid, read)
+}
+
+func (h *Handler) readItemUnsafe(uid string, read func(uid string) ([]byte, error)) (it itemcodec.Item, found bool) {
+	buf, err := read(uid)
+	if err != nil {
+		return it, false
+	}
+
+	it, err = itemcodec.Decode(buf)
+	if (err != nil) || !it.IsAlive(h.now()) {
+		h.cache.RemoveRecord(uid)
+		return it, false
+	}
+
+	return it, true
+}
+
+// getRecord returns data of a record.
+func (h *Handler) getRecord(rw http.ResponseWriter, req *http.Request) {
+	uid := req.PathValue("uid")
+	it, found := h.readItem(uid, h.cache.GetRecord)
+	if !found {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf(ErrRecordIsNotFound, uid))
+		return
+	}
+
+	h.writeTtl(rw, it)
+	rw.Header().Set("Content-Type", "application/octet-stream")
+	rw.Header().Set("Content-Length", strconv.Itoa(len(it.Data)))
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(it.Data)
+}
+
+// recordExists checks whether a record exists. Like the RecordExists method
+// of the cache, it does not move the record and does not prolong its life.
+func (h *Handler) recordExists(rw http.ResponseWriter, req *http.Request) {
+	it, found := h.readItem(req.PathValue("uid"), h.cache.PeekRecord)
+	if !found {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	h.writeTtl(rw, it)
+	rw.Header().Set("Content-Length", strconv.Itoa(len(it.Data)))
+	rw.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) writeTtl(rw http.ResponseWriter, it itemcodec.Item) {
+	if it.ExpiresAt != 0 {
+		rw.Header().Set(TtlHeader, strconv.FormatInt(it.ExpiresAt-h.now(), 10))
+	}
+}
+
+// putRecord adds or updates a record. The request body is the record's data.
+func (h *Handler) putRecord(rw http.ResponseWriter, req *http.Request) {
+	uid := req.PathValue("uid")
+
+	var it itemcodec.Item
+	ttlHeader := req.Header.Get(TtlHeader)
+	if len(ttlHeader) > 0 {
+		ttl, err := strconv.ParseInt(ttlHeader, 10, 64)
+		if (err != nil) || (ttl <= 0) {
+			writeError(rw, http.StatusBadRequest, fmt.Sprintf(ErrTtlHeaderIsBad, ttlHeader))
+			return
+		}
+		it.ExpiresAt = h.now() + ttl
+	}
+
+	var err error
+	it.Data, err = io.ReadAll(http.MaxBytesReader(rw, req.Body, h.settings.MaxBodySize))
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			writeError(rw, http.StatusRequestEntityTooLarge, ErrRecordIsTooBig)
+			return
+		}
+
+		writeError(rw, http.StatusBadRequest, fmt.Sprintf(ErrRequestBodyIsNotReadable, err))
+		return
+	}
+
+	h.lock.Lock()
+	err = h.cache.AddRecord(uid, it.Encode())
+	h.lock.Unlock()
+	if err != nil {
+		if err.Error() == vl.ErrRecordIsTooBig {
+			writeError(rw, http.StatusRequestEntityTooLarge, ErrRecordIsTooBig)
+			return
+		}
+
+		writeError(rw, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// deleteRecord removes a record.
+func (h *Handler) deleteRecord(rw http.ResponseWriter, req *http.Request) {
+	uid := req.PathValue("uid")
+
+	h.lock.Lock()
+	_, found := h.readItemUnsafe(uid, h.cache.PeekRecord)
+	if found {
+		h.cache.RemoveRecord(uid)
+	}
+	h.lock.Unlock()
+	if !found {
+		writeError(rw, http.StatusNotFound, fmt.Sprintf(ErrRecordIsNotFound, uid))
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// clear removes all records.
+func (h *Handler) clear(rw http.ResponseWriter, _ *http.Request) {
+	h.lock.Lock()
+	err := h.cache.Clear()
+	h.lock.Unlock()
+	if err != nil {
+		writeError(rw, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// getStats returns the state of the cache in the format of the 'inspector'
+// package without UIDs of records.
+func (h *Handler) getStats(rw http.ResponseWriter, _ *http.Request) {
+	s := h.stats.Inspect(0)
+	s.Name = h.settings.Name
+
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(s)
+}
+
+// errorResponse is a body of an error response.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(rw http.ResponseWriter, statusCode int, msg string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(statusCode)
+	_ = json.NewEncoder(rw).Encode(errorResponse{Error: msg})
+}
@@ -0,0 +1,209 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/cachetest"
+	"github.com/vault-thirteen/Cache/inspector"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+// _test_prepare_handler creates a handler of a new cache.
+func _test_prepare_handler(aTest *tester.Test, cfg vl.Config[string, []byte], settings Settings) (h *Handler, c *vl.Cache[string, []byte], clock *cachetest.Clock) {
+	clock = cachetest.NewClock(time.Unix(1_000_000, 0))
+	cfg.Clock = clock
+	settings.Clock = clock
+	if cfg.RecordTtl == 0 {
+		cfg.RecordTtl = 3600
+	}
+
+	c, err := vl.NewFromConfig(cfg)
+	aTest.MustBeNoError(err)
+	h, err = NewHandler(c, settings)
+	aTest.MustBeNoError(err)
+
+	return h, c, clock
+}
+
+// _test_do serves the request and returns the status code, the TTL header
+// and the body of the response.
+func _test_do(h http.Handler, method string, path string, body string, headers ...string) (statusCode int, ttl string, respBody string) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	buf, _ := io.ReadAll(rec.Body)
+
+	return rec.Code, rec.Header().Get(TtlHeader), string(buf)
+}
+
+func Test_NewHandler(t *testing.T) {
+	aTest := tester.New(t)
+	var c = vl.NewCache[string, []byte](0, 0, 60)
+	var h *Handler
+	var err error
+
+	// Test #1. Bad arguments.
+	_, err = NewHandler(nil, Settings{})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrCacheIsNil)
+	_, err = NewHandler(c, Settings{MaxBodySize: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrMaxBodySizeIsNegative)
+
+	// Test #2. OK.
+	h, err = NewHandler(c, Settings{})
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(h.settings.Name, DefaultName)
+	aTest.MustBeEqual(h.settings.MaxBodySize, int64(DefaultMaxBodySize))
+}
+
+func Test_Handler_records(t *testing.T) {
+	aTest := tester.New(t)
+	var statusCode int
+	var body string
+
+	h, c, _ := _test_prepare_handler(aTest, vl.Config[string, []byte]{}, Settings{})
+
+	// Test #1. Missing record.
+	statusCode, _, body = _test_do(h, http.MethodGet, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusNotFound)
+	aTest.MustBeEqual(body, fmt.Sprintf(`{"error":"%s"}`+"\n", fmt.Sprintf(ErrRecordIsNotFound, "a")))
+	statusCode, _, _ = _test_do(h, http.MethodHead, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusNotFound)
+
+	// Test #2. Writing and reading.
+	statusCode, _, _ = _test_do(h, http.MethodPut, "/records/a", "123")
+	aTest.MustBeEqual(statusCode, http.StatusNoContent)
+	statusCode, _, _ = _test_do(h, http.MethodPut, "/records/b", "")
+	aTest.MustBeEqual(statusCode, http.StatusNoContent)
+	statusCode, _, body = _test_do(h, http.MethodGet, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusOK)
+	aTest.MustBeEqual(body, "123")
+	statusCode, _, body = _test_do(h, http.MethodGet, "/records/b", "")
+	aTest.MustBeEqual(statusCode, http.StatusOK)
+	aTest.MustBeEqual(body, "")
+
+	// Test #3. Existence check does not move the record.
+	aTest.MustBeEqual(c.PeekTopUids(1), []string{"b"})
+	statusCode, _, body = _test_do(h, http.MethodHead, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusOK)
+	aTest.MustBeEqual(body, "")
+	aTest.MustBeEqual(c.PeekTopUids(1), []string{"b"})
+
+	// Test #4. Removal.
+	statusCode, _, _ = _test_do(h, http.MethodDelete, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusNoContent)
+	statusCode, _, _ = _test_do(h, http.MethodDelete, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusNotFound)
+
+	// Test #5. Clearing.
+	statusCode, _, _ = _test_do(h, http.MethodPost, "/clear", "")
+	aTest.MustBeEqual(statusCode, http.StatusNoContent)
+	aTest.MustBeEqual(c.RecordExists("b"), false)
+
+	// Test #6. Unsupported methods.
+	statusCode, _, _ = _test_do(h, http.MethodPost, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusMethodNotAllowed)
+	statusCode, _, _ = _test_do(h, http.MethodGet, "/clear", "")
+	aTest.MustBeEqual(statusCode, http.StatusMethodNotAllowed)
+}
+
+func Test_Handler_ttl(t *testing.T) {
+	aTest := tester.New(t)
+	var statusCode int
+	var ttl string
+
+	h, _, clock := _test_prepare_handler(aTest, vl.Config[string, []byte]{RecordTtl: 100}, Settings{})
+
+	// Test #1. TTL override.
+	statusCode, _, _ = _test_do(h, http.MethodPut, "/records/a", "1", TtlHeader, "10")
+	aTest.MustBeEqual(statusCode, http.StatusNoContent)
+	statusCode, ttl, _ = _test_do(h, http.MethodGet, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusOK)
+	aTest.MustBeEqual(ttl, "10")
+	clock.Add(time.Second * 9)
+	statusCode, ttl, _ = _test_do(h, http.MethodHead, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusOK)
+	aTest.MustBeEqual(ttl, "1")
+	clock.Add(time.Second)
+	statusCode, _, _ = _test_do(h, http.MethodHead, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusNotFound)
+	statusCode, _, _ = _test_do(h, http.MethodGet, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusNotFound)
+
+	// Test #2. Record with an expired override can not be removed.
+	_, _, _ = _test_do(h, http.MethodPut, "/records/a", "1", TtlHeader, "10")
+	clock.Add(time.Second * 10)
+	statusCode, _, _ = _test_do(h, http.MethodDelete, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusNotFound)
+
+	// Test #3. No override.
+	_, _, _ = _test_do(h, http.MethodPut, "/records/a", "1")
+	statusCode, ttl, _ = _test_do(h, http.MethodGet, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusOK)
+	aTest.MustBeEqual(ttl, "")
+	clock.Add(time.Second * 100)
+	statusCode, _, _ = _test_do(h, http.MethodGet, "/records/a", "")
+	aTest.MustBeEqual(statusCode, http.StatusNotFound)
+
+	// Test #4. Bad override.
+	for _, header := range []string{"x", "0", "-1"} {
+		statusCode, _, _ = _test_do(h, http.MethodPut, "/records/a", "1", TtlHeader, header)
+		aTest.MustBeEqual(statusCode, http.StatusBadRequest)
+	}
+}
+
+func Test_Handler_limits(t *testing.T) {
+	aTest := tester.New(t)
+	var statusCode int
+	var body string
+
+	h, _, _ := _test_prepare_handler(aTest, vl.Config[string, []byte]{VolumeLimit: 100}, Settings{MaxBodySize: 200})
+
+	// Test #1. Record exceeds the volume limit of the cache.
+	statusCode, _, body = _test_do(h, http.MethodPut, "/records/a", strings.Repeat("x", 150))
+	aTest.MustBeEqual(statusCode, http.StatusRequestEntityTooLarge)
+	aTest.MustBeEqual(body, `{"error":"`+ErrRecordIsTooBig+`"}`+"\n")
+
+	// Test #2. Record exceeds the size limit of the handler.
+	statusCode, _, _ = _test_do(h, http.MethodPut, "/records/a", strings.Repeat("x", 250))
+	aTest.MustBeEqual(statusCode, http.StatusRequestEntityTooLarge)
+}
+
+func Test_Handler_stats(t *testing.T) {
+	aTest := tester.New(t)
+	var statusCode int
+	var body string
+
+	h, _, _ := _test_prepare_handler(aTest, vl.Config[string, []byte]{VolumeLimit: 1000}, Settings{Name: "main"})
+	_, _, _ = _test_do(h, http.MethodPut, "/records/a", "12")
+	_, _, _ = _test_do(h, http.MethodGet, "/records/a", "")
+	_, _, _ = _test_do(h, http.MethodGet, "/records/b", "")
+
+	// Test.
+	statusCode, _, body = _test_do(h, http.MethodGet, "/stats", "")
+	aTest.MustBeEqual(statusCode, http.StatusOK)
+
+	var s inspector.State
+	aTest.MustBeNoError(json.Unmarshal([]byte(body), &s))
+	aTest.MustBeEqual(s.Name, "main")
+	aTest.MustBeEqual(s.Size, 1)
+	aTest.MustBeEqual(s.Volume, 10)
+	aTest.MustBeEqual(s.VolumeLimit, 1000)
+	aTest.MustBeEqual(s.Ttl, uint(3600))
+	aTest.MustBeEqual(s.Stats.Hits, uint64(1))
+	aTest.MustBeEqual(s.Stats.Misses, uint64(1))
+	aTest.MustBeEqual(len(s.TopUids), 0)
+}
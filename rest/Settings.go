@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"errors"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+const (
+	// DefaultMaxBodySize is the default maximum size of a record's data.
+	DefaultMaxBodySize = 16 * 1024 * 1024
+
+	// DefaultName is the default name of the cache reported by statistics.
+	DefaultName = "cache"
+)
+
+// Settings contains settings of the handler.
+type Settings struct {
+	// Name is a name of the cache reported by statistics. An empty name is
+	// replaced with the default name.
+	Name string
+
+	// MaxBodySize is a maximum size of a record's data in bytes. Larger
+	// requests are rejected without reading them into memory. Zero selects
+	// the default size.
+	MaxBodySize int64
+
+	// Clock is an optional source of current time used for TTL overrides.
+	// It should be the clock of the cache.
+	Clock vl.Clock
+}
+
+func (s Settings) validate() (err error) {
+	if s.MaxBodySize < 0 {
+		return errors.New(ErrMaxBodySizeIsNegative)
+	}
+
+	return nil
+}
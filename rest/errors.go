@@ -0,0 +1,10 @@
+package rest
+
+const (
+	ErrCacheIsNil               = "cache is nil"
+	ErrMaxBodySizeIsNegative    = "max body size is negative"
+	ErrTtlHeaderIsBad           = "TTL header is bad: %q"
+	ErrRecordIsNotFound         = "record is not found: %v"
+	ErrRecordIsTooBig           = "record is too big"
+	ErrRequestBodyIsNotReadable = "request body is not readable: %v"
+)
@@ -0,0 +1,571 @@
+// Package slab contains a cache of byte data which stores records in slabs
+// in the style of memcached. Memory is allocated in pages of the same size,
+// each page is assigned to a size class and is divided into slots of the
+// class. A record is stored in a free slot of the smallest class which fits
+// it, so freed memory is reused by records of similar sizes without
+// fragmentation, and the memory is never compacted. Records are kept in a
+// slice of structures without pointers and are linked into LRU lists of size
+// classes by indices, so the garbage collector sees only a few objects which
+// it does not need to scan.
+//
+// The cache is a separate type rather than a mode of the vl.Cache. It has the
+// basic methods of the vl.Cache for byte data and passes the conformance
+// suite of the 'cachetest' package, but its semantics differ:
+//   - data is copied when it is added and when it is read;
+//   - a record which does not fit a whole page is rejected;
+//   - the memory limit evicts the least recently used record of a size
+//     class rather than of the whole cache, and a record of a class which
+//     can not get a slot is rejected;
+//   - the volume is the total size of slots used by records, so it includes
+//     UIDs and slack space of slots;
+//   - adding a record whose UID has the same hash as the UID of another
+//     record evicts the other record with the collision reason;
+//   - loaders, stores, a grace period, refresh-ahead and methods accepting a
+//     context are not supported.
+//
+// Records with empty data are rejected, like by the vl.Cache.
+package slab
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/internal/recordindex"
+	"github.com/vault-thirteen/Cache/internal/uidcodec"
+)
+
+// EvictionReasonCollision is used when a record is replaced by a record
+// having another UID with the same hash.
+const EvictionReasonCollision = recordindex.EvictionReasonCollision
+
+// Cache is a cache of byte data stored in slabs. Data is copied when it is
+// added and when it is read, so callers may modify their slices.
+//
+// When the memory limit is reached and a size class has no free slots, the
+// least recently used record of the class is evicted to free a slot, even if
+// records of other classes were used earlier. Pages are never moved between
+// classes, so a class which got no pages before the limit was reached can not
+// store records, adding them fails. When the size limit is reached, the least
+// recently used record of all classes is evicted.
+//
+// The volume of the cache is the total size of slots used by records, so it
+// includes UIDs and slack space of slots. Memory which is allocated but is
+// not used by records is reported by statistics of slabs.
+type Cache[U vl.UidType] struct {
+	sizeLimit   int
+	memoryLimit int
+	pageSize    int
+	maxPages    int
+	recordTtl   uint
+	clock       vl.Clock
+
+	lock *sync.Mutex
+
+	// index maps UIDs to indices of records.
+	index   *recordindex.Index
+	records recordindex.Records[record]
+	size    int
+	volume  int
+	tick    uint64
+
+	classes []sizeClass
+	pages   [][]byte
+
+	stats *recordindex.Stats[U]
+}
+
+// New creates a cache using the configuration.
+func New[U vl.UidType](cfg Config[U]) (c *Cache[U], err error) {
+	cfg = cfg.withDefaults()
+
+	err = cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Cache[U]{
+		sizeLimit:   cfg.SizeLimit,
+		memoryLimit: cfg.MemoryLimit,
+		pageSize:    cfg.PageSize,
+		maxPages:    cfg.MemoryLimit / cfg.PageSize,
+		recordTtl:   cfg.RecordTtl,
+		clock:       cfg.Clock,
+		lock:        new(sync.Mutex),
+		index:       recordindex.NewIndex(),
+		stats:       recordindex.NewStats(cfg.OnEviction),
+	}
+
+	for _, slotSize := range slotSizes(cfg.MinSlotSize, cfg.PageSize, cfg.GrowthFactor) {
+		c.classes = append(c.classes, sizeClass{slotSize: slotSize})
+	}
+	c.reset()
+
+	return c, nil
+}
+
+// slotSizes returns sizes of slots of size classes. Sizes grow by the factor
+// and are aligned to 8 bytes, the largest slot is a whole page.
+func slotSizes(minSlotSize int, pageSize int, factor float64) (sizes []int) {
+	for size := minSlotSize; size < pageSize; {
+		sizes = append(sizes, size)
+
+		next := int(math.Ceil(float64(size) * factor))
+		next = (next + 7) &^ 7
+		if next <= size {
+			next = size + 8
+		}
+		size = next
+	}
+
+	return append(sizes, pageSize)
+}
+
+// reset removes all the records and releases the pages.
+func (c *Cache[U]) reset() {
+	c.index.Clear()
+	c.records.Clear()
+	c.size = 0
+	c.volume = 0
+	c.pages = nil
+
+	for i := range c.classes {
+		c.classes[i] = sizeClass{
+			slotSize:  c.classes[i].slotSize,
+			top:       noRecord,
+			bottom:    noRecord,
+			evictions: c.classes[i].evictions,
+		}
+	}
+}
+
+// now returns the current time of the cache's clock in seconds.
+func (c *Cache[U]) now() int64 {
+	if c.clock == nil {
+		return time.Now().Unix()
+	}
+
+	return c.clock.Now().Unix()
+}
+
+func (c *Cache[U]) isAlive(r *record) bool {
+	return c.now() < r.lastAccessTime+int64(c.recordTtl)
+}
+
+func (c *Cache[U]) uidBytes(i int32) []byte {
+	r := &c.records.Items[i]
+	buf := c.pages[r.slot.page]
+	return buf[r.slot.offset : int(r.slot.offset)+int(r.uidLen)]
+}
+
+func (c *Cache[U]) dataBytes(i int32) []byte {
+	r := &c.records.Items[i]
+	buf := c.pages[r.slot.page]
+	start := int(r.slot.offset) + int(r.uidLen)
+	return buf[start : start+int(r.dataLen)]
+}
+
+// classOf returns the index of the smallest size class which fits a record
+// of the length.
+func (c *Cache[U]) classOf(length int) (classIdx int, ok bool) {
+	for i := range c.classes {
+		if c.classes[i].slotSize >= length {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// allocate takes a free slot of the size class. If the class has no free
+// slots, a new page is assigned to it, or the least recently used record of
+// the class is evicted when the memory limit is reached.
+func (c *Cache[U]) allocate(classIdx int) (s slot, ok bool) {
+	cl := &c.classes[classIdx]
+
+	for len(cl.freeSlots) == 0 {
+		if (c.maxPages == 0) || (len(c.pages) < c.maxPages) {
+			c.addPage(classIdx)
+			break
+		}
+
+		if cl.bottom == noRecord {
+			return slot{}, false
+		}
+
+		cl.evictions++
+		c.evict(cl.bottom, vl.EvictionReasonVolume)
+	}
+
+	s = cl.freeSlots[len(cl.freeSlots)-1]
+	cl.freeSlots = cl.freeSlots[:len(cl.freeSlots)-1]
+
+	return s, true
+}
+
+// addPage allocates a page and divides it into free slots of the size class.
+func (c *Cache[U]) addPage(classIdx int) {
+	cl := &c.classes[classIdx]
+	c.pages = append(c.pages, make([]byte, c.pageSize))
+	cl.pages++
+
+	page := int32(len(c.pages) - 1)
+	for i := cl.slotsPerPage(c.pageSize) - 1; i >= 0; i-- {
+		cl.freeSlots = append(cl.freeSlots, slot{page: page, offset: int32(i * cl.slotSize)})
+	}
+}
+
+func (c *Cache[U]) linkTop(i int32) {
+	r := &c.records.Items[i]
+	cl := &c.classes[r.class]
+	r.upperRecord = noRecord
+	r.lowerRecord = cl.top
+	if cl.top != noRecord {
+		c.records.Items[cl.top].upperRecord = i
+	}
+	cl.top = i
+	if cl.bottom == noRecord {
+		cl.bottom = i
+	}
+}
+
+func (c *Cache[U]) unlink(i int32) {
+	r := &c.records.Items[i]
+	cl := &c.classes[r.class]
+	if r.upperRecord != noRecord {
+		c.records.Items[r.upperRecord].lowerRecord = r.lowerRecord
+	} else {
+		cl.top = r.lowerRecord
+	}
+	if r.lowerRecord != noRecord {
+		c.records.Items[r.lowerRecord].upperRecord = r.upperRecord
+	} else {
+		cl.bottom = r.upperRecord
+	}
+	r.upperRecord = noRecord
+	r.lowerRecord = noRecord
+}
+
+// touch moves the record to the top of its size class and marks it as the
+// most recently used record of the cache.
+func (c *Cache[U]) touch(i int32) {
+	c.tick++
+	c.records.Items[i].tick = c.tick
+
+	if c.classes[c.records.Items[i].class].top == i {
+		return
+	}
+
+	c.unlink(i)
+	c.linkTop(i)
+}
+
+// remove removes the record, its slot and its index become free.
+func (c *Cache[U]) remove(i int32) {
+	r := &c.records.Items[i]
+	cl := &c.classes[r.class]
+	c.unlink(i)
+	c.index.Delete(r.hash)
+	cl.freeSlots = append(cl.freeSlots, r.slot)
+	cl.size--
+	cl.stored -= r.length()
+	c.size--
+	c.volume -= cl.slotSize
+	c.records.Free(i)
+}
+
+// evict removes the record and notifies about it.
+func (c *Cache[U]) evict(i int32, reason vl.EvictionReason) {
+	c.stats.Evict(reason, c.uidBytes(i), c.dataBytes(i), func() { c.remove(i) })
+}
+
+// leastRecentlyUsed returns the least recently used record of all the size
+// classes.
+func (c *Cache[U]) leastRecentlyUsed() (i int32) {
+	i = noRecord
+	for _, cl := range c.classes {
+		if (cl.bottom != noRecord) && ((i == noRecord) || (c.records.Items[cl.bottom].tick < c.records.Items[i].tick)) {
+			i = cl.bottom
+		}
+	}
+
+	return i
+}
+
+// RecordExists checks whether the specified record exists or not. An
+// outdated record is treated as absent, and it is removed from the cache.
+func (c *Cache[U]) RecordExists(uid U) (recordExists bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	i, ok := c.index.Find(uidcodec.EncodeBinary(uid), c.uidBytes)
+	if !ok {
+		return false
+	}
+
+	if !c.isAlive(&c.records.Items[i]) {
+		c.evict(i, vl.EvictionReasonExpired)
+		return false
+	}
+
+	return true
+}
+
+// AddRecord either adds a new record to the top of the cache or moves an
+// existing record to the top of the cache. If the record already exists, its
+// data and LAT are updated. If the updated record needs a slot of another
+// size class and the slot can not be allocated, the record is not changed.
+func (c *Cache[U]) AddRecord(uid U, data []byte) (err error) {
+	if len(data) == 0 {
+		return errors.New(ErrDataIsEmpty)
+	}
+
+	uidBuf := uidcodec.EncodeBinary(uid)
+	length := len(uidBuf) + len(data)
+	classIdx, ok := c.classOf(length)
+	if !ok {
+		return errors.New(ErrRecordIsTooBig)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	h := c.index.Hash(uidBuf)
+	i, exists := c.index.Get(h)
+	if exists && !bytes.Equal(c.uidBytes(i), uidBuf) {
+		c.evict(i, EvictionReasonCollision)
+		exists = false
+	}
+
+	if !exists || (int(c.records.Items[i].class) != classIdx) {
+		// The slot of another class is allocated before the old slot is
+		// freed, so that the record is kept when the allocation fails.
+		// Records evicted by the allocation belong to the new class.
+		s, ok := c.allocate(classIdx)
+		if !ok {
+			return fmt.Errorf(ErrMemoryIsExhausted, length)
+		}
+		if exists {
+			c.remove(i)
+		}
+
+		i = c.records.New()
+		c.records.Items[i] = record{hash: h, class: int32(classIdx), slot: s}
+		c.index.Set(h, i)
+		c.linkTop(i)
+		c.classes[classIdx].size++
+		c.size++
+		c.volume += c.classes[classIdx].slotSize
+	}
+
+	r := &c.records.Items[i]
+	cl := &c.classes[classIdx]
+	cl.stored += length - r.length()
+	r.uidLen = int32(len(uidBuf))
+	r.dataLen = int32(len(data))
+	buf := c.pages[r.slot.page]
+	copy(buf[r.slot.offset:], uidBuf)
+	copy(buf[int(r.slot.offset)+len(uidBuf):], data)
+	r.lastAccessTime = c.now()
+	c.touch(i)
+
+	for (c.sizeLimit > 0) && (c.size > c.sizeLimit) {
+		c.evict(c.leastRecentlyUsed(), vl.EvictionReasonSize)
+	}
+
+	return nil
+}
+
+// GetRecord reads a copy of data of a record. An outdated record is not
+// returned, and it is removed from the cache.
+func (c *Cache[U]) GetRecord(uid U) (data []byte, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	i, ok := c.index.Find(uidcodec.EncodeBinary(uid), c.uidBytes)
+	if !ok {
+		c.stats.Misses++
+		return nil, fmt.Errorf(ErrRecordIsNotFound, uid)
+	}
+
+	r := &c.records.Items[i]
+	if !c.isAlive(r) {
+		c.evict(i, vl.EvictionReasonExpired)
+		c.stats.Misses++
+		c.stats.ExpiredOnRead++
+		return nil, fmt.Errorf(ErrRecordIsOutdated, uid)
+	}
+
+	c.touch(i)
+	r.lastAccessTime = c.now()
+	c.stats.Hits++
+
+	return bytes.Clone(c.dataBytes(i)), nil
+}
+
+// RemoveRecord removes a record if it exists.
+func (c *Cache[U]) RemoveRecord(uid U) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	i, ok := c.index.Find(uidcodec.EncodeBinary(uid), c.uidBytes)
+	if ok {
+		c.remove(i)
+	}
+}
+
+// RemoveExistingRecord removes an existing record, it returns an error if
+// the record is not found.
+func (c *Cache[U]) RemoveExistingRecord(uid U) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	i, ok := c.index.Find(uidcodec.EncodeBinary(uid), c.uidBytes)
+	if !ok {
+		return fmt.Errorf(ErrRecordIsNotFound, uid)
+	}
+
+	c.remove(i)
+
+	return nil
+}
+
+// Clear removes all records and releases the pages.
+func (c *Cache[U]) Clear() (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.reset()
+
+	return nil
+}
+
+// GetSize returns the number of records and the size limit.
+func (c *Cache[U]) GetSize() (size int, sizeLimit int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.size, c.sizeLimit
+}
+
+// GetVolume returns the total size of slots used by records and the memory
+// limit.
+func (c *Cache[U]) GetVolume() (usedVolume int, volumeLimit int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.volume, c.memoryLimit
+}
+
+// GetTtl returns the records' TTL in seconds.
+func (c *Cache[U]) GetTtl() (recordTtl uint) {
+	return c.recordTtl
+}
+
+// GetStats returns a copy of the statistics.
+func (c *Cache[U]) GetStats() (stats vl.Stats) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.stats.Copy()
+}
+
+// GetSlabStats returns statistics of memory of the cache.
+func (c *Cache[U]) GetSlabStats() (stats SlabStats) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats = SlabStats{
+		PageSize:       c.pageSize,
+		Pages:          len(c.pages),
+		MaxPages:       c.maxPages,
+		AllocatedBytes: c.volume,
+		Classes:        make([]ClassStats, 0, len(c.classes)),
+	}
+
+	for _, cl := range c.classes {
+		cs := ClassStats{
+			SlotSize:    cl.slotSize,
+			Pages:       cl.pages,
+			Slots:       cl.pages * cl.slotsPerPage(c.pageSize),
+			UsedSlots:   cl.size,
+			StoredBytes: cl.stored,
+			SlackBytes:  cl.size*cl.slotSize - cl.stored,
+			Evictions:   cl.evictions,
+		}
+		stats.Classes = append(stats.Classes, cs)
+
+		stats.StoredBytes += cs.StoredBytes
+		stats.SlackBytes += cs.SlackBytes
+		stats.FreeBytes += len(cl.freeSlots) * cl.slotSize
+		stats.TailBytes += cl.pages * (c.pageSize % cl.slotSize)
+	}
+
+	return stats
+}
+
+// PeekTopUids returns UIDs of at most n records from the top of the cache,
+// i.e. of the most recently used records.
+func (c *Cache[U]) PeekTopUids(n int) (uids []U) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.peek(n, true)
+}
+
+// PeekBottomUids returns UIDs of at most n records from the bottom of the
+// cache, i.e. of the least recently used records.
+func (c *Cache[U]) PeekBottomUids(n int) (uids []U) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.peek(n, false)
+}
+
+// peek merges LRU lists of the size classes from their tops or from their
+// bottoms.
+func (c *Cache[U]) peek(n int, fromTop bool) (uids []U) {
+	cursors := make([]int32, len(c.classes))
+	for ci, cl := range c.classes {
+		if fromTop {
+			cursors[ci] = cl.top
+		} else {
+			cursors[ci] = cl.bottom
+		}
+	}
+
+	uids = make([]U, 0, max(min(n, c.size), 0))
+	for len(uids) < n {
+		best := -1
+		for ci, i := range cursors {
+			if i == noRecord {
+				continue
+			}
+			if (best < 0) || ((c.records.Items[i].tick > c.records.Items[cursors[best]].tick) == fromTop) {
+				best = ci
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		r := &c.records.Items[cursors[best]]
+		uids = append(uids, uidcodec.DecodeBinary[U](c.uidBytes(cursors[best])))
+		if fromTop {
+			cursors[best] = r.lowerRecord
+		} else {
+			cursors[best] = r.upperRecord
+		}
+	}
+
+	return uids
+}
+
+// Close does nothing, it exists for compatibility with the vl.Cache.
+func (c *Cache[U]) Close() (err error) {
+	return nil
+}
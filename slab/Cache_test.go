@@ -0,0 +1,415 @@
+package slab
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	cache "github.com/vault-thirteen/Cache"
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/cachetest"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+// stringCache adapts the cache to string data for the conformance suite.
+type stringCache struct {
+	c *Cache[string]
+}
+
+func (sc stringCache) RecordExists(uid string) (recordExists bool) {
+	return sc.c.RecordExists(uid)
+}
+
+func (sc stringCache) AddRecord(uid string, data string) (err error) {
+	return sc.c.AddRecord(uid, []byte(data))
+}
+
+func (sc stringCache) GetRecord(uid string) (data string, err error) {
+	var buf []byte
+	buf, err = sc.c.GetRecord(uid)
+	return string(buf), err
+}
+
+func (sc stringCache) RemoveRecord(uid string) {
+	sc.c.RemoveRecord(uid)
+}
+
+func (sc stringCache) RemoveExistingRecord(uid string) (err error) {
+	return sc.c.RemoveExistingRecord(uid)
+}
+
+func (sc stringCache) Clear() (err error) {
+	return sc.c.Clear()
+}
+
+func Test_Conformance(t *testing.T) {
+	cachetest.Run(t, func(t *testing.T, s cachetest.Settings) cache.Cache[string, string] {
+		c, err := New(Config[string]{
+			SizeLimit:   s.SizeLimit,
+			RecordTtl:   s.RecordTtl,
+			PageSize:    256,
+			MinSlotSize: 8,
+			Clock:       s.Clock,
+			OnEviction: func(uid string, data []byte, reason vl.EvictionReason) {
+				if s.OnEviction != nil {
+					s.OnEviction(uid)
+				}
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return stringCache{c}
+	}, cachetest.Features{SizeLimit: true, Ttl: true, OnEviction: true})
+}
+
+func Test_New(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	// Test #1. Bad configurations.
+	for _, test := range []struct {
+		cfg Config[string]
+		err string
+	}{
+		{Config[string]{}, ErrTtlIsZero},
+		{Config[string]{RecordTtl: 1, SizeLimit: -1}, ErrSizeLimitIsNegative},
+		{Config[string]{RecordTtl: 1, MemoryLimit: -1}, ErrMemoryLimitIsNegative},
+		{Config[string]{RecordTtl: 1, MemoryLimit: 100, PageSize: 128}, ErrMemoryLimitIsTooSmall},
+		{Config[string]{RecordTtl: 1, PageSize: -1}, ErrPageSizeIsBad},
+		{Config[string]{RecordTtl: 1, PageSize: 128, MinSlotSize: 256}, ErrMinSlotSizeIsBad},
+		{Config[string]{RecordTtl: 1, MinSlotSize: -1}, ErrMinSlotSizeIsBad},
+		{Config[string]{RecordTtl: 1, GrowthFactor: 1}, ErrGrowthFactorIsBad},
+	} {
+		_, err = New(test.cfg)
+		aTest.MustBeAnError(err)
+		aTest.MustBeEqual(err.Error(), test.err)
+	}
+
+	// Test #2. Default settings.
+	c, err := New(Config[string]{RecordTtl: 1, MemoryLimit: 3 * DefaultPageSize})
+	aTest.MustBeNoError(err)
+	stats := c.GetSlabStats()
+	aTest.MustBeEqual(stats.PageSize, DefaultPageSize)
+	aTest.MustBeEqual(stats.MaxPages, 3)
+	aTest.MustBeEqual(stats.Pages, 0)
+	aTest.MustBeEqual(stats.Classes[0].SlotSize, DefaultMinSlotSize)
+	aTest.MustBeEqual(stats.Classes[1].SlotSize, DefaultMinSlotSize*2)
+	aTest.MustBeEqual(stats.Classes[len(stats.Classes)-1].SlotSize, DefaultPageSize)
+	aTest.MustBeEqual(c.GetTtl(), uint(1))
+}
+
+func Test_slotSizes(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test #1. Powers of two.
+	aTest.MustBeEqual(slotSizes(16, 128, 2), []int{16, 32, 64, 128})
+
+	// Test #2. Sizes are aligned, the largest slot is a page.
+	aTest.MustBeEqual(slotSizes(48, 200, 1.25), []int{48, 64, 80, 104, 136, 176, 200})
+
+	// Test #3. Sizes grow even with a small factor.
+	aTest.MustBeEqual(slotSizes(8, 32, 1.01), []int{8, 16, 24, 32})
+}
+
+func Test_record(t *testing.T) {
+	// Test #1. Records have no pointers, so the garbage collector does not
+	// scan them.
+	var check func(rt reflect.Type)
+	check = func(rt reflect.Type) {
+		for i := 0; i < rt.NumField(); i++ {
+			switch ft := rt.Field(i).Type; ft.Kind() {
+			case reflect.Int32, reflect.Int64, reflect.Uint64:
+			case reflect.Struct:
+				check(ft)
+			default:
+				t.Fatalf("field %s has a kind %v", rt.Field(i).Name, ft.Kind())
+			}
+		}
+	}
+	check(reflect.TypeFor[record]())
+}
+
+func Test_Cache(t *testing.T) {
+	aTest := tester.New(t)
+	var data []byte
+	var err error
+
+	c, _ := _test_new(t, Config[string]{})
+
+	// Test #1. Empty data, unknown records and records which are too big.
+	err = c.AddRecord("A", nil)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrDataIsEmpty)
+	err = c.AddRecord("A", []byte(strings.Repeat("a", 128)))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrRecordIsTooBig)
+	_, err = c.GetRecord("A")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrRecordIsNotFound, "A"))
+	err = c.RemoveExistingRecord("A")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrRecordIsNotFound, "A"))
+
+	// Test #2. Data is copied.
+	buf := []byte("abc")
+	aTest.MustBeNoError(c.AddRecord("A", buf))
+	buf[0] = 'x'
+	data, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("abc"))
+	data[0] = 'y'
+	data, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("abc"))
+
+	// Test #3. Order of records of different size classes, updates within a
+	// class and across classes.
+	aTest.MustBeNoError(c.AddRecord("B", []byte(strings.Repeat("b", 20))))
+	aTest.MustBeNoError(c.AddRecord("C", []byte("c")))
+	aTest.MustBeNoError(c.AddRecord("A", []byte("aaaa")))
+	aTest.MustBeEqual(c.PeekTopUids(5), []string{"A", "C", "B"})
+	aTest.MustBeNoError(c.AddRecord("C", []byte(strings.Repeat("c", 40))))
+	aTest.MustBeEqual(c.PeekTopUids(5), []string{"C", "A", "B"})
+	aTest.MustBeEqual(c.PeekBottomUids(2), []string{"B", "A"})
+	data, err = c.GetRecord("C")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte(strings.Repeat("c", 40)))
+	size, _ := c.GetSize()
+	aTest.MustBeEqual(size, 3)
+	volume, _ := c.GetVolume()
+	aTest.MustBeEqual(volume, 16+32+64)
+	_test_check_accounting(t, c)
+
+	// Test #4. Removal.
+	c.RemoveRecord("C")
+	aTest.MustBeNoError(c.RemoveExistingRecord("B"))
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+	aTest.MustBeEqual(c.PeekTopUids(5), []string{"A"})
+	_test_check_accounting(t, c)
+
+	// Test #5. Clearing releases the pages.
+	aTest.MustBeNoError(c.Clear())
+	aTest.MustBeEqual(c.RecordExists("A"), false)
+	aTest.MustBeEqual(c.GetSlabStats().Pages, 0)
+	aTest.MustBeNoError(c.AddRecord("A", []byte("a")))
+	aTest.MustBeEqual(c.RecordExists("A"), true)
+	_test_check_accounting(t, c)
+	aTest.MustBeNoError(c.Close())
+}
+
+func Test_Cache_memoryLimit(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	var evicted []string
+	c, _ := _test_new(t, Config[string]{
+		MemoryLimit: 256,
+		OnEviction: func(uid string, data []byte, reason vl.EvictionReason) {
+			evicted = append(evicted, uid+":"+string(reason))
+		},
+	})
+
+	// Test #1. Pages are assigned to size classes. A page of the class of 64
+	// bytes has two slots.
+	aTest.MustBeNoError(c.AddRecord("S0", []byte("s")))
+	aTest.MustBeNoError(c.AddRecord("L0", []byte(strings.Repeat("l", 40))))
+	aTest.MustBeNoError(c.AddRecord("L1", []byte(strings.Repeat("l", 40))))
+	aTest.MustBeEqual(c.GetSlabStats().Pages, 2)
+
+	// Test #2. When the memory is exhausted, records are evicted from the
+	// class which needs a slot, even if records of other classes are older.
+	aTest.MustBeNoError(c.AddRecord("L2", []byte(strings.Repeat("l", 40))))
+	aTest.MustBeEqual(evicted, []string{"L0:" + string(vl.EvictionReasonVolume)})
+	aTest.MustBeEqual(c.RecordExists("S0"), true)
+	aTest.MustBeEqual(c.GetStats().Evictions[vl.EvictionReasonVolume], uint64(1))
+
+	// Test #3. Small records use free slots of their class.
+	for i := 1; i < 8; i++ {
+		aTest.MustBeNoError(c.AddRecord(fmt.Sprint("S", i), []byte("s")))
+	}
+	aTest.MustBeEqual(len(evicted), 1)
+	aTest.MustBeNoError(c.AddRecord("S8", []byte("s")))
+	aTest.MustBeEqual(evicted[1], "S0:"+string(vl.EvictionReasonVolume))
+	_test_check_accounting(t, c)
+
+	// Test #4. A class without pages can not store records.
+	err = c.AddRecord("M", []byte(strings.Repeat("m", 20)))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrMemoryIsExhausted, 21))
+	aTest.MustBeEqual(c.RecordExists("M"), false)
+
+	// Test #5. A record which can not move to another class is kept.
+	err = c.AddRecord("L1", []byte(strings.Repeat("l", 20)))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrMemoryIsExhausted, 22))
+	data, err := c.GetRecord("L1")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte(strings.Repeat("l", 40)))
+	_test_check_accounting(t, c)
+
+	// Test #6. Statistics of classes.
+	stats := c.GetSlabStats()
+	aTest.MustBeEqual(stats.Classes[0], ClassStats{
+		SlotSize: 16, Pages: 1, Slots: 8, UsedSlots: 8, StoredBytes: 8 * 3, SlackBytes: 8 * 13, Evictions: 1,
+	})
+	aTest.MustBeEqual(stats.Classes[2], ClassStats{
+		SlotSize: 64, Pages: 1, Slots: 2, UsedSlots: 2, StoredBytes: 2 * 42, SlackBytes: 2 * 22, Evictions: 1,
+	})
+	aTest.MustBeEqual(stats.AllocatedBytes, 8*16+2*64)
+	aTest.MustBeEqual(stats.StoredBytes, 8*3+2*42)
+	aTest.MustBeEqual(stats.SlackBytes, 8*13+2*22)
+	aTest.MustBeEqual(stats.FreeBytes, 0)
+	aTest.MustBeEqual(stats.TailBytes, 0)
+	volume, volumeLimit := c.GetVolume()
+	aTest.MustBeEqual(volume, stats.AllocatedBytes)
+	aTest.MustBeEqual(volumeLimit, 256)
+}
+
+func Test_Cache_sizeLimit(t *testing.T) {
+	aTest := tester.New(t)
+
+	var evicted []string
+	c, _ := _test_new(t, Config[string]{
+		SizeLimit: 2,
+		OnEviction: func(uid string, data []byte, reason vl.EvictionReason) {
+			evicted = append(evicted, uid+":"+string(data)+":"+string(reason))
+		},
+	})
+
+	// Test #1. The least recently used record of all classes is evicted.
+	aTest.MustBeNoError(c.AddRecord("A", []byte("a")))
+	aTest.MustBeNoError(c.AddRecord("B", []byte(strings.Repeat("b", 20))))
+	aTest.MustBeNoError(c.AddRecord("C", []byte(strings.Repeat("c", 40))))
+	aTest.MustBeEqual(evicted, []string{"A:a:size"})
+	_, err := c.GetRecord("B")
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(c.AddRecord("D", []byte("d")))
+	aTest.MustBeEqual(evicted[1], "C:"+strings.Repeat("c", 40)+":size")
+	aTest.MustBeEqual(c.PeekTopUids(5), []string{"D", "B"})
+	_test_check_accounting(t, c)
+}
+
+func Test_Cache_ttl(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	var evicted []string
+	c, clock := _test_new(t, Config[string]{
+		OnEviction: func(uid string, data []byte, reason vl.EvictionReason) {
+			evicted = append(evicted, uid+":"+string(reason))
+		},
+	})
+
+	// Test #1. Reading prolongs the life of a record.
+	aTest.MustBeNoError(c.AddRecord("A", []byte("a")))
+	aTest.MustBeNoError(c.AddRecord("B", []byte("b")))
+	clock.Add(time.Second * 40)
+	_, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	clock.Add(time.Second * 30)
+
+	// Test #2. Outdated records are removed on access.
+	_, err = c.GetRecord("B")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrRecordIsOutdated, "B"))
+	aTest.MustBeEqual(c.RecordExists("A"), true)
+	clock.Add(time.Second * 60)
+	aTest.MustBeEqual(c.RecordExists("A"), false)
+	aTest.MustBeEqual(evicted, []string{"B:expired", "A:expired"})
+
+	stats := c.GetStats()
+	aTest.MustBeEqual(stats.Hits, uint64(1))
+	aTest.MustBeEqual(stats.Misses, uint64(1))
+	aTest.MustBeEqual(stats.ExpiredOnRead, uint64(1))
+	aTest.MustBeEqual(stats.Evictions[vl.EvictionReasonExpired], uint64(2))
+	_test_check_accounting(t, c)
+}
+
+func Test_Cache_collision(t *testing.T) {
+	aTest := tester.New(t)
+
+	var evicted []string
+	c, _ := _test_new(t, Config[string]{
+		OnEviction: func(uid string, data []byte, reason vl.EvictionReason) {
+			evicted = append(evicted, uid+":"+string(reason))
+		},
+	})
+
+	// Test #1. A record having the same hash of the UID is replaced. The
+	// collision is simulated by moving the record to the hash of another
+	// UID.
+	aTest.MustBeNoError(c.AddRecord("A", []byte("a")))
+	i, _ := c.index.Get(c.index.Hash([]byte("A")))
+	c.index.Delete(c.records.Items[i].hash)
+	c.records.Items[i].hash = c.index.Hash([]byte("B"))
+	c.index.Set(c.records.Items[i].hash, i)
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+
+	aTest.MustBeNoError(c.AddRecord("B", []byte("b")))
+	aTest.MustBeEqual(evicted, []string{"A:" + string(EvictionReasonCollision)})
+	aTest.MustBeEqual(c.PeekTopUids(5), []string{"B"})
+	_test_check_accounting(t, c)
+}
+
+func Test_Cache_churn(t *testing.T) {
+	aTest := tester.New(t)
+	var data []byte
+	var err error
+
+	c, _ := _test_new(t, Config[string]{MemoryLimit: 1024})
+	var expected = map[string][]byte{}
+
+	// Test #1. Records of various sizes are updated many times, the memory
+	// is reused without growing.
+	for n := 0; n < 1000; n++ {
+		uid := fmt.Sprint(n % 13)
+		err = c.AddRecord(uid, []byte(strings.Repeat(uid[:1], 1+(n*7)%100)))
+		if err != nil {
+			aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrMemoryIsExhausted, len(uid)+1+(n*7)%100))
+		} else {
+			expected[uid] = []byte(strings.Repeat(uid[:1], 1+(n*7)%100))
+		}
+		_test_check_accounting(t, c)
+	}
+	stats := c.GetSlabStats()
+	aTest.MustBeEqual(stats.Pages, 8)
+	aTest.MustBeEqual(stats.AllocatedBytes+stats.FreeBytes+stats.TailBytes, stats.Pages*stats.PageSize)
+	aTest.MustBeEqual(stats.AllocatedBytes, stats.StoredBytes+stats.SlackBytes)
+
+	// Test #2. Stored records are intact, a failed update keeps the
+	// previous data.
+	for uid, expectedData := range expected {
+		data, err = c.GetRecord(uid)
+		if err == nil {
+			aTest.MustBeEqual(data, expectedData)
+		}
+	}
+}
+
+func Test_Cache_integerUids(t *testing.T) {
+	aTest := tester.New(t)
+	var data []byte
+	var err error
+
+	c, err := New(Config[int]{RecordTtl: 60, PageSize: 64, MinSlotSize: 16})
+	aTest.MustBeNoError(err)
+
+	// Test #1. Integer UIDs, including negative ones.
+	aTest.MustBeNoError(c.AddRecord(-1, []byte("minus")))
+	aTest.MustBeNoError(c.AddRecord(1, []byte("plus")))
+	data, err = c.GetRecord(-1)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("minus"))
+	aTest.MustBeEqual(c.PeekTopUids(2), []int{-1, 1})
+	aTest.MustBeEqual(c.RecordExists(2), false)
+
+	// Test #2. Unsigned UIDs.
+	cu, err := New(Config[uint]{RecordTtl: 60})
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(cu.AddRecord(^uint(0), []byte("max")))
+	aTest.MustBeEqual(cu.PeekBottomUids(1), []uint{^uint(0)})
+}
@@ -0,0 +1,25 @@
+package slab
+
+// ClassStats contains statistics of a size class.
+type ClassStats struct {
+	// SlotSize is the size of a slot of the class in bytes.
+	SlotSize int
+
+	// Pages is the number of pages assigned to the class.
+	Pages int
+
+	// Slots is the number of slots of the class, UsedSlots is the number of
+	// slots storing records.
+	Slots     int
+	UsedSlots int
+
+	// StoredBytes is the number of bytes of stored UIDs and data.
+	StoredBytes int
+
+	// SlackBytes is the number of bytes of used slots which are not used by
+	// UIDs and data.
+	SlackBytes int
+
+	// Evictions is the number of records of the class evicted to free a slot.
+	Evictions uint64
+}
@@ -0,0 +1,103 @@
+package slab
+
+import (
+	"errors"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+const (
+	// DefaultPageSize is the default size of a page in bytes.
+	DefaultPageSize = 1024 * 1024
+
+	// DefaultMinSlotSize is the default size of a slot of the smallest size
+	// class in bytes.
+	DefaultMinSlotSize = 64
+
+	// DefaultGrowthFactor is the default ratio of slot sizes of neighbouring
+	// size classes.
+	DefaultGrowthFactor = 2.0
+)
+
+// Config contains settings of the cache. Zero values of the page size, the
+// minimal slot size and the growth factor select default values.
+type Config[U vl.UidType] struct {
+	// SizeLimit is a maximum number of records, zero disables the limit.
+	SizeLimit int
+
+	// MemoryLimit is a maximum total size of pages in bytes, zero disables
+	// the limit.
+	MemoryLimit int
+
+	// PageSize is a size of a page in bytes. A record, i.e. its UID and its
+	// data, must fit a single page.
+	PageSize int
+
+	// MinSlotSize is a size of a slot of the smallest size class in bytes.
+	MinSlotSize int
+
+	// GrowthFactor is a ratio of slot sizes of neighbouring size classes.
+	GrowthFactor float64
+
+	// RecordTtl is the records' TTL in seconds, it must not be zero.
+	RecordTtl uint
+
+	// Clock is an optional source of current time.
+	Clock vl.Clock
+
+	// OnEviction is an optional callback which is called when a record is
+	// evicted to fit the limits, removed as outdated or replaced by a record
+	// having the same hash of the UID. It receives a copy of the data. It is
+	// called while the cache is locked, so it must not use the cache.
+	OnEviction func(uid U, data []byte, reason vl.EvictionReason)
+}
+
+// withDefaults returns the configuration where zero values are replaced by
+// default values.
+func (cfg Config[U]) withDefaults() Config[U] {
+	if cfg.PageSize == 0 {
+		cfg.PageSize = DefaultPageSize
+	}
+
+	if cfg.MinSlotSize == 0 {
+		cfg.MinSlotSize = min(DefaultMinSlotSize, cfg.PageSize)
+	}
+
+	if cfg.GrowthFactor == 0 {
+		cfg.GrowthFactor = DefaultGrowthFactor
+	}
+
+	return cfg
+}
+
+func (cfg Config[U]) validate() (err error) {
+	if cfg.SizeLimit < 0 {
+		return errors.New(ErrSizeLimitIsNegative)
+	}
+
+	if cfg.MemoryLimit < 0 {
+		return errors.New(ErrMemoryLimitIsNegative)
+	}
+
+	if cfg.PageSize <= 0 {
+		return errors.New(ErrPageSizeIsBad)
+	}
+
+	if (cfg.MemoryLimit > 0) && (cfg.MemoryLimit < cfg.PageSize) {
+		return errors.New(ErrMemoryLimitIsTooSmall)
+	}
+
+	if (cfg.MinSlotSize <= 0) || (cfg.MinSlotSize > cfg.PageSize) {
+		return errors.New(ErrMinSlotSizeIsBad)
+	}
+
+	if !(cfg.GrowthFactor > 1) {
+		return errors.New(ErrGrowthFactorIsBad)
+	}
+
+	if cfg.RecordTtl == 0 {
+		return errors.New(ErrTtlIsZero)
+	}
+
+	return nil
+}
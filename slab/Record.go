@@ -0,0 +1,33 @@
+package slab
+
+import (
+	"github.com/vault-thirteen/Cache/internal/recordindex"
+)
+
+// noRecord is an index meaning the absence of a record.
+const noRecord = recordindex.NoRecord
+
+// record is a record of the cache. It contains no pointers, so the garbage
+// collector does not scan records. The UID and the data of the record are
+// stored one after another in a slot of its size class, records of a size
+// class are linked into the LRU list of the class by indices.
+type record struct {
+	hash uint64
+
+	// tick is the number of the last access to the record among accesses to
+	// all records, it orders records of different size classes.
+	tick uint64
+
+	lastAccessTime int64
+	class          int32
+	slot           slot
+	uidLen         int32
+	dataLen        int32
+	upperRecord    int32
+	lowerRecord    int32
+}
+
+// length returns the number of bytes of the record in its slot.
+func (r *record) length() int {
+	return int(r.uidLen) + int(r.dataLen)
+}
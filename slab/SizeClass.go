@@ -0,0 +1,23 @@
+package slab
+
+// sizeClass is a class of slots of the same size. Pages assigned to the class
+// are divided into its slots, the class has its own list of free slots and
+// its own LRU list of records.
+type sizeClass struct {
+	slotSize  int
+	pages     int
+	freeSlots []slot
+
+	top    int32
+	bottom int32
+	size   int
+
+	// stored is the number of bytes of UIDs and data stored in the slots.
+	stored    int
+	evictions uint64
+}
+
+// slotsPerPage returns the number of slots of the class in a page.
+func (cl *sizeClass) slotsPerPage(pageSize int) int {
+	return pageSize / cl.slotSize
+}
@@ -0,0 +1,34 @@
+package slab
+
+// SlabStats contains statistics of memory of the cache.
+type SlabStats struct {
+	// PageSize is the size of a page in bytes.
+	PageSize int
+
+	// Pages is the number of allocated pages, MaxPages is the maximum number
+	// of pages or zero if it is not limited.
+	Pages    int
+	MaxPages int
+
+	// AllocatedBytes is the number of bytes of used slots, it is the volume
+	// of the cache.
+	AllocatedBytes int
+
+	// StoredBytes is the number of bytes of stored UIDs and data.
+	StoredBytes int
+
+	// SlackBytes is the number of bytes of used slots which are not used by
+	// UIDs and data.
+	SlackBytes int
+
+	// FreeBytes is the number of bytes of free slots.
+	FreeBytes int
+
+	// TailBytes is the number of bytes at the ends of pages which are too
+	// small for a slot.
+	TailBytes int
+
+	// Classes are statistics of size classes, from the smallest slots to the
+	// largest ones.
+	Classes []ClassStats
+}
@@ -0,0 +1,7 @@
+package slab
+
+// slot is a place of a record in a page.
+type slot struct {
+	page   int32
+	offset int32
+}
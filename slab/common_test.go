@@ -0,0 +1,64 @@
+package slab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vault-thirteen/Cache/cachetest"
+)
+
+// _test_new creates a cache of string UIDs with the TTL of 60 seconds and
+// small pages having slots of 16, 32, 64 and 128 bytes.
+func _test_new(t *testing.T, cfg Config[string]) (c *Cache[string], clock *cachetest.Clock) {
+	clock = cachetest.NewClock(time.Unix(1_700_000_000, 0))
+	cfg.Clock = clock
+	if cfg.RecordTtl == 0 {
+		cfg.RecordTtl = 60
+	}
+	if cfg.PageSize == 0 {
+		cfg.PageSize = 128
+	}
+	if cfg.MinSlotSize == 0 {
+		cfg.MinSlotSize = 16
+	}
+
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c, clock
+}
+
+// _test_check_accounting checks that counters of the cache match its
+// records and slots.
+func _test_check_accounting(t *testing.T, c *Cache[string]) {
+	t.Helper()
+
+	var size, volume int
+	for ci, cl := range c.classes {
+		var classSize, stored int
+		for i := cl.top; i != noRecord; i = c.records.Items[i].lowerRecord {
+			r := &c.records.Items[i]
+			if int(r.class) != ci {
+				t.Fatalf("record %d of class %d is linked into class %d", i, r.class, ci)
+			}
+			classSize++
+			stored += r.length()
+		}
+
+		if (classSize != cl.size) || (stored != cl.stored) {
+			t.Fatalf("class %d: size %d/%d, stored %d/%d", ci, cl.size, classSize, cl.stored, stored)
+		}
+		if cl.size+len(cl.freeSlots) != cl.pages*cl.slotsPerPage(c.pageSize) {
+			t.Fatalf("class %d: %d used and %d free slots in %d pages", ci, cl.size, len(cl.freeSlots), cl.pages)
+		}
+
+		size += classSize
+		volume += classSize * cl.slotSize
+	}
+
+	if (size != c.size) || (volume != c.volume) || (c.index.Len() != size) {
+		t.Fatalf("size %d/%d, volume %d/%d, index %d", c.size, size, c.volume, volume, c.index.Len())
+	}
+}
@@ -0,0 +1,18 @@
+package slab
+
+import vl "github.com/vault-thirteen/Cache/VL"
+
+const (
+	ErrDataIsEmpty           = vl.ErrDataIsEmpty
+	ErrRecordIsNotFound      = vl.ErrRecordIsNotFound
+	ErrRecordIsOutdated      = vl.ErrRecordIsOutdated
+	ErrRecordIsTooBig        = vl.ErrRecordIsTooBig
+	ErrTtlIsZero             = vl.ErrTtlIsZero
+	ErrSizeLimitIsNegative   = vl.ErrSizeLimitIsNegative
+	ErrMemoryLimitIsNegative = "memory limit is negative"
+	ErrMemoryLimitIsTooSmall = "memory limit is smaller than a page"
+	ErrPageSizeIsBad         = "page size is not positive"
+	ErrMinSlotSizeIsBad      = "minimal slot size is not positive or exceeds the page size"
+	ErrGrowthFactorIsBad     = "growth factor is not greater than one"
+	ErrMemoryIsExhausted     = "memory is exhausted for a record of %d bytes"
+)
@@ -0,0 +1,9 @@
+package tiered
+
+import (
+	cache "github.com/vault-thirteen/Cache"
+)
+
+// Cache is a set of methods which a cache of a tier must have. Both vl.Cache
+// and nvl.Cache implement it.
+type Cache[U comparable, D any] = cache.Cache[U, D]
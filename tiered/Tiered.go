@@ -0,0 +1,151 @@
+package tiered
+
+import (
+	"errors"
+	"sync"
+)
+
+// Tiered is a composition of a small L1 cache in front of a larger L2 cache.
+// Records missing in L1 are read through from L2 and are added to L1. Added
+// records are propagated according to the write policy, and removed records
+// are removed from both tiers. Several compositions may share the same L2
+// cache, but removal of a record in one composition does not affect L1
+// caches of the others.
+type Tiered[U comparable, D any] struct {
+	l1          Cache[U, D]
+	l2          Cache[U, D]
+	writePolicy WritePolicy
+
+	// lock keeps L1 coherent with L2: reads may fill L1 concurrently, while
+	// writes and removals exclude them, so that a read can not put into L1 a
+	// record which is being removed.
+	lock *sync.RWMutex
+}
+
+// New creates a composition of the L1 and L2 caches.
+func New[U comparable, D any](l1 Cache[U, D], l2 Cache[U, D], writePolicy WritePolicy) (t *Tiered[U, D], err error) {
+	if l1 == nil {
+		return nil, errors.New(ErrL1IsNil)
+	}
+	if l2 == nil {
+		return nil, errors.New(ErrL2IsNil)
+	}
+	if !writePolicy.isValid() {
+		return nil, errors.New(ErrWritePolicyIsUnknown)
+	}
+
+	t = &Tiered[U, D]{
+		l1:          l1,
+		l2:          l2,
+		writePolicy: writePolicy,
+		lock:        new(sync.RWMutex),
+	}
+
+	return t, nil
+}
+
+// L1 returns the L1 cache.
+func (t *Tiered[U, D]) L1() (l1 Cache[U, D]) {
+	return t.l1
+}
+
+// L2 returns the L2 cache.
+func (t *Tiered[U, D]) L2() (l2 Cache[U, D]) {
+	return t.l2
+}
+
+// RecordExists checks whether the record exists in any tier.
+func (t *Tiered[U, D]) RecordExists(uid U) (recordExists bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.l1.RecordExists(uid) || t.l2.RecordExists(uid)
+}
+
+// AddRecord adds a record according to the write policy. When the record is
+// written to L2, an error of L2 is returned and L1 is not changed. A record
+// which is rejected by L1, e.g. for being too big, is removed from L1 and
+// is not reported as an error, as it is stored in L2. When the record is
+// written to L1 only, its copy is removed from L2 even if L1 rejects it.
+func (t *Tiered[U, D]) AddRecord(uid U, data D) (err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.writePolicy == WritePolicyLocal {
+		t.l2.RemoveRecord(uid)
+		return t.l1.AddRecord(uid, data)
+	}
+
+	err = t.l2.AddRecord(uid, data)
+	if err != nil {
+		return err
+	}
+
+	if t.writePolicy == WritePolicyAround {
+		t.l1.RemoveRecord(uid)
+		return nil
+	}
+
+	err = t.l1.AddRecord(uid, data)
+	if err != nil {
+		t.l1.RemoveRecord(uid)
+	}
+
+	return nil
+}
+
+// GetRecord reads a record from L1. If the record is missing in L1, it is
+// read from L2 and is added to L1. An error of L2 is returned when the record
+// is missing in both tiers.
+func (t *Tiered[U, D]) GetRecord(uid U) (data D, err error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	data, err = t.l1.GetRecord(uid)
+	if err == nil {
+		return data, nil
+	}
+
+	data, err = t.l2.GetRecord(uid)
+	if err != nil {
+		return data, err
+	}
+
+	// L1 may reject the record, it is still returned.
+	_ = t.l1.AddRecord(uid, data)
+
+	return data, nil
+}
+
+// RemoveRecord removes a record from both tiers.
+func (t *Tiered[U, D]) RemoveRecord(uid U) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.l2.RemoveRecord(uid)
+	t.l1.RemoveRecord(uid)
+}
+
+// RemoveExistingRecord removes a record from both tiers. An error is
+// returned when the record has not been removed from any tier, the error of
+// L2 is preferred.
+func (t *Tiered[U, D]) RemoveExistingRecord(uid U) (err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	l2Err := t.l2.RemoveExistingRecord(uid)
+	l1Err := t.l1.RemoveExistingRecord(uid)
+	if (l1Err != nil) && (l2Err != nil) {
+		return l2Err
+	}
+
+	return nil
+}
+
+// Clear removes all records from both tiers.
+func (t *Tiered[U, D]) Clear() (err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return errors.Join(t.l2.Clear(), t.l1.Clear())
+}
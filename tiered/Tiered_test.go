@@ -0,0 +1,150 @@
+package tiered
+
+import (
+	"testing"
+
+	nvl "github.com/vault-thirteen/Cache/NVL"
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+var (
+	_ Cache[string, string] = (*vl.Cache[string, string])(nil)
+	_ Cache[string, string] = (*nvl.Cache[string, string])(nil)
+)
+
+func _test_prepare(t *testing.T, writePolicy WritePolicy) (tc *Tiered[string, string]) {
+	var err error
+	tc, err = New[string, string](
+		vl.NewCache[string, string](2, 5, 60),
+		nvl.NewCache[string, string](0, 60),
+		writePolicy,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tc
+}
+
+func Test_New(t *testing.T) {
+	aTest := tester.New(t)
+	var l1 = vl.NewCache[string, string](0, 0, 60)
+	var err error
+
+	// Test #1. Bad arguments.
+	_, err = New[string, string](nil, l1, WritePolicyThrough)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrL1IsNil)
+	_, err = New[string, string](l1, nil, WritePolicyThrough)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrL2IsNil)
+	_, err = New[string, string](l1, l1, WritePolicyLocal+1)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrWritePolicyIsUnknown)
+
+	// Test #2. OK.
+	tc, err := New[string, string](l1, vl.NewCache[string, string](0, 0, 60), WritePolicyAround)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(tc.L1(), Cache[string, string](l1))
+}
+
+func Test_AddRecord(t *testing.T) {
+	aTest := tester.New(t)
+	var tc *Tiered[string, string]
+
+	// Test #1. Write-through.
+	tc = _test_prepare(t, WritePolicyThrough)
+	aTest.MustBeNoError(tc.AddRecord("A", "1"))
+	aTest.MustBeEqual(tc.L1().RecordExists("A"), true)
+	aTest.MustBeEqual(tc.L2().RecordExists("A"), true)
+
+	// Test #2. Record rejected by L1 is stored in L2 only.
+	aTest.MustBeNoError(tc.AddRecord("A", "123456"))
+	aTest.MustBeEqual(tc.L1().RecordExists("A"), false)
+	data, err := tc.L2().GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "123456")
+
+	// Test #3. Write-around.
+	tc = _test_prepare(t, WritePolicyAround)
+	aTest.MustBeNoError(tc.L1().AddRecord("A", "0"))
+	aTest.MustBeNoError(tc.AddRecord("A", "1"))
+	aTest.MustBeEqual(tc.L1().RecordExists("A"), false)
+	aTest.MustBeEqual(tc.L2().RecordExists("A"), true)
+
+	// Test #4. Local write.
+	tc = _test_prepare(t, WritePolicyLocal)
+	aTest.MustBeNoError(tc.AddRecord("A", "1"))
+	aTest.MustBeEqual(tc.L1().RecordExists("A"), true)
+	aTest.MustBeEqual(tc.L2().RecordExists("A"), false)
+	err = tc.AddRecord("B", "123456")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), vl.ErrRecordIsTooBig)
+
+	// Test #5. Local write removes an outdated copy from L2.
+	aTest.MustBeNoError(tc.L2().AddRecord("C", "old"))
+	aTest.MustBeNoError(tc.AddRecord("C", "new"))
+	aTest.MustBeEqual(tc.L2().RecordExists("C"), false)
+	tc.L1().RemoveRecord("C")
+	_, err = tc.GetRecord("C")
+	aTest.MustBeAnError(err)
+}
+
+func Test_GetRecord(t *testing.T) {
+	aTest := tester.New(t)
+	var tc = _test_prepare(t, WritePolicyAround)
+	var data string
+	var err error
+
+	// Test #1. Read-through.
+	aTest.MustBeNoError(tc.AddRecord("A", "1"))
+	aTest.MustBeEqual(tc.L1().RecordExists("A"), false)
+	data, err = tc.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "1")
+	aTest.MustBeEqual(tc.L1().RecordExists("A"), true)
+	aTest.MustBeEqual(tc.RecordExists("A"), true)
+
+	// Test #2. Record rejected by L1 is returned.
+	aTest.MustBeNoError(tc.AddRecord("B", "123456"))
+	data, err = tc.GetRecord("B")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "123456")
+	aTest.MustBeEqual(tc.L1().RecordExists("B"), false)
+
+	// Test #3. Absent record.
+	_, err = tc.GetRecord("Q")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "record is not found, uid=Q")
+	aTest.MustBeEqual(tc.RecordExists("Q"), false)
+}
+
+func Test_Remove(t *testing.T) {
+	aTest := tester.New(t)
+	var tc = _test_prepare(t, WritePolicyThrough)
+	var err error
+
+	aTest.MustBeNoError(tc.AddRecord("A", "1"))
+	aTest.MustBeNoError(tc.AddRecord("B", "2"))
+	aTest.MustBeNoError(tc.L1().AddRecord("C", "3"))
+
+	// Test #1. RemoveRecord.
+	tc.RemoveRecord("A")
+	aTest.MustBeEqual(tc.L1().RecordExists("A"), false)
+	aTest.MustBeEqual(tc.L2().RecordExists("A"), false)
+
+	// Test #2. RemoveExistingRecord.
+	aTest.MustBeNoError(tc.RemoveExistingRecord("B"))
+	aTest.MustBeEqual(tc.RecordExists("B"), false)
+	aTest.MustBeNoError(tc.RemoveExistingRecord("C"))
+	err = tc.RemoveExistingRecord("C")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), "record is not found, uid=C")
+
+	// Test #3. Clear.
+	aTest.MustBeNoError(tc.AddRecord("D", "4"))
+	aTest.MustBeNoError(tc.Clear())
+	aTest.MustBeEqual(tc.L1().RecordExists("D"), false)
+	aTest.MustBeEqual(tc.L2().RecordExists("D"), false)
+}
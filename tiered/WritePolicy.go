@@ -0,0 +1,22 @@
+package tiered
+
+// WritePolicy is a policy of propagation of added records through tiers.
+type WritePolicy byte
+
+const (
+	// WritePolicyThrough writes a record to L2 and then to L1.
+	WritePolicyThrough WritePolicy = iota
+
+	// WritePolicyAround writes a record to L2 and removes its copy from L1,
+	// so that L1 is filled by reads only.
+	WritePolicyAround
+
+	// WritePolicyLocal writes a record to L1 only and removes its copy from
+	// L2, so that an outdated copy is not read back after L1 evicts the
+	// record.
+	WritePolicyLocal
+)
+
+func (wp WritePolicy) isValid() bool {
+	return wp <= WritePolicyLocal
+}
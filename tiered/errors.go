@@ -0,0 +1,7 @@
+package tiered
+
+const (
+	ErrL1IsNil              = "L1 cache is nil"
+	ErrL2IsNil              = "L2 cache is nil"
+	ErrWritePolicyIsUnknown = "write policy is unknown"
+)
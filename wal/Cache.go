@@ -0,0 +1,370 @@
+// Package wal makes contents of a cache durable. Each change of the cache,
+// i.e. adding, updating and removal of a record and clearing of the cache, is
+// appended to a write-ahead log as a checksummed entry before it is applied.
+// The log is compacted into a snapshot of the cache from time to time. When
+// the cache is opened, the snapshot and the log are replayed, so that the
+// cache has the contents it had before a crash or a restart.
+package wal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/internal/uidcodec"
+)
+
+// Cache is a cache with a write-ahead log. Records keep the times of their
+// last changes when they are replayed, so a replayed record expires when it
+// would have expired without a restart. Reading a record prolongs its life
+// only until the next restart, as reading is not logged. Evictions are not
+// logged, the limits of the cache evict records again when they are replayed.
+type Cache[U vl.UidType, D vl.DataType] struct {
+	memory   *vl.Cache[U, D]
+	settings Settings
+	clock    *replayClock
+
+	// lock orders changes of the cache and entries of the log.
+	lock     *sync.Mutex
+	log      *logFile
+	isClosed bool
+
+	// compactionLock prevents simultaneous compactions.
+	compactionLock *sync.Mutex
+
+	stopCh chan struct{}
+	wg     *sync.WaitGroup
+}
+
+// Open creates a cache using its configuration and restores its contents
+// from the directory of the settings. The clock of the configuration is
+// wrapped by the cache.
+func Open[U vl.UidType, D vl.DataType](memory vl.Config[U, D], settings Settings) (c *Cache[U, D], err error) {
+	err = settings.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(settings.Dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Cache[U, D]{
+		settings:       settings,
+		clock:          &replayClock{base: memory.Clock},
+		lock:           new(sync.Mutex),
+		compactionLock: new(sync.Mutex),
+		stopCh:         make(chan struct{}),
+		wg:             new(sync.WaitGroup),
+	}
+
+	memory.Clock = c.clock
+	c.memory, err = vl.NewFromConfig(memory)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.recover()
+	if err != nil {
+		_ = c.memory.Close()
+		return nil, err
+	}
+
+	if settings.CompactionInterval > 0 {
+		c.wg.Add(1)
+		go c.compactPeriodically()
+	}
+
+	return c, nil
+}
+
+// recover replays the latest snapshot and the logs which follow it, then it
+// opens the latest log for appending.
+func (c *Cache[U, D]) recover() (err error) {
+	dir := c.settings.Dir
+
+	tmpPaths, _ := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	for _, path := range tmpPaths {
+		_ = os.Remove(path)
+	}
+
+	var snapshots, logs []uint64
+	snapshots, err = listFiles(dir, SnapshotFileExt)
+	if err != nil {
+		return err
+	}
+	logs, err = listFiles(dir, LogFileExt)
+	if err != nil {
+		return err
+	}
+
+	var generation uint64 = 1
+	if len(snapshots) > 0 {
+		generation = snapshots[len(snapshots)-1]
+
+		var correctSize, fileSize int64
+		correctSize, fileSize, err = scanFile(filepath.Join(dir, fileName(generation, SnapshotFileExt)), c.replay)
+		if err != nil {
+			return err
+		}
+		if correctSize != fileSize {
+			return fmt.Errorf(ErrSnapshotIsCorrupted, fileName(generation, SnapshotFileExt))
+		}
+	}
+
+	var logSize int64
+	for _, g := range logs {
+		if g < generation {
+			continue
+		}
+
+		logSize, _, err = scanFile(filepath.Join(dir, fileName(g, LogFileExt)), c.replay)
+		if err != nil {
+			return err
+		}
+		generation = g
+	}
+	c.clock.setReplayTime(nil)
+
+	err = removeFiles(dir, generation)
+	if err != nil {
+		return err
+	}
+
+	c.log, err = openLogFile(dir, generation, logSize, c.settings.SyncWrites)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// replay applies an entry at the time of the entry. Records which have
+// expired since then are not added.
+func (c *Cache[U, D]) replay(e entry) (err error) {
+	t := time.Unix(e.time, 0)
+	c.clock.setReplayTime(&t)
+
+	switch e.kind {
+	case entryKindPut:
+		var uid U
+		uid, err = uidcodec.DecodeText[U](e.uid)
+		if err != nil {
+			return err
+		}
+
+		expiresAt := t.Add(time.Duration(c.memory.GetTtl()) * time.Second)
+		if !c.clock.baseNow().Before(expiresAt) {
+			c.memory.RemoveRecord(uid)
+			return nil
+		}
+
+		// Records which do not fit the limits are not added, as they have
+		// not been added before.
+		_ = c.memory.AddRecord(uid, D(e.data))
+
+	case entryKindRemove:
+		var uid U
+		uid, err = uidcodec.DecodeText[U](e.uid)
+		if err != nil {
+			return err
+		}
+		c.memory.RemoveRecord(uid)
+
+	case entryKindClear:
+		return c.memory.Clear()
+
+	default:
+		return fmt.Errorf(ErrEntryKindIsUnknown, e.kind)
+	}
+
+	return nil
+}
+
+// append writes an entry of a change to the log.
+func (c *Cache[U, D]) append(kind byte, uid []byte, data []byte) (err error) {
+	if c.isClosed {
+		return errors.New(ErrCacheIsClosed)
+	}
+
+	return c.log.append(entry{
+		kind: kind,
+		time: c.clock.Now().Unix(),
+		uid:  uid,
+		data: data,
+	})
+}
+
+func (c *Cache[U, D]) reportError(err error) {
+	if c.settings.OnError != nil {
+		c.settings.OnError(err)
+	}
+}
+
+// Memory returns the cache. Changes made directly in the cache are not
+// logged.
+func (c *Cache[U, D]) Memory() (memory *vl.Cache[U, D]) {
+	return c.memory
+}
+
+// RecordExists checks whether the specified record exists or not.
+func (c *Cache[U, D]) RecordExists(uid U) (recordExists bool) {
+	return c.memory.RecordExists(uid)
+}
+
+// AddRecord logs and adds a new record or updates an existing one.
+func (c *Cache[U, D]) AddRecord(uid U, data D) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	err = c.append(entryKindPut, uidcodec.EncodeText(uid), []byte(data))
+	if err != nil {
+		return fmt.Errorf(ErrLoggingIsFailed, err)
+	}
+
+	return c.memory.AddRecord(uid, data)
+}
+
+// GetRecord reads a record.
+func (c *Cache[U, D]) GetRecord(uid U) (data D, err error) {
+	return c.memory.GetRecord(uid)
+}
+
+// RemoveRecord logs and removes a record if it exists. Errors of logging are
+// reported to the OnError callback.
+func (c *Cache[U, D]) RemoveRecord(uid U) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	err := c.append(entryKindRemove, uidcodec.EncodeText(uid), nil)
+	if err != nil {
+		c.reportError(fmt.Errorf(ErrLoggingIsFailed, err))
+	}
+
+	c.memory.RemoveRecord(uid)
+}
+
+// RemoveExistingRecord logs and removes an existing record, it returns an
+// error if the record is not found.
+func (c *Cache[U, D]) RemoveExistingRecord(uid U) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.memory.RecordExists(uid) {
+		return fmt.Errorf(ErrRecordIsNotFound, uid)
+	}
+
+	err = c.append(entryKindRemove, uidcodec.EncodeText(uid), nil)
+	if err != nil {
+		return fmt.Errorf(ErrLoggingIsFailed, err)
+	}
+
+	return c.memory.RemoveExistingRecord(uid)
+}
+
+// Clear logs clearing and removes all records.
+func (c *Cache[U, D]) Clear() (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	err = c.append(entryKindClear, nil, nil)
+	if err != nil {
+		return fmt.Errorf(ErrLoggingIsFailed, err)
+	}
+
+	return c.memory.Clear()
+}
+
+// Compact writes a snapshot of the cache and removes the log preceding it.
+// Changes made during compaction are written to a new log.
+func (c *Cache[U, D]) Compact() (err error) {
+	c.compactionLock.Lock()
+	defer c.compactionLock.Unlock()
+
+	c.lock.Lock()
+	if c.isClosed {
+		c.lock.Unlock()
+		return errors.New(ErrCacheIsClosed)
+	}
+
+	generation := c.log.generation + 1
+	var log *logFile
+	log, err = openLogFile(c.settings.Dir, generation, 0, c.settings.SyncWrites)
+	if err != nil {
+		c.lock.Unlock()
+		return err
+	}
+
+	oldLog := c.log
+	c.log = log
+
+	var entries []entry
+	c.memory.WalkRecords(func(uid U, data D, lastAccessTime time.Time) bool {
+		entries = append(entries, entry{
+			kind: entryKindPut,
+			time: lastAccessTime.Unix(),
+			uid:  uidcodec.EncodeText(uid),
+			data: []byte(data),
+		})
+		return true
+	})
+	c.lock.Unlock()
+
+	err = oldLog.close()
+	if err != nil {
+		return err
+	}
+
+	// When the snapshot is not written, the previous snapshot and all the
+	// logs following it remain.
+	err = writeSnapshot(c.settings.Dir, generation, entries)
+	if err != nil {
+		return err
+	}
+
+	return removeFiles(c.settings.Dir, generation)
+}
+
+func (c *Cache[U, D]) compactPeriodically() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.settings.CompactionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			err := c.Compact()
+			if err != nil {
+				c.reportError(fmt.Errorf(ErrCompactionIsFailed, err))
+			}
+		}
+	}
+}
+
+// Close stops background compaction and closes the log and the cache. The
+// log is not compacted.
+func (c *Cache[U, D]) Close() (err error) {
+	c.lock.Lock()
+	if c.isClosed {
+		c.lock.Unlock()
+		return nil
+	}
+	c.isClosed = true
+	c.lock.Unlock()
+
+	close(c.stopCh)
+	c.wg.Wait()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return errors.Join(c.log.close(), c.memory.Close())
+}
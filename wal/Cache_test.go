@@ -0,0 +1,237 @@
+package wal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_Open(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	// Test #1. Bad settings.
+	cfg := vl.Config[string, string]{RecordTtl: 60}
+	_, err = Open(cfg, Settings{})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrDirIsNotSet)
+	_, err = Open(cfg, Settings{Dir: t.TempDir(), CompactionInterval: -1})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrCompactionIntervalIsBad)
+
+	// Test #2. Bad configuration of the cache.
+	_, err = Open(vl.Config[string, string]{}, Settings{Dir: t.TempDir()})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), vl.ErrTtlIsZero)
+
+	// Test #3. Corrupted snapshot.
+	dir := t.TempDir()
+	aTest.MustBeNoError(os.WriteFile(filepath.Join(dir, fileName(2, SnapshotFileExt)), []byte("junk"), 0o644))
+	_, err = Open(cfg, Settings{Dir: dir})
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrSnapshotIsCorrupted, fileName(2, SnapshotFileExt)))
+}
+
+func Test_Cache_replay(t *testing.T) {
+	aTest := tester.New(t)
+	var data string
+	var err error
+
+	dir := t.TempDir()
+	clock := _test_clock()
+	c := _test_open(t, dir, clock)
+
+	// Test #1. Changes are replayed.
+	for _, uid := range []string{"A", "B", "C"} {
+		aTest.MustBeNoError(c.AddRecord(uid, uid))
+	}
+	aTest.MustBeNoError(c.AddRecord("A", "AA"))
+	c.RemoveRecord("B")
+	err = c.RemoveExistingRecord("B")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrRecordIsNotFound, "B"))
+	aTest.MustBeNoError(c.RemoveExistingRecord("C"))
+	aTest.MustBeNoError(c.AddRecord("D", "D"))
+	aTest.MustBeNoError(c.Close())
+
+	c = _test_open(t, dir, clock)
+	aTest.MustBeEqual(c.Memory().PeekTopUids(3), []string{"D", "A"})
+	data, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, "AA")
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+	aTest.MustBeEqual(c.RecordExists("C"), false)
+
+	// Test #2. Clearing is replayed.
+	aTest.MustBeNoError(c.Clear())
+	aTest.MustBeNoError(c.AddRecord("E", "E"))
+	aTest.MustBeNoError(c.Close())
+
+	c = _test_open(t, dir, clock)
+	size, _ := c.Memory().GetSize()
+	aTest.MustBeEqual(size, 1)
+	aTest.MustBeEqual(c.RecordExists("E"), true)
+
+	// Test #3. Closed cache.
+	aTest.MustBeNoError(c.Close())
+	err = c.AddRecord("F", "F")
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(errors.Unwrap(err).Error(), ErrCacheIsClosed)
+	err = c.Compact()
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrCacheIsClosed)
+}
+
+func Test_Cache_ttl(t *testing.T) {
+	aTest := tester.New(t)
+
+	dir := t.TempDir()
+	clock := _test_clock()
+	c := _test_open(t, dir, clock)
+
+	aTest.MustBeNoError(c.AddRecord("A", "A"))
+	clock.Add(time.Second * 30)
+	aTest.MustBeNoError(c.AddRecord("B", "B"))
+	aTest.MustBeNoError(c.Close())
+
+	// Test #1. Records expire relative to the times of their changes.
+	clock.Add(time.Second * 40)
+	c = _test_open(t, dir, clock)
+	aTest.MustBeEqual(c.RecordExists("A"), false)
+	aTest.MustBeEqual(c.RecordExists("B"), true)
+
+	// Test #2. A replayed record is not prolonged by the restart.
+	clock.Add(time.Second * 20)
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+}
+
+func Test_Cache_Compact(t *testing.T) {
+	aTest := tester.New(t)
+	var generations []uint64
+	var err error
+
+	dir := t.TempDir()
+	clock := _test_clock()
+	c := _test_open(t, dir, clock)
+
+	for _, uid := range []string{"A", "B", "C"} {
+		clock.Add(time.Second)
+		aTest.MustBeNoError(c.AddRecord(uid, uid))
+	}
+	c.RemoveRecord("B")
+	_, err = c.GetRecord("A")
+	aTest.MustBeNoError(err)
+
+	// Test #1. The log is replaced with a snapshot.
+	aTest.MustBeNoError(c.Compact())
+	generations, err = listFiles(dir, SnapshotFileExt)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(generations, []uint64{2})
+	generations, err = listFiles(dir, LogFileExt)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(generations, []uint64{2})
+
+	// Test #2. The snapshot and the following log are replayed, the order of
+	// records is kept.
+	aTest.MustBeNoError(c.AddRecord("D", "D"))
+	aTest.MustBeNoError(c.Close())
+	c = _test_open(t, dir, clock)
+	aTest.MustBeEqual(c.Memory().PeekTopUids(5), []string{"D", "A", "C"})
+
+	// Test #3. Logs older than the snapshot are ignored and removed.
+	lf, err := openLogFile(dir, 1, 0, false)
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(lf.append(entry{kind: entryKindPut, time: clock.Now().Unix(), uid: []byte("X"), data: []byte("X")}))
+	aTest.MustBeNoError(lf.close())
+	aTest.MustBeNoError(c.Close())
+	c = _test_open(t, dir, clock)
+	aTest.MustBeEqual(c.RecordExists("X"), false)
+	generations, err = listFiles(dir, LogFileExt)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(generations, []uint64{2})
+}
+
+func Test_Cache_tornTail(t *testing.T) {
+	aTest := tester.New(t)
+
+	dir := t.TempDir()
+	clock := _test_clock()
+	c := _test_open(t, dir, clock)
+	aTest.MustBeNoError(c.AddRecord("A", "A"))
+	aTest.MustBeNoError(c.Close())
+
+	// Test #1. A partially written entry is cut off.
+	path := filepath.Join(dir, fileName(1, LogFileExt))
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	aTest.MustBeNoError(err)
+	_, err = file.Write(entry{kind: entryKindPut, uid: []byte("B"), data: []byte("B")}.encode()[:15])
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(file.Close())
+
+	c = _test_open(t, dir, clock)
+	aTest.MustBeEqual(c.RecordExists("A"), true)
+	aTest.MustBeEqual(c.RecordExists("B"), false)
+
+	// Test #2. Following entries are readable.
+	aTest.MustBeNoError(c.AddRecord("C", "C"))
+	aTest.MustBeNoError(c.Close())
+	c = _test_open(t, dir, clock)
+	aTest.MustBeEqual(c.RecordExists("A"), true)
+	aTest.MustBeEqual(c.RecordExists("C"), true)
+}
+
+func Test_Cache_compactPeriodically(t *testing.T) {
+	aTest := tester.New(t)
+	var errs []error
+	var errsLock sync.Mutex
+
+	dir := t.TempDir()
+	c, err := Open(vl.Config[int, []byte]{RecordTtl: 60}, Settings{
+		Dir:                dir,
+		SyncWrites:         true,
+		CompactionInterval: time.Millisecond * 10,
+		OnError: func(err error) {
+			errsLock.Lock()
+			defer errsLock.Unlock()
+			errs = append(errs, err)
+		},
+	})
+	aTest.MustBeNoError(err)
+
+	// Test #1. The log is compacted in background.
+	aTest.MustBeNoError(c.AddRecord(1, []byte("1")))
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		generations, err := listFiles(dir, SnapshotFileExt)
+		aTest.MustBeNoError(err)
+		if len(generations) > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("log is not compacted")
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+	aTest.MustBeNoError(c.AddRecord(2, []byte("2")))
+	aTest.MustBeNoError(c.Close())
+
+	errsLock.Lock()
+	aTest.MustBeEqual(len(errs), 0)
+	errsLock.Unlock()
+
+	// Test #2. Integer UIDs are replayed.
+	c, err = Open(vl.Config[int, []byte]{RecordTtl: 60}, Settings{Dir: dir})
+	aTest.MustBeNoError(err)
+	data, err := c.GetRecord(1)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(data, []byte("1"))
+	aTest.MustBeEqual(c.RecordExists(2), true)
+	aTest.MustBeNoError(c.Close())
+}
@@ -0,0 +1,93 @@
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+const entryHeaderLen = 21
+
+// Kinds of entries.
+const (
+	entryKindPut    byte = 1
+	entryKindRemove byte = 2
+	entryKindClear  byte = 3
+)
+
+// entry is a frame of a log or snapshot file. The layout of an entry is
+// following: a CRC-32 checksum of the rest of the entry (4 bytes), kind (1
+// byte), time of the change in Unix seconds (8 bytes), length of the UID (4
+// bytes), length of the data (4 bytes), the UID and the data. Integers are
+// big-endian.
+type entry struct {
+	kind byte
+	time int64
+	uid  []byte
+	data []byte
+}
+
+func (e entry) size() int {
+	return entryHeaderLen + len(e.uid) + len(e.data)
+}
+
+func (e entry) encode() (buf []byte) {
+	buf = make([]byte, e.size())
+	buf[4] = e.kind
+	binary.BigEndian.PutUint64(buf[5:13], uint64(e.time))
+	binary.BigEndian.PutUint32(buf[13:17], uint32(len(e.uid)))
+	binary.BigEndian.PutUint32(buf[17:21], uint32(len(e.data)))
+	copy(buf[entryHeaderLen:], e.uid)
+	copy(buf[entryHeaderLen+len(e.uid):], e.data)
+	binary.BigEndian.PutUint32(buf[0:4], crc32.ChecksumIEEE(buf[4:]))
+
+	return buf
+}
+
+// readEntry reads the next entry. The limit is the number of bytes left in
+// the file, an entry crossing the limit is treated as corrupted, e.g. as an
+// entry which has been written partially. The end of the file is reported
+// with the io.EOF error.
+func readEntry(r *bufio.Reader, limit int64) (e entry, size int64, err error) {
+	if limit == 0 {
+		return e, 0, io.EOF
+	}
+	if limit < entryHeaderLen {
+		return e, 0, errors.New(ErrEntryIsCorrupted)
+	}
+
+	header := make([]byte, entryHeaderLen)
+	_, err = io.ReadFull(r, header)
+	if err != nil {
+		return e, 0, err
+	}
+
+	uidLen := int64(binary.BigEndian.Uint32(header[13:17]))
+	dataLen := int64(binary.BigEndian.Uint32(header[17:21]))
+	size = entryHeaderLen + uidLen + dataLen
+	if size > limit {
+		return e, 0, errors.New(ErrEntryIsCorrupted)
+	}
+
+	buf := make([]byte, size)
+	copy(buf, header)
+	_, err = io.ReadFull(r, buf[entryHeaderLen:])
+	if err != nil {
+		return e, 0, err
+	}
+
+	if binary.BigEndian.Uint32(buf[0:4]) != crc32.ChecksumIEEE(buf[4:]) {
+		return e, 0, errors.New(ErrEntryIsCorrupted)
+	}
+
+	e = entry{
+		kind: buf[4],
+		time: int64(binary.BigEndian.Uint64(buf[5:13])),
+		uid:  buf[entryHeaderLen : entryHeaderLen+uidLen],
+		data: buf[entryHeaderLen+uidLen:],
+	}
+
+	return e, size, nil
+}
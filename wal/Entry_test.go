@@ -0,0 +1,48 @@
+package wal
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_entry(t *testing.T) {
+	aTest := tester.New(t)
+	var e = entry{kind: entryKindPut, time: 123, uid: []byte("uid"), data: []byte("data")}
+	var err error
+
+	// Test #1. Encoding and decoding.
+	buf := e.encode()
+	aTest.MustBeEqual(len(buf), e.size())
+	aTest.MustBeEqual(e.size(), entryHeaderLen+7)
+	stream := append(append([]byte{}, buf...), buf...)
+	r := bufio.NewReader(bytes.NewReader(stream))
+	var e2 entry
+	var size int64
+	e2, size, err = readEntry(r, int64(len(stream)))
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(size, int64(e.size()))
+	aTest.MustBeEqual(e2, e)
+	e2, _, err = readEntry(r, int64(len(stream))-size)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(e2, e)
+	_, _, err = readEntry(r, 0)
+	aTest.MustBeEqual(err, io.EOF)
+
+	// Test #2. Truncated entry.
+	_, _, err = readEntry(bufio.NewReader(bytes.NewReader(buf)), int64(len(buf)-1))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrEntryIsCorrupted)
+	_, _, err = readEntry(bufio.NewReader(bytes.NewReader(buf)), 10)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrEntryIsCorrupted)
+
+	// Test #3. Damaged entry.
+	buf[len(buf)-1] ^= 0xFF
+	_, _, err = readEntry(bufio.NewReader(bytes.NewReader(buf)), int64(len(buf)))
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), ErrEntryIsCorrupted)
+}
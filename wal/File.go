@@ -0,0 +1,242 @@
+package wal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Extensions of files.
+const (
+	LogFileExt      = ".wal"
+	SnapshotFileExt = ".snapshot"
+)
+
+// Files of the log are numbered by generations. A snapshot of a generation
+// contains the state of the cache before the first entry of the log of the
+// same generation. Each compaction starts a new generation.
+func fileName(generation uint64, ext string) string {
+	return fmt.Sprintf("%020d%s", generation, ext)
+}
+
+func parseFileName(name string, ext string) (generation uint64, err error) {
+	generation, err = strconv.ParseUint(strings.TrimSuffix(name, ext), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf(ErrFileNameIsBad, name)
+	}
+
+	return generation, nil
+}
+
+// listFiles returns generations of files having the extension in ascending
+// order.
+func listFiles(dir string, ext string) (generations []uint64, err error) {
+	var dirEntries []os.DirEntry
+	dirEntries, err = os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var generation uint64
+	for _, de := range dirEntries {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), ext) {
+			continue
+		}
+
+		generation, err = parseFileName(de.Name(), ext)
+		if err != nil {
+			return nil, err
+		}
+		generations = append(generations, generation)
+	}
+	slices.Sort(generations)
+
+	return generations, nil
+}
+
+// scanFile reads all the entries of the file in order. It returns the size
+// of the correct part of the file, which is less than the size of the file
+// when the file has a corrupted tail.
+func scanFile(path string, fn func(e entry) (err error)) (correctSize int64, fileSize int64, err error) {
+	var file *os.File
+	file, err = os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var fi os.FileInfo
+	fi, err = file.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	fileSize = fi.Size()
+
+	r := bufio.NewReader(file)
+	var e entry
+	var size int64
+	for {
+		e, size, err = readEntry(r, fileSize-correctSize)
+		if err != nil {
+			break
+		}
+
+		err = fn(e)
+		if err != nil {
+			return correctSize, fileSize, err
+		}
+		correctSize += size
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || (err.Error() == ErrEntryIsCorrupted) {
+		return correctSize, fileSize, nil
+	}
+
+	return correctSize, fileSize, err
+}
+
+// logFile is an append-only file of entries.
+type logFile struct {
+	generation uint64
+	file       *os.File
+	size       int64
+	syncWrites bool
+}
+
+// openLogFile opens a log file, creating it if it does not exist. The file is
+// truncated to the size, so that a corrupted tail is cut off.
+func openLogFile(dir string, generation uint64, size int64, syncWrites bool) (lf *logFile, err error) {
+	var file *os.File
+	file, err = os.OpenFile(filepath.Join(dir, fileName(generation, LogFileExt)), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	err = file.Truncate(size)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	lf = &logFile{
+		generation: generation,
+		file:       file,
+		size:       size,
+		syncWrites: syncWrites,
+	}
+
+	return lf, nil
+}
+
+// append writes the entry to the end of the file. A partially written entry
+// is cut off, so that following entries are readable.
+func (lf *logFile) append(e entry) (err error) {
+	_, err = lf.file.WriteAt(e.encode(), lf.size)
+	if err == nil && lf.syncWrites {
+		err = lf.file.Sync()
+	}
+	if err != nil {
+		_ = lf.file.Truncate(lf.size)
+		return err
+	}
+
+	lf.size += int64(e.size())
+
+	return nil
+}
+
+func (lf *logFile) close() (err error) {
+	return lf.file.Close()
+}
+
+// writeSnapshot writes the entries to a snapshot file. The file is written
+// under a temporary name and is renamed when it is complete, so that a
+// snapshot is either absent or complete.
+func writeSnapshot(dir string, generation uint64, entries []entry) (err error) {
+	path := filepath.Join(dir, fileName(generation, SnapshotFileExt))
+	tmpPath := path + ".tmp"
+
+	var file *os.File
+	file, err = os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = file.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	w := bufio.NewWriter(file)
+	for _, e := range entries {
+		_, err = w.Write(e.encode())
+		if err != nil {
+			return err
+		}
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	err = file.Sync()
+	if err != nil {
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+// syncDir makes changes of names of files of the directory durable.
+func syncDir(dir string) (err error) {
+	var d *os.File
+	d, err = os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = d.Close() }()
+
+	// Some systems do not support syncing of directories.
+	_ = d.Sync()
+
+	return nil
+}
+
+// removeFiles removes log and snapshot files of generations older than the
+// generation.
+func removeFiles(dir string, generation uint64) (err error) {
+	var errs []error
+	for _, ext := range []string{LogFileExt, SnapshotFileExt} {
+		var generations []uint64
+		generations, err = listFiles(dir, ext)
+		if err != nil {
+			return err
+		}
+
+		for _, g := range generations {
+			if g < generation {
+				errs = append(errs, os.Remove(filepath.Join(dir, fileName(g, ext))))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,100 @@
+package wal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vault-thirteen/auxie/tester"
+)
+
+func Test_fileName(t *testing.T) {
+	aTest := tester.New(t)
+
+	// Test #1. Names.
+	aTest.MustBeEqual(fileName(12, LogFileExt), "00000000000000000012.wal")
+	generation, err := parseFileName(fileName(12, SnapshotFileExt), SnapshotFileExt)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(generation, uint64(12))
+
+	// Test #2. Bad name.
+	_, err = parseFileName("x.wal", LogFileExt)
+	aTest.MustBeAnError(err)
+	aTest.MustBeEqual(err.Error(), fmt.Sprintf(ErrFileNameIsBad, "x.wal"))
+}
+
+func Test_logFile(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+	var entries []entry
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, fileName(1, LogFileExt))
+	collect := func(e entry) error {
+		entries = append(entries, e)
+		return nil
+	}
+
+	// Test #1. Appending and scanning.
+	lf, err := openLogFile(dir, 1, 0, true)
+	aTest.MustBeNoError(err)
+	e := entry{kind: entryKindRemove, time: 1, uid: []byte("a"), data: []byte{}}
+	aTest.MustBeNoError(lf.append(e))
+	aTest.MustBeNoError(lf.append(entry{kind: entryKindClear, time: 2, uid: []byte{}, data: []byte{}}))
+	aTest.MustBeNoError(lf.close())
+
+	correctSize, fileSize, err := scanFile(path, collect)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(correctSize, fileSize)
+	aTest.MustBeEqual(len(entries), 2)
+	aTest.MustBeEqual(entries[0], e)
+
+	// Test #2. A corrupted tail is skipped and cut off on opening.
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	aTest.MustBeNoError(err)
+	_, err = file.Write(e.encode()[:10])
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(file.Close())
+
+	entries = nil
+	correctSize, fileSize, err = scanFile(path, collect)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(fileSize-correctSize, int64(10))
+	aTest.MustBeEqual(len(entries), 2)
+
+	lf, err = openLogFile(dir, 1, correctSize, false)
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(lf.append(e))
+	aTest.MustBeNoError(lf.close())
+	entries = nil
+	_, _, err = scanFile(path, collect)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(len(entries), 3)
+}
+
+func Test_writeSnapshot(t *testing.T) {
+	aTest := tester.New(t)
+	var err error
+
+	dir := t.TempDir()
+
+	// Test #1. Snapshots are listed by generations.
+	aTest.MustBeNoError(writeSnapshot(dir, 3, []entry{{kind: entryKindPut, time: 1, uid: []byte("a"), data: []byte("1")}}))
+	aTest.MustBeNoError(writeSnapshot(dir, 2, nil))
+	generations, err := listFiles(dir, SnapshotFileExt)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(generations, []uint64{2, 3})
+
+	// Test #2. Older files are removed.
+	lf, err := openLogFile(dir, 2, 0, false)
+	aTest.MustBeNoError(err)
+	aTest.MustBeNoError(lf.close())
+	aTest.MustBeNoError(removeFiles(dir, 3))
+	generations, err = listFiles(dir, SnapshotFileExt)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(generations, []uint64{3})
+	generations, err = listFiles(dir, LogFileExt)
+	aTest.MustBeNoError(err)
+	aTest.MustBeEqual(len(generations), 0)
+}
@@ -0,0 +1,41 @@
+package wal
+
+import (
+	"sync/atomic"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+)
+
+// replayClock is the clock of the cache. While entries are replayed, it shows
+// the time of the current entry, so that replayed records have the times of
+// their original changes and expire when they would have expired without a
+// restart. Otherwise, it shows the time of the base clock.
+type replayClock struct {
+	base       vl.Clock
+	replayTime atomic.Pointer[time.Time]
+}
+
+// Now returns the current time of the clock.
+func (rc *replayClock) Now() time.Time {
+	t := rc.replayTime.Load()
+	if t != nil {
+		return *t
+	}
+
+	return rc.baseNow()
+}
+
+func (rc *replayClock) baseNow() time.Time {
+	if rc.base == nil {
+		return time.Now()
+	}
+
+	return rc.base.Now()
+}
+
+// setReplayTime starts showing the time. A nil time returns the clock to the
+// base clock.
+func (rc *replayClock) setReplayTime(t *time.Time) {
+	rc.replayTime.Store(t)
+}
@@ -0,0 +1,40 @@
+package wal
+
+import (
+	"errors"
+	"time"
+)
+
+// Settings contains settings of the log.
+type Settings struct {
+	// Dir is a directory of log and snapshot files. It is created if it does
+	// not exist.
+	Dir string
+
+	// SyncWrites makes each entry be synced to the disk before a method
+	// returns. Without it, entries survive a crash of the process, but may
+	// be lost on a crash of the system.
+	SyncWrites bool
+
+	// CompactionInterval is an optional interval of background compaction
+	// of the log into a snapshot. Zero disables background compaction, the
+	// log is compacted only by the Compact method.
+	CompactionInterval time.Duration
+
+	// OnError is an optional callback which receives errors which can not be
+	// returned to a caller, i.e. errors of background compaction and errors
+	// of the RemoveRecord method.
+	OnError func(err error)
+}
+
+func (s Settings) validate() (err error) {
+	if len(s.Dir) == 0 {
+		return errors.New(ErrDirIsNotSet)
+	}
+
+	if s.CompactionInterval < 0 {
+		return errors.New(ErrCompactionIntervalIsBad)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package wal
+
+import (
+	"testing"
+	"time"
+
+	vl "github.com/vault-thirteen/Cache/VL"
+	"github.com/vault-thirteen/Cache/cachetest"
+)
+
+// _test_open opens a cache of the directory with the TTL of 60 seconds. The
+// cache is closed at the end of the test.
+func _test_open(t *testing.T, dir string, clock *cachetest.Clock) (c *Cache[string, string]) {
+	c, err := Open(
+		vl.Config[string, string]{SizeLimit: 10, RecordTtl: 60, Clock: clock},
+		Settings{Dir: dir},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c
+}
+
+func _test_clock() (clock *cachetest.Clock) {
+	return cachetest.NewClock(time.Unix(1_700_000_000, 0))
+}
@@ -0,0 +1,14 @@
+package wal
+
+const (
+	ErrDirIsNotSet             = "directory is not set"
+	ErrCompactionIntervalIsBad = "compaction interval is negative"
+	ErrEntryIsCorrupted        = "entry is corrupted"
+	ErrEntryKindIsUnknown      = "entry kind is unknown: %v"
+	ErrFileNameIsBad           = "file name is bad: %v"
+	ErrCacheIsClosed           = "cache is closed"
+	ErrLoggingIsFailed         = "logging is failed: %w"
+	ErrCompactionIsFailed      = "compaction is failed: %w"
+	ErrRecordIsNotFound        = "record is not found, uid=%v"
+	ErrSnapshotIsCorrupted     = "snapshot is corrupted: %v"
+)